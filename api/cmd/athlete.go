@@ -0,0 +1,49 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const athleteTimeout = 10 * time.Minute
+
+// athleteCmd represents the athlete command
+var athleteCmd = &cobra.Command{
+	Use:   "athlete",
+	Short: "Manage athlete identities",
+	Long: `Links race results to athletes and fixes athlete identities by hand when
+the automatic matching by name, unit, gender and age group gets them wrong.`,
+}
+
+// findAthleteArg 以 athlete ID 找出選手，已合併的選手會回傳錯誤
+func findAthleteArg(ctx context.Context, store mongo.AthleteStore, arg string) (*models.Athlete, error) {
+	id, err := bson.ObjectIDFromHex(arg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid athlete id %s: %w", arg, err)
+	}
+	athlete, err := store.FindAthlete(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if athlete == nil {
+		return nil, fmt.Errorf("athlete %s not found", arg)
+	}
+	if !athlete.MergedInto.IsZero() {
+		return nil, fmt.Errorf("athlete %s was merged into %s", arg, athlete.MergedInto.Hex())
+	}
+	return athlete, nil
+}
+
+func init() {
+	rootCmd.AddCommand(athleteCmd)
+}
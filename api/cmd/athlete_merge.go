@@ -0,0 +1,78 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"aquascore/api/internal/crawler/persistence"
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// minMergeArgs 是合併需要的參數數量：目標選手與至少一位來源選手
+const minMergeArgs = 2
+
+// athleteMergeCmd represents the athlete merge command
+var athleteMergeCmd = &cobra.Command{
+	Use:   "merge <target-id> <source-id>...",
+	Short: "Merge athletes that are the same swimmer",
+	Long: `Merges the source athletes into the target athlete. The target takes over
+their name variants and units, and their results are linked to the target. Later
+crawls match those names to the target.`,
+	Args: cobra.MinimumNArgs(minMergeArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		closeDB, err := connectMongo()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		var athleteStore mongo.AthleteStore
+		var athleteBestStore mongo.AthleteBestStore
+		mongo.InjectStore(func(s *mongo.Stores) {
+			athleteStore = s.AthleteStore
+			athleteBestStore = s.AthleteBestStore
+		})
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), athleteTimeout)
+		defer cancel()
+		target, err := findAthleteArg(ctx, athleteStore, args[0])
+		if err != nil {
+			return err
+		}
+		sources := make([]*models.Athlete, 0, len(args)-1)
+		for _, arg := range args[1:] {
+			source, err := findAthleteArg(ctx, athleteStore, arg)
+			if err != nil {
+				return err
+			}
+			if source.ID == target.ID {
+				return fmt.Errorf("cannot merge athlete %s into itself", arg)
+			}
+			sources = append(sources, source)
+		}
+		updated, err := athleteStore.MergeAthletes(ctx, target, sources)
+		if err != nil {
+			return err
+		}
+		ids := []bson.ObjectID{target.ID}
+		for _, source := range sources {
+			ids = append(ids, source.ID)
+		}
+		if err := persistence.UpdateAthleteBests(ctx, athleteBestStore, ids); err != nil {
+			return err
+		}
+		fmt.Printf("✅ 已合併到 %s (%s)，更新 %d 筆成績\n", target.Name, target.ID.Hex(), updated)
+		return nil
+	},
+}
+
+func init() {
+	athleteCmd.AddCommand(athleteMergeCmd)
+}
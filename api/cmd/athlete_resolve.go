@@ -0,0 +1,97 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"aquascore/api/internal/athlete"
+	"aquascore/api/internal/crawler/persistence"
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/spf13/cobra"
+)
+
+// athleteResolveCmd represents the athlete resolve command
+var athleteResolveCmd = &cobra.Command{
+	Use:   "resolve",
+	Short: "Link race results to athletes",
+	Long: `Links race results that have no athlete yet (for example results crawled
+before athletes existed) to athletes, creating athletes as needed. Races are
+processed in date order so an athlete's age group and unit history builds up
+the same way it does while crawling.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		year, err := cmd.Flags().GetString("year")
+		if err != nil {
+			return fmt.Errorf("get year fail: %w", err)
+		}
+		relink, err := cmd.Flags().GetBool("relink")
+		if err != nil {
+			return fmt.Errorf("get relink fail: %w", err)
+		}
+
+		closeDB, err := connectMongo()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		var store *mongo.Stores
+		mongo.InjectStore(func(s *mongo.Stores) {
+			store = s
+		})
+		resolver := athlete.NewResolver(store.AthleteStore)
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), athleteTimeout)
+		defer cancel()
+		years := []string{year}
+		if year == "" {
+			years, err = store.RaceStore.GetYears(ctx)
+			if err != nil {
+				return fmt.Errorf("get years fail: %w", err)
+			}
+			slices.Sort(years)
+		}
+
+		var linked int
+		for _, y := range years {
+			races, err := store.RaceStore.FindRacesWithResults(ctx, mongo.NewRaceQueryByYear(y))
+			if err != nil {
+				return fmt.Errorf("find races of %s fail: %w", y, err)
+			}
+			slices.SortStableFunc(races, func(a, b *models.AggrRaceWithResult) int {
+				return a.Time.Compare(b.Time)
+			})
+			for _, race := range races {
+				for _, result := range race.Results {
+					if !relink && len(result.AthleteIDs) == len(result.Name) {
+						continue
+					}
+					ids, err := persistence.ResolveResultAthletes(ctx, resolver,
+						race.Year, race.Gender, race.AgeGroup, result.Unit, result.Name)
+					if err != nil {
+						return err
+					}
+					if err := store.AthleteStore.LinkResultAthletes(ctx, result.ID, ids); err != nil {
+						return err
+					}
+					linked++
+				}
+			}
+			fmt.Printf("%s 年: %d 個項目\n", y, len(races))
+		}
+		fmt.Printf("✅ 連結 %d 筆成績\n", linked)
+		return nil
+	},
+}
+
+func init() {
+	athleteCmd.AddCommand(athleteResolveCmd)
+
+	athleteResolveCmd.Flags().String("year", "", "only link results of this year")
+	athleteResolveCmd.Flags().Bool("relink", false, "also re-link results that are already linked")
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"aquascore/api/internal/crawler/persistence"
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// athleteSplitCmd represents the athlete split command
+var athleteSplitCmd = &cobra.Command{
+	Use:   "split <athlete-id>",
+	Short: "Split the results of some units into a new athlete",
+	Long: `Moves the results an athlete swam for the given units to a new athlete with
+the same name, for two swimmers that were matched as one. Later crawls match
+results of those units to the new athlete.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		units, err := cmd.Flags().GetStringSlice("unit")
+		if err != nil {
+			return fmt.Errorf("get unit fail: %w", err)
+		}
+		if len(units) == 0 {
+			return errors.New("at least one --unit is required")
+		}
+
+		closeDB, err := connectMongo()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		var athleteStore mongo.AthleteStore
+		var athleteBestStore mongo.AthleteBestStore
+		mongo.InjectStore(func(s *mongo.Stores) {
+			athleteStore = s.AthleteStore
+			athleteBestStore = s.AthleteBestStore
+		})
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), athleteTimeout)
+		defer cancel()
+		original, err := findAthleteArg(ctx, athleteStore, args[0])
+		if err != nil {
+			return err
+		}
+		for _, unit := range units {
+			if !slices.Contains(original.Units, unit) {
+				return fmt.Errorf("athlete %s has no results for %s (units: %s)",
+					original.Name, unit, strings.Join(original.Units, ", "))
+			}
+		}
+		newAthlete := models.NewAthlete()
+		newAthlete.Name = original.Name
+		newAthlete.Names = []string{original.Name}
+		newAthlete.Gender = original.Gender
+		updated, err := athleteStore.SplitAthlete(ctx, original, newAthlete, units)
+		if err != nil {
+			return err
+		}
+		ids := []bson.ObjectID{original.ID, newAthlete.ID}
+		if err := persistence.UpdateAthleteBests(ctx, athleteBestStore, ids); err != nil {
+			return err
+		}
+		fmt.Printf("✅ 新選手 %s (%s)，移動 %d 筆成績\n", newAthlete.Name, newAthlete.ID.Hex(), updated)
+		return nil
+	},
+}
+
+func init() {
+	athleteCmd.AddCommand(athleteSplitCmd)
+
+	athleteSplitCmd.Flags().StringSlice("unit", nil, "unit whose results belong to the new athlete (repeatable)")
+}
@@ -0,0 +1,24 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const backfillTimeout = 10 * time.Minute
+
+// backfillCmd represents the backfill command
+var backfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "Populate fields added after races were crawled",
+	Long: `Backfills fields on existing race documents that older crawler versions did
+not set, without crawling or re-parsing score reports.`,
+}
+
+func init() {
+	rootCmd.AddCommand(backfillCmd)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/spf13/cobra"
+)
+
+// backfillCompetitionCmd represents the backfill competition command
+var backfillCompetitionCmd = &cobra.Command{
+	Use:   "competition",
+	Short: "Create competitions for existing races and link the races to them",
+	Long: `Groups existing races by year and competition name, creates the missing
+competition documents with the date range, organizer and pool course found on
+the races, and links every race to its competition. Races crawled before the
+source activity ID was recorded get a competition without source_id.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		year, err := cmd.Flags().GetString("year")
+		if err != nil {
+			return fmt.Errorf("get year fail: %w", err)
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return fmt.Errorf("get dry-run fail: %w", err)
+		}
+
+		closeDB, err := connectMongo()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		var competitionStore mongo.CompetitionStore
+		mongo.InjectStore(func(s *mongo.Stores) {
+			competitionStore = s.CompetitionStore
+		})
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), backfillTimeout)
+		defer cancel()
+		raceCompetitions, err := competitionStore.GetRaceCompetitions(ctx, year)
+		if err != nil {
+			return fmt.Errorf("get race competitions fail: %w", err)
+		}
+
+		var updated int64
+		for _, rc := range raceCompetitions {
+			fmt.Printf("%s %s (%d 個項目, %s ~ %s)\n", rc.Year, rc.CompetitionName, rc.Races,
+				rc.StartDate.Format("2006-01-02"), rc.EndDate.Format("2006-01-02"))
+			if dryRun {
+				continue
+			}
+			id, err := competitionStore.UpsertCompetition(ctx, raceCompetitionToModelCompetition(rc))
+			if err != nil {
+				return fmt.Errorf("save %s fail: %w", rc.CompetitionName, err)
+			}
+			n, err := competitionStore.SetRacesCompetition(ctx, rc.Year, rc.CompetitionName, id)
+			if err != nil {
+				return fmt.Errorf("update %s fail: %w", rc.CompetitionName, err)
+			}
+			updated += n
+		}
+		if dryRun {
+			fmt.Printf("%d 場比賽\n", len(raceCompetitions))
+			return nil
+		}
+		fmt.Printf("✅ %d 場比賽，更新 %d 個項目\n", len(raceCompetitions), updated)
+		return nil
+	},
+}
+
+func raceCompetitionToModelCompetition(rc *models.AggrRaceCompetition) *models.Competition {
+	competition := models.NewCompetition()
+	competition.Source = rc.Source
+	competition.Name = rc.CompetitionName
+	competition.Year = rc.Year
+	competition.ADYear = crawler.ADYear(rc.Year)
+	competition.StartDate = rc.StartDate
+	competition.EndDate = rc.EndDate
+	competition.Organizer = rc.Organizer
+	competition.PoolType = rc.PoolType
+	return competition
+}
+
+func init() {
+	backfillCmd.AddCommand(backfillCompetitionCmd)
+
+	backfillCompetitionCmd.Flags().String("year", "", "only backfill races of this year")
+	backfillCompetitionCmd.Flags().Bool("dry-run", false, "print the competitions that would be created without updating")
+}
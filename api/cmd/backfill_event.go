@@ -0,0 +1,80 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/crawler/persistence"
+	"aquascore/api/internal/db/mongo"
+
+	"github.com/spf13/cobra"
+)
+
+// backfillEventCmd represents the backfill event command
+var backfillEventCmd = &cobra.Command{
+	Use:   "event",
+	Short: "Parse distance, stroke, relay and round of existing races",
+	Long: `Parses the event name of existing races into the structured event fields
+(distance, stroke, relay, relay_count, round) used to group events.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		year, err := cmd.Flags().GetString("year")
+		if err != nil {
+			return fmt.Errorf("get year fail: %w", err)
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return fmt.Errorf("get dry-run fail: %w", err)
+		}
+
+		closeDB, err := connectMongo()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		var raceStore mongo.RaceStore
+		mongo.InjectStore(func(s *mongo.Stores) {
+			raceStore = s.RaceStore
+		})
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), backfillTimeout)
+		defer cancel()
+		eventNames, err := raceStore.GetEventNames(ctx, year)
+		if err != nil {
+			return fmt.Errorf("get event names fail: %w", err)
+		}
+
+		var updated int64
+		var unparsed int
+		for _, eventName := range eventNames {
+			event := crawler.ParseEvent(eventName)
+			if event.Distance == 0 || event.Stroke == "" {
+				fmt.Printf("⚠️ 無法解析項目: %s\n", eventName)
+				unparsed++
+			}
+			if dryRun {
+				fmt.Printf("%s => %+v\n", eventName, event)
+				continue
+			}
+			n, err := raceStore.SetRacesEvent(ctx,
+				mongo.NewRaceQueryByEventName(year, eventName), persistence.EventToModelRaceEvent(event))
+			if err != nil {
+				return fmt.Errorf("update %s fail: %w", eventName, err)
+			}
+			updated += n
+		}
+		fmt.Printf("✅ 更新 %d 個項目，%d 個項目名稱無法解析\n", updated, unparsed)
+		return nil
+	},
+}
+
+func init() {
+	backfillCmd.AddCommand(backfillEventCmd)
+
+	backfillEventCmd.Flags().String("year", "", "only backfill races of this year")
+	backfillEventCmd.Flags().Bool("dry-run", false, "print the parsed events without updating")
+}
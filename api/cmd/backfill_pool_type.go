@@ -0,0 +1,98 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"aquascore/api/internal/db/mongo"
+
+	"github.com/spf13/cobra"
+)
+
+// backfillPoolTypeCmd represents the backfill pool-type command
+var backfillPoolTypeCmd = &cobra.Command{
+	Use:   "pool-type",
+	Short: "Set the pool course (25m/50m) of existing races",
+	Long: `Determines the pool course of every competition from its name and the
+crawler.pool_type settings, then sets pool_type on its races. Races that
+already have a pool type are kept unless --overwrite is given.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		year, err := cmd.Flags().GetString("year")
+		if err != nil {
+			return fmt.Errorf("get year fail: %w", err)
+		}
+		overwrite, err := cmd.Flags().GetBool("overwrite")
+		if err != nil {
+			return fmt.Errorf("get overwrite fail: %w", err)
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return fmt.Errorf("get dry-run fail: %w", err)
+		}
+		poolTypes, err := crawlerPoolTypes()
+		if err != nil {
+			return err
+		}
+
+		closeDB, err := connectMongo()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		var raceStore mongo.RaceStore
+		mongo.InjectStore(func(s *mongo.Stores) {
+			raceStore = s.RaceStore
+		})
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), backfillTimeout)
+		defer cancel()
+		years := []string{year}
+		if year == "" {
+			years, err = raceStore.GetYears(ctx)
+			if err != nil {
+				return fmt.Errorf("get years fail: %w", err)
+			}
+		}
+
+		var updated int64
+		var unknown int
+		for _, y := range years {
+			competitions, err := raceStore.GetCompetitions(ctx, y, "")
+			if err != nil {
+				return fmt.Errorf("get competitions of %s fail: %w", y, err)
+			}
+			for _, competition := range competitions {
+				poolType := poolTypes.Resolve(competition)
+				if poolType == "" {
+					fmt.Printf("⚠️ 無法判斷水道: %s\n", competition)
+					unknown++
+					continue
+				}
+				fmt.Printf("%s => %s\n", competition, poolType)
+				if dryRun {
+					continue
+				}
+				n, err := raceStore.SetRacesPoolType(ctx,
+					mongo.NewRaceQueryByCompetition(y, competition), poolType, overwrite)
+				if err != nil {
+					return fmt.Errorf("update %s fail: %w", competition, err)
+				}
+				updated += n
+			}
+		}
+		fmt.Printf("✅ 更新 %d 個項目，%d 場比賽無法判斷水道 (可在 crawler.pool_type 設定)\n", updated, unknown)
+		return nil
+	},
+}
+
+func init() {
+	backfillCmd.AddCommand(backfillPoolTypeCmd)
+
+	backfillPoolTypeCmd.Flags().String("year", "", "only backfill races of this year")
+	backfillPoolTypeCmd.Flags().Bool("overwrite", false, "also replace pool types that are already set")
+	backfillPoolTypeCmd.Flags().Bool("dry-run", false, "print the resolved pool types without updating")
+}
@@ -0,0 +1,62 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"aquascore/api/internal/db/mongo"
+
+	"github.com/spf13/cobra"
+)
+
+// backfillRaceKeyCmd represents the backfill race-key command
+var backfillRaceKeyCmd = &cobra.Command{
+	Use:   "race-key",
+	Short: "Recompute the natural key of existing races",
+	Long: `Recomputes the key races are upserted by (source, year, competition, event name
+and parsed round). Run it after the key changes so that re-crawling or re-parsing
+overwrites existing races instead of adding new ones. Races that end up with the
+same key are listed and keep their old key; delete the duplicates by hand.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return fmt.Errorf("get dry-run fail: %w", err)
+		}
+
+		closeDB, err := connectMongo()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		var raceStore mongo.RaceStore
+		mongo.InjectStore(func(s *mongo.Stores) {
+			raceStore = s.RaceStore
+		})
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), backfillTimeout)
+		defer cancel()
+		updated, duplicates, err := raceStore.UpdateRaceKeys(ctx, dryRun)
+		if err != nil {
+			return fmt.Errorf("update race keys fail: %w", err)
+		}
+		for _, ids := range duplicates {
+			fmt.Printf("⚠️ 重複的項目: %s 保留 key，重複的 %v\n", ids[0].Hex(), ids[1:])
+		}
+		if dryRun {
+			fmt.Printf("✅ %d 個項目的 key 需要更新，%d 組重複的項目\n", updated, len(duplicates))
+			return nil
+		}
+		fmt.Printf("✅ 更新 %d 個項目的 key，%d 組重複的項目\n", updated, len(duplicates))
+		return nil
+	},
+}
+
+func init() {
+	backfillCmd.AddCommand(backfillRaceKeyCmd)
+
+	backfillRaceKeyCmd.Flags().Bool("dry-run", false, "count the races whose key would change without updating")
+}
@@ -0,0 +1,71 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/db/mongo"
+
+	"github.com/spf13/cobra"
+)
+
+// backfillStatusCmd represents the backfill status command
+var backfillStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Parse the result status (DQ/DNS/DNF/...) of existing results",
+	Long: `Parses the note of existing results without a status into the structured
+result status (ok, dq, dns, dnf, scratch, exhibition) and the DQ reason.
+Results without a time and without a status keyword are marked as dns.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return fmt.Errorf("get dry-run fail: %w", err)
+		}
+
+		closeDB, err := connectMongo()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		var raceStore mongo.RaceStore
+		mongo.InjectStore(func(s *mongo.Stores) {
+			raceStore = s.RaceStore
+		})
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), backfillTimeout)
+		defer cancel()
+		notes, err := raceStore.GetUnsetStatusNotes(ctx)
+		if err != nil {
+			return fmt.Errorf("get result notes fail: %w", err)
+		}
+
+		var updated int64
+		for _, note := range notes {
+			for _, hasTime := range []bool{true, false} {
+				status, reason := crawler.ParseResultStatus(note, hasTime)
+				if dryRun {
+					fmt.Printf("%q (有成績: %t) => %s %s\n", note, hasTime, status, reason)
+					continue
+				}
+				n, err := raceStore.SetResultsStatus(ctx, note, hasTime, string(status), reason)
+				if err != nil {
+					return fmt.Errorf("update %q fail: %w", note, err)
+				}
+				updated += n
+			}
+		}
+		fmt.Printf("✅ %d 種備註，更新 %d 筆成績\n", len(notes), updated)
+		return nil
+	},
+}
+
+func init() {
+	backfillCmd.AddCommand(backfillStatusCmd)
+
+	backfillStatusCmd.Flags().Bool("dry-run", false, "print the parsed statuses without updating")
+}
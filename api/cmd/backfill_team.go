@@ -0,0 +1,105 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/team"
+
+	"github.com/spf13/cobra"
+)
+
+// backfillTeamCmd represents the backfill team command
+var backfillTeamCmd = &cobra.Command{
+	Use:   "team",
+	Short: "Link the units of existing results to teams",
+	Long: `Normalizes every unit name found on race results with the team.aliases
+settings, creates the missing teams and links the results to them. Run it again
+after adding aliases to move results to the right team.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return fmt.Errorf("get dry-run fail: %w", err)
+		}
+
+		closeDB, err := connectMongo()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		var teamStore mongo.TeamStore
+		mongo.InjectStore(func(s *mongo.Stores) {
+			teamStore = s.TeamStore
+		})
+		normalizer, err := teamNormalizer(teamStore)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), backfillTimeout)
+		defer cancel()
+		units, err := teamStore.GetUnits(ctx)
+		if err != nil {
+			return fmt.Errorf("get units fail: %w", err)
+		}
+
+		if dryRun {
+			printUnitGroups(normalizer, units)
+			return nil
+		}
+
+		var updated int64
+		for _, unit := range units {
+			t, err := normalizer.Resolve(ctx, unit)
+			if err != nil {
+				return err
+			}
+			if t == nil {
+				continue
+			}
+			if t.Name != unit {
+				fmt.Printf("%s => %s\n", unit, t.Name)
+			}
+			n, err := teamStore.SetResultsTeam(ctx, unit, t.ID)
+			if err != nil {
+				return fmt.Errorf("update %s fail: %w", unit, err)
+			}
+			updated += n
+		}
+		fmt.Printf("✅ %d 個單位名稱，更新 %d 筆成績\n", len(units), updated)
+		return nil
+	},
+}
+
+// printUnitGroups 列出會被視為同一個隊伍的單位名稱
+func printUnitGroups(normalizer *team.Normalizer, units []string) {
+	groups := make(map[string][]string)
+	var keys []string
+	for _, unit := range units {
+		key := team.NormalizeKey(normalizer.CanonicalName(unit))
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], unit)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if len(groups[key]) > 1 {
+			fmt.Printf("%s: %s\n", key, strings.Join(groups[key], " / "))
+		}
+	}
+	fmt.Printf("%d 個單位名稱，對應 %d 個隊伍\n", len(units), len(keys))
+}
+
+func init() {
+	backfillCmd.AddCommand(backfillTeamCmd)
+
+	backfillTeamCmd.Flags().Bool("dry-run", false, "print the unit names that would be grouped into one team without updating")
+}
@@ -6,11 +6,16 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"aquascore/api/internal/athlete"
 	"aquascore/api/internal/crawler"
 	"aquascore/api/internal/crawler/persistence"
 	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/team"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -29,50 +34,77 @@ to quickly create a Cobra application.`,
 	RunE: func(cmd *cobra.Command, _ []string) error {
 		year, err := cmd.Flags().GetString("year")
 		if err != nil {
-			return fmt.Errorf("get target url fail: %w", err)
+			return fmt.Errorf("get year fail: %w", err)
 		}
-		var targetURL string
-		if year == "" {
-			targetURL = "https://ctsa.utk.com.tw/CTSA/public/race/game_data.aspx"
-		} else {
-			targetURL = fmt.Sprintf("https://ctsa.utk.com.tw/CTSA_%s/public/race/game_data.aspx", year)
+		sourceName, err := cmd.Flags().GetString("source")
+		if err != nil {
+			return fmt.Errorf("get source fail: %w", err)
 		}
-
-		// db connection
-		dbCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
-		closeFunc, err := mongo.IniMongodb(
-			dbCtx,
-			viper.GetString("database.uri"),
-			viper.GetString("database.db"),
-		)
+		resumeJobID, err := cmd.Flags().GetString("resume")
 		if err != nil {
-			cancel()
-			return fmt.Errorf("init mongodb fail: %w", err)
+			return fmt.Errorf("get resume fail: %w", err)
+		}
+		yearRange, err := cmd.Flags().GetString("years")
+		if err != nil {
+			return fmt.Errorf("get years fail: %w", err)
+		}
+		allYears, err := cmd.Flags().GetBool("all-years")
+		if err != nil {
+			return fmt.Errorf("get all-years fail: %w", err)
+		}
+		concurrency, err := cmd.Flags().GetInt("concurrency")
+		if err != nil {
+			return fmt.Errorf("get concurrency fail: %w", err)
+		}
+		daemon, err := cmd.Flags().GetBool("daemon")
+		if err != nil {
+			return fmt.Errorf("get daemon fail: %w", err)
+		}
+		recheckDays, err := cmd.Flags().GetInt("recheck-days")
+		if err != nil {
+			return fmt.Errorf("get recheck-days fail: %w", err)
 		}
-		defer func() {
-			closeCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
-			defer cancel()
-			if err := closeFunc(closeCtx); err != nil {
-				fmt.Printf("close mongodb fail: %v\n", err)
-			}
-		}()
-		cancel()
-
-		var crawlerPersistence crawler.Persistence
-		mongo.InjectStore(func(s *mongo.Stores) {
-			crawlerPersistence = persistence.NewMongoPersistence(s.RaceStore, s.CrawlLogStore)
-		})
 
-		crawler, err := crawler.NewCtsaCrawler(
-			crawler.WithBaseURL(targetURL),
-			crawler.WithPersistence(crawlerPersistence))
+		closeDB, err := connectMongo()
 		if err != nil {
-			return fmt.Errorf("init ctsa crawler fail: %w", err)
+			return err
 		}
+		defer closeDB()
 
-		crawlCtx, crawlCancel := context.WithCancel(context.Background())
+		// 收到中斷訊號時取消爬取，已完成的進度會保存在工作中
+		crawlCtx, crawlCancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 		defer crawlCancel()
-		err = crawler.Crawl(crawlCtx)
+
+		if daemon {
+			s, err := newCrawlScheduler()
+			if err != nil {
+				return err
+			}
+			fmt.Println("✅ 爬蟲排程已啟動，按 Ctrl+C 結束")
+			return s.Run(crawlCtx)
+		}
+
+		opts, err := crawlerOptions()
+		if err != nil {
+			return err
+		}
+		if recheckDays > 0 {
+			opts = append(opts, crawlerRecheckOption(recheckDays))
+		}
+		switch {
+		case resumeJobID != "":
+			err = crawler.ResumeJob(crawlCtx, resumeJobID, opts...)
+		case yearRange != "" || allYears:
+			var years []string
+			years, err = crawlYears(crawlCtx, sourceName, yearRange)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("✅ 準備爬取 %d 個年度: %v\n", len(years), years)
+			err = crawler.CrawlYears(crawlCtx, sourceName, years, concurrency, opts...)
+		default:
+			err = crawler.CrawlYears(crawlCtx, sourceName, []string{year}, 1, opts...)
+		}
 		if err != nil {
 			return fmt.Errorf("crawler fail: %w", err)
 		}
@@ -81,6 +113,131 @@ to quickly create a Cobra application.`,
 	},
 }
 
+// crawlerOptions 組合爬蟲的連線、儲存與水道判斷設定
+func crawlerOptions() ([]crawler.Option, error) {
+	poolTypes, err := crawlerPoolTypes()
+	if err != nil {
+		return nil, err
+	}
+	storeOpts, err := crawlerStoreOptions()
+	if err != nil {
+		return nil, err
+	}
+	opts := append(crawlerFetchOptions(), storeOpts...)
+	return append(opts, crawler.WithPoolTypes(poolTypes)), nil
+}
+
+// crawlerPoolTypes 讀取 crawler.pool_type.* 設定，決定無法由競賽名稱判斷水道時的規則與預設值
+func crawlerPoolTypes() (*crawler.PoolTypeResolver, error) {
+	var rules []crawler.PoolTypeRule
+	if err := viper.UnmarshalKey("crawler.pool_type.competitions", &rules); err != nil {
+		return nil, fmt.Errorf("read crawler.pool_type.competitions fail: %w", err)
+	}
+	resolver, err := crawler.NewPoolTypeResolver(rules, viper.GetString("crawler.pool_type.default"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid crawler.pool_type config: %w", err)
+	}
+	return resolver, nil
+}
+
+// crawlerStoreOptions 讓爬蟲使用 MongoDB 保存成績、原始成績報告與工作進度
+func crawlerStoreOptions() ([]crawler.Option, error) {
+	var store *mongo.Stores
+	mongo.InjectStore(func(s *mongo.Stores) {
+		store = s
+	})
+	crawlerPersistence, err := newMongoPersistence(store)
+	if err != nil {
+		return nil, err
+	}
+	return []crawler.Option{
+		crawler.WithPersistence(crawlerPersistence),
+		crawler.WithArchive(persistence.NewMongoArchive(store.RawPageStore)),
+		crawler.WithJobStore(persistence.NewMongoJobStore(store.CrawlJobStore)),
+	}, nil
+}
+
+// newMongoPersistence 建立保存成績的 Persistence，寫入時一併連結比賽、選手與隊伍
+func newMongoPersistence(store *mongo.Stores) (crawler.Persistence, error) {
+	normalizer, err := teamNormalizer(store.TeamStore)
+	if err != nil {
+		return nil, err
+	}
+	return persistence.NewMongoPersistence(store.RaceStore, store.CrawlLogStore, store.CompetitionStore,
+		store.RecordStore, store.AthleteBestStore, athlete.NewResolver(store.AthleteStore), normalizer), nil
+}
+
+// teamNormalizer 讀取 team.aliases 設定，建立將單位名稱對應到隊伍的 Normalizer
+func teamNormalizer(teamStore mongo.TeamStore) (*team.Normalizer, error) {
+	var aliases []team.Alias
+	if err := viper.UnmarshalKey("team.aliases", &aliases); err != nil {
+		return nil, fmt.Errorf("read team.aliases fail: %w", err)
+	}
+	normalizer, err := team.NewNormalizer(teamStore, aliases)
+	if err != nil {
+		return nil, fmt.Errorf("invalid team.aliases config: %w", err)
+	}
+	return normalizer, nil
+}
+
+// crawlerRecheckOption 重新檢查最近 days 天內爬取過的成績報告
+func crawlerRecheckOption(days int) crawler.Option {
+	const hoursPerDay = 24
+	return crawler.WithRecheck(time.Duration(days) * hoursPerDay * time.Hour)
+}
+
+// crawlYears 解析 --years，沒有指定時由來源探測所有有資料的年度
+func crawlYears(ctx context.Context, sourceName, yearRange string) ([]string, error) {
+	if yearRange != "" {
+		return crawler.ParseYears(yearRange)
+	}
+	source, err := crawler.NewSource(sourceName, crawlerFetchOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("init %s source fail: %w", sourceName, err)
+	}
+	discoverer, ok := source.(crawler.YearDiscoverer)
+	if !ok {
+		return nil, fmt.Errorf("source %s does not support --all-years", sourceName)
+	}
+	years, err := discoverer.DiscoverYears(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("discover years fail: %w", err)
+	}
+	if len(years) == 0 {
+		return nil, fmt.Errorf("no year found on source %s", sourceName)
+	}
+	return years, nil
+}
+
+// crawlerFetchOptions 讀取 crawler.retry.* 與 crawler.rate.* 設定，未設定的欄位沿用預設值
+func crawlerFetchOptions() []crawler.Option {
+	retry := crawler.DefaultRetryPolicy()
+	if viper.IsSet("crawler.retry.max_attempts") {
+		retry.MaxAttempts = viper.GetInt("crawler.retry.max_attempts")
+	}
+	if viper.IsSet("crawler.retry.timeout") {
+		retry.Timeout = viper.GetDuration("crawler.retry.timeout")
+	}
+	if viper.IsSet("crawler.retry.initial_backoff") {
+		retry.InitialBackoff = viper.GetDuration("crawler.retry.initial_backoff")
+	}
+	if viper.IsSet("crawler.retry.max_backoff") {
+		retry.MaxBackoff = viper.GetDuration("crawler.retry.max_backoff")
+	}
+	if viper.IsSet("crawler.retry.multiplier") {
+		retry.Multiplier = viper.GetFloat64("crawler.retry.multiplier")
+	}
+
+	rate := crawler.DefaultRateLimit()
+	if viper.IsSet("crawler.rate.requests_per_second") {
+		rate.RequestsPerSecond = viper.GetFloat64("crawler.rate.requests_per_second")
+	}
+	if viper.IsSet("crawler.rate.burst") {
+		rate.Burst = viper.GetInt("crawler.rate.burst")
+	}
+	return []crawler.Option{crawler.WithRetryPolicy(retry), crawler.WithRateLimit(rate)}
+}
+
 func init() {
 	rootCmd.AddCommand(crawlerCmd)
 
@@ -94,4 +251,14 @@ func init() {
 	// is called directly, e.g.:
 	// crawlerCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 	crawlerCmd.Flags().String("year", "", "year to crawl")
+	crawlerCmd.Flags().String("years", "", "years to crawl, e.g. 105-114 or 105,108,110-112")
+	crawlerCmd.Flags().Bool("all-years", false, "discover and crawl every year available on the source")
+	crawlerCmd.Flags().Int("concurrency", 2, "number of years to crawl at the same time")
+	crawlerCmd.Flags().String("resume", "", "resume a crawl job by ID, retrying only its failed items")
+	crawlerCmd.Flags().String("source", "ctsa",
+		fmt.Sprintf("results source to crawl, one of %v", crawler.SourceNames()))
+	crawlerCmd.Flags().Int("recheck-days", 0,
+		"re-fetch crawled score reports of races swum within the last N days and apply corrections")
+	crawlerCmd.Flags().Bool("daemon", false, "keep running and crawl on the schedule configured in scheduler.crawls")
+	crawlerCmd.MarkFlagsMutuallyExclusive("year", "years", "all-years", "resume", "daemon")
 }
@@ -0,0 +1,115 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/crawler/persistence"
+	"aquascore/api/internal/db/mongo"
+
+	"github.com/spf13/cobra"
+)
+
+const recentCrawlJobLimit = 10
+
+// crawlerStatusCmd represents the crawler status command
+var crawlerStatusCmd = &cobra.Command{
+	Use:   "status [job-id]",
+	Short: "Show the progress of crawl jobs",
+	Long: `Without arguments, lists the most recent crawl jobs. With a job ID, prints
+the progress of that job, every failed competition or race, and the result rows
+that were skipped because they could not be parsed.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		closeDB, err := connectMongo()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		var crawlJobStore mongo.CrawlJobStore
+		mongo.InjectStore(func(s *mongo.Stores) {
+			crawlJobStore = s.CrawlJobStore
+		})
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), time.Minute)
+		defer cancel()
+		if len(args) == 0 {
+			jobs, err := crawlJobStore.FindRecentCrawlJobs(ctx, recentCrawlJobLimit)
+			if err != nil {
+				return err
+			}
+			for _, job := range jobs {
+				printJobSummary(persistence.ModelCrawlJobToJob(job))
+			}
+			return nil
+		}
+
+		job, err := persistence.NewMongoJobStore(crawlJobStore).GetJob(args[0])
+		if err != nil {
+			return err
+		}
+		printJobSummary(job)
+		printJobFailures(job)
+		printJobUnparsed(job)
+		return nil
+	},
+}
+
+func printJobSummary(job *crawler.Job) {
+	progress := job.Progress()
+	racesTotal := 0
+	for _, count := range progress.Races {
+		racesTotal += count
+	}
+	fmt.Printf("%s  %-6s %-4s %-8s 開始 %s  嘗試 %d 次\n",
+		job.ID, job.Source, job.Year, job.Status, job.StartedAt.Local().Format(time.DateTime), job.Attempts)
+	fmt.Printf("    比賽: %d/%d 完成, %d 失敗   項目: %d/%d 完成, %d 已爬取, %d 失敗\n",
+		progress.Competitions[crawler.JobStatusDone], len(job.Competitions),
+		progress.Competitions[crawler.JobStatusFailed],
+		progress.Races[crawler.JobStatusDone], racesTotal,
+		progress.Races[crawler.JobStatusSkipped], progress.Races[crawler.JobStatusFailed])
+	if job.Error != "" {
+		fmt.Printf("    錯誤: %s\n", job.Error)
+	}
+}
+
+func printJobFailures(job *crawler.Job) {
+	for _, competition := range job.Competitions {
+		if competition.Status != crawler.JobStatusFailed {
+			continue
+		}
+		fmt.Printf("❌ %s (%s) 嘗試 %d 次 %s\n",
+			competition.Competition.Name, competition.Competition.ID, competition.Attempts, competition.Error)
+		for _, race := range competition.Races {
+			if race.Status != crawler.JobStatusFailed {
+				continue
+			}
+			fmt.Printf("    ❌ %s 嘗試 %d 次: %s\n", race.Race.RaceName, race.Attempts, race.Error)
+		}
+	}
+}
+
+// printJobUnparsed 列出解析成功但有略過無法解析的列的項目
+func printJobUnparsed(job *crawler.Job) {
+	for _, competition := range job.Competitions {
+		for _, race := range competition.Races {
+			if len(race.Unparsed) == 0 {
+				continue
+			}
+			fmt.Printf("⚠️ %s %s 略過 %d 列:\n", competition.Competition.Name, race.Race.RaceName, len(race.Unparsed))
+			for _, row := range race.Unparsed {
+				fmt.Printf("    %s\n", row)
+			}
+		}
+	}
+}
+
+func init() {
+	crawlerCmd.AddCommand(crawlerStatusCmd)
+}
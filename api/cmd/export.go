@@ -0,0 +1,19 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export results to files",
+	Long:  `Exports results stored in the database to files for other meet management software.`,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+}
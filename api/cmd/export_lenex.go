@@ -0,0 +1,138 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/crawler/persistence"
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/lenex"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	lenexConstructorName    = "aquascore"
+	lenexConstructorVersion = "1.0"
+)
+
+// exportLenexCmd represents the export lenex command
+var exportLenexCmd = &cobra.Command{
+	Use:   "lenex",
+	Short: "Export a competition as a Lenex (.lef/.lxf) results file",
+	Long: `Writes the races and results of a competition as a Lenex 3.0 results file.
+Each race becomes an event with a single age group; athletes' birth dates are
+estimated from their age groups. The file is zip compressed when --output ends
+with .lxf.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		competitionID, err := cmd.Flags().GetString("competition")
+		if err != nil {
+			return fmt.Errorf("get competition fail: %w", err)
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return fmt.Errorf("get output fail: %w", err)
+		}
+		nation, err := cmd.Flags().GetString("nation")
+		if err != nil {
+			return fmt.Errorf("get nation fail: %w", err)
+		}
+		email, err := cmd.Flags().GetString("contact-email")
+		if err != nil {
+			return fmt.Errorf("get contact-email fail: %w", err)
+		}
+		if competitionID == "" {
+			return errors.New("--competition is required")
+		}
+		id, err := bson.ObjectIDFromHex(competitionID)
+		if err != nil {
+			return fmt.Errorf("invalid competition id %q: %w", competitionID, err)
+		}
+
+		closeDB, err := connectMongo()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		var store *mongo.Stores
+		mongo.InjectStore(func(s *mongo.Stores) {
+			store = s
+		})
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), time.Minute)
+		defer cancel()
+		competition, err := store.CompetitionStore.FindCompetition(ctx, id)
+		if err != nil {
+			return fmt.Errorf("find competition fail: %w", err)
+		}
+		aggrRaces, err := store.RaceStore.FindRacesWithResults(ctx,
+			mongo.NewRaceQueryByCompetition(competition.Year, competition.Name))
+		if err != nil {
+			return fmt.Errorf("find races fail: %w", err)
+		}
+		races := make([]*crawler.Race, len(aggrRaces))
+		for i, aggr := range aggrRaces {
+			races[i] = persistence.AggrRaceToRace(aggr)
+		}
+
+		doc := lenex.Build(lenex.MeetInfo{
+			Name:      competition.Name,
+			City:      competition.Venue,
+			Nation:    nation,
+			Organizer: competition.Organizer,
+			PoolType:  competition.PoolType,
+		}, races, lenex.Constructor{
+			Name:    lenexConstructorName,
+			Version: lenexConstructorVersion,
+			Contact: lenex.Contact{Email: email},
+		})
+		if output == "" {
+			output = competition.Name + ".lef"
+		}
+		if err := writeLenex(output, doc); err != nil {
+			return err
+		}
+		fmt.Printf("✅ 匯出 %d 個項目到 %s\n", len(races), output)
+		return nil
+	},
+}
+
+// writeLenex 依副檔名寫出 .lef 或壓縮的 .lxf
+func writeLenex(output string, doc *lenex.Lenex) error {
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("create %s fail: %w", output, err)
+	}
+	defer file.Close()
+
+	ext := filepath.Ext(output)
+	if strings.EqualFold(ext, ".lxf") {
+		err = lenex.WriteCompressed(file, doc, strings.TrimSuffix(filepath.Base(output), ext)+".lef")
+	} else {
+		err = lenex.Write(file, doc)
+	}
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func init() {
+	exportCmd.AddCommand(exportLenexCmd)
+
+	exportLenexCmd.Flags().String("competition", "", "id of the competition to export")
+	exportLenexCmd.Flags().String("output", "", "output file, .lef or .lxf (default: <competition name>.lef)")
+	exportLenexCmd.Flags().String("nation", "TPE", "IOC nation code of the meet")
+	exportLenexCmd.Flags().String("contact-email", "", "contact email written to the file's constructor")
+}
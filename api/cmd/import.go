@@ -0,0 +1,20 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import results from files",
+	Long: `Imports results from files exported by other meet management software into
+the race/raceResult collections, the same way crawled score reports are stored.`,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,208 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/crawler/persistence"
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/sheet"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const sheetHashLength = 16
+
+// importCsvCmd represents the import csv command
+var importCsvCmd = &cobra.Command{
+	Use:   "csv <file>",
+	Short: "Import results from a CSV or Excel (.xlsx) spreadsheet",
+	Long: `Imports the results of a meet that is only published as a spreadsheet.
+A YAML mapping file (--mapping) names the competition and maps each result
+field to a column header, for example:
+
+  competition: 113年臺南市議長盃游泳錦標賽
+  pool_type: 50m
+  date: 2024-07-20
+  header_row: 1
+  columns:
+    age_group: 組別
+    gender: 性別
+    event: 項目
+    name: 姓名
+    unit: 單位
+    time: 成績
+    rank: 名次
+    note: 備註
+
+Rows that cannot be converted are skipped and reported with their row number.
+Races are identified by their natural key (source, year, competition, event
+and round), so importing the same spreadsheet again overwrites its races.
+--dry-run prints what would be imported without saving.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mappingFile, err := cmd.Flags().GetString("mapping")
+		if err != nil {
+			return fmt.Errorf("get mapping fail: %w", err)
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return fmt.Errorf("get dry-run fail: %w", err)
+		}
+		if mappingFile == "" {
+			return errors.New("--mapping is required")
+		}
+		mapping, err := readSheetMapping(mappingFile)
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("read %s fail: %w", args[0], err)
+		}
+		rows, err := sheet.Read(args[0], data, mapping.Sheet)
+		if err != nil {
+			return err
+		}
+		races, invalid, err := sheet.Races(rows, mapping)
+		if err != nil {
+			return err
+		}
+		for _, row := range invalid {
+			fmt.Printf("❌ %s\n", row)
+		}
+
+		poolTypes, err := crawlerPoolTypes()
+		if err != nil {
+			return err
+		}
+		closeDB, err := connectMongo()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		var store *mongo.Stores
+		mongo.InjectStore(func(s *mongo.Stores) {
+			store = s
+		})
+		crawlerPersistence, err := newMongoPersistence(store)
+		if err != nil {
+			return err
+		}
+
+		sum := sha256.Sum256(data)
+		importer := &sheetImporter{
+			raceStore:   store.RaceStore,
+			persistence: crawlerPersistence,
+			poolTypes:   poolTypes,
+			fileHash:    hex.EncodeToString(sum[:])[:sheetHashLength],
+			dryRun:      dryRun,
+		}
+		imported, failed, err := importer.importRaces(cmd.Context(), races)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ 匯入完成: %d 個項目, 失敗 %d, 無法轉換 %d 列\n", imported, failed, len(invalid))
+		if failed > 0 {
+			return fmt.Errorf("import csv fail: %d of %d races failed", failed, imported+failed)
+		}
+		return nil
+	},
+}
+
+type sheetImporter struct {
+	raceStore   mongo.RaceStore
+	persistence crawler.Persistence
+	poolTypes   *crawler.PoolTypeResolver
+	fileHash    string
+	dryRun      bool
+}
+
+// importRaces 寫入每一個項目，先前匯入過的項目會被覆寫，回傳寫入與失敗的數量
+func (s *sheetImporter) importRaces(ctx context.Context, races []*crawler.Race) (imported, failed int, err error) {
+	for i, race := range races {
+		if race.PoolType == "" {
+			race.PoolType = s.poolTypes.Resolve(race.CompetitionName)
+		}
+		existing, err := findExistingRace(ctx, s.raceStore, race)
+		if err != nil {
+			return imported, failed, err
+		}
+		if s.dryRun {
+			printSheetRace(race, existing)
+			imported++
+			continue
+		}
+		url := fmt.Sprintf("%s://%s/%d", sheet.SourceName, s.fileHash, i+1)
+		if err := s.persistence.PersistRace(url, race); err != nil {
+			log.Printf("❌ 儲存失敗 %s [%s]: %v", race.CompetitionName, race.EventName, err)
+			failed++
+			continue
+		}
+		imported++
+	}
+	return imported, failed, nil
+}
+
+// readSheetMapping 讀取試算表的欄位對應設定 (YAML)
+func readSheetMapping(file string) (sheet.Mapping, error) {
+	var mapping sheet.Mapping
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return mapping, fmt.Errorf("read mapping %s fail: %w", file, err)
+	}
+	if err := v.Unmarshal(&mapping); err != nil {
+		return mapping, fmt.Errorf("decode mapping %s fail: %w", file, err)
+	}
+	if err := mapping.Validate(); err != nil {
+		return mapping, fmt.Errorf("invalid mapping %s: %w", file, err)
+	}
+	return mapping, nil
+}
+
+// findExistingRace 以自然鍵找出資料庫中先前匯入的同一個項目，沒有時回傳 nil
+func findExistingRace(ctx context.Context, raceStore mongo.RaceStore, race *crawler.Race) (*crawler.Race, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	stored, err := raceStore.FindRaceWithResults(ctx, mongo.NewRaceQueryByKey(sheet.RaceKey(race)))
+	if err != nil {
+		return nil, fmt.Errorf("find existing race fail: %w", err)
+	}
+	if stored == nil {
+		return nil, nil
+	}
+	return persistence.AggrRaceToRace(stored), nil
+}
+
+func printSheetRace(race, existing *crawler.Race) {
+	if existing == nil {
+		fmt.Printf("➕ %s [%s] 新增 %d 筆成績\n", race.CompetitionName, race.EventName, len(race.Results))
+		return
+	}
+	changes := crawler.DiffRace(existing, race)
+	fmt.Printf("🔄 %s [%s] 覆寫先前匯入的成績，%d 處不同\n", race.CompetitionName, race.EventName, len(changes))
+	for _, change := range changes {
+		fmt.Printf("    %s %s: %s => %s\n", change.Athlete, change.Field, change.OldValue, change.NewValue)
+	}
+}
+
+func init() {
+	importCmd.AddCommand(importCsvCmd)
+
+	importCsvCmd.Flags().String("mapping", "", "YAML file mapping the spreadsheet columns to result fields")
+	importCsvCmd.Flags().Bool("dry-run", false, "print the validation errors and the races to import without saving")
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/db/mongo/models"
+	"aquascore/api/internal/sheet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRaceStore 以自然鍵保存 race，只實作匯入試算表用到的方法
+type memoryRaceStore struct {
+	mongo.RaceStore
+	races map[string]*models.AggrRaceWithResult
+}
+
+func (s *memoryRaceStore) FindRaceWithResults(
+	_ context.Context, q mongo.Query,
+) (*models.AggrRaceWithResult, error) {
+	key, _ := q.Query()["key"].(string)
+	return s.races[key], nil
+}
+
+// memoryPersistence 與 PersistRace 相同，以自然鍵覆寫 race
+type memoryPersistence struct {
+	store *memoryRaceStore
+}
+
+func (p *memoryPersistence) PersistRace(_ string, race *crawler.Race) error {
+	p.store.races[sheet.RaceKey(race)] = &models.AggrRaceWithResult{
+		Source:          race.Source,
+		Year:            race.Year,
+		Type:            race.Type,
+		CompetitionName: race.CompetitionName,
+		EventName:       race.EventName,
+	}
+	return nil
+}
+
+func (*memoryPersistence) IsCrawled(string) (bool, error) {
+	return false, nil
+}
+
+func TestSheetImporter_importTwice(t *testing.T) {
+	mapping := sheet.Mapping{
+		Competition: "113年臺南市議長盃游泳錦標賽",
+		PoolType:    crawler.PoolTypeLongCourse,
+		Date:        "2024-07-20",
+		Columns:     sheet.Columns{AgeGroup: "組別", Gender: "性別", Event: "項目", Name: "姓名", Time: "成績"},
+	}
+	rows := [][]string{
+		{"組別", "性別", "項目", "姓名", "成績"},
+		{"11&12歲級", "男", "100公尺自由式 計時決賽", "王小明", "1:02.35"},
+		{"11&12歲級", "男", "100公尺自由式 計時決賽", "李大華", "1:03.00"},
+	}
+	races, invalid, err := sheet.Races(rows, mapping)
+	require.NoError(t, err)
+	require.Empty(t, invalid)
+	require.Len(t, races, 1)
+
+	// 其他來源同名的項目不是同一個 race
+	ctsa := *races[0]
+	ctsa.Source = "ctsa"
+	store := &memoryRaceStore{races: map[string]*models.AggrRaceWithResult{
+		sheet.RaceKey(&ctsa): {Source: ctsa.Source},
+	}}
+	importer := &sheetImporter{raceStore: store, persistence: &memoryPersistence{store: store}, fileHash: "test"}
+
+	existing, err := findExistingRace(t.Context(), store, races[0])
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+	for range 2 {
+		imported, failed, err := importer.importRaces(t.Context(), races)
+		require.NoError(t, err)
+		assert.Equal(t, 1, imported)
+		assert.Zero(t, failed)
+	}
+	// 重複匯入只會覆寫同一個 race，加上 ctsa 的項目共兩個
+	assert.Len(t, store.races, 2)
+
+	existing, err = findExistingRace(t.Context(), store, races[0])
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, sheet.SourceName, existing.Source)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/lenex"
+
+	"github.com/spf13/cobra"
+)
+
+const lenexHashLength = 16
+
+// importLenexCmd represents the import lenex command
+var importLenexCmd = &cobra.Command{
+	Use:   "lenex <file>",
+	Short: "Import a Lenex (.lef/.lxf) results file",
+	Long: `Imports the meets, events, clubs, athletes, results, splits and relays of a
+Lenex results file (.lef, or the zip compressed .lxf). Each event and age group
+becomes a race; importing the same file again overwrites the races instead of
+duplicating them. Results whose time cannot be parsed are skipped and reported.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return fmt.Errorf("get dry-run fail: %w", err)
+		}
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("read %s fail: %w", args[0], err)
+		}
+		doc, err := lenex.Read(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		races, err := lenex.Races(doc)
+		if err != nil {
+			return fmt.Errorf("convert lenex fail: %w", err)
+		}
+
+		poolTypes, err := crawlerPoolTypes()
+		if err != nil {
+			return err
+		}
+		var crawlerPersistence crawler.Persistence
+		if !dryRun {
+			closeDB, err := connectMongo()
+			if err != nil {
+				return err
+			}
+			defer closeDB()
+
+			var store *mongo.Stores
+			mongo.InjectStore(func(s *mongo.Stores) {
+				store = s
+			})
+			crawlerPersistence, err = newMongoPersistence(store)
+			if err != nil {
+				return err
+			}
+		}
+
+		sum := sha256.Sum256(data)
+		fileHash := hex.EncodeToString(sum[:])[:lenexHashLength]
+		var imported, results, failed int
+		for _, r := range races {
+			race := r.Race
+			crawler.LogUnparsedRows(race)
+			if len(race.Results) == 0 {
+				continue
+			}
+			if race.PoolType == "" {
+				race.PoolType = poolTypes.Resolve(race.CompetitionName)
+			}
+			if dryRun {
+				fmt.Printf("%s [%s] %d 筆成績\n", race.CompetitionName, race.EventName, len(race.Results))
+			} else if err := crawlerPersistence.PersistRace(lenexURL(fileHash, r.Key), race); err != nil {
+				log.Printf("❌ 儲存失敗 %s [%s]: %v", race.CompetitionName, race.EventName, err)
+				failed++
+				continue
+			}
+			imported++
+			results += len(race.Results)
+		}
+		fmt.Printf("✅ 匯入完成: %d 個項目, %d 筆成績, 失敗 %d\n", imported, results, failed)
+		if failed > 0 {
+			return fmt.Errorf("import lenex fail: %d of %d races failed", failed, imported+failed)
+		}
+		return nil
+	},
+}
+
+// lenexURL 是匯入的項目記錄在爬取紀錄中的網址，以檔案內容的雜湊區分不同的檔案
+func lenexURL(fileHash, key string) string {
+	return fmt.Sprintf("%s://%s/%s", lenex.SourceName, fileHash, key)
+}
+
+func init() {
+	importCmd.AddCommand(importLenexCmd)
+
+	importLenexCmd.Flags().Bool("dry-run", false, "print the races in the file without saving")
+}
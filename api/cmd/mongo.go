@@ -0,0 +1,35 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"aquascore/api/internal/db/mongo"
+
+	"github.com/spf13/viper"
+)
+
+// connectMongo 依設定檔連線 MongoDB，回傳的函式用來關閉連線
+func connectMongo() (func(), error) {
+	dbCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	closeFunc, err := mongo.IniMongodb(
+		dbCtx,
+		viper.GetString("database.uri"),
+		viper.GetString("database.db"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("init mongodb fail: %w", err)
+	}
+	return func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := closeFunc(closeCtx); err != nil {
+			fmt.Printf("close mongodb fail: %v\n", err)
+		}
+	}, nil
+}
@@ -0,0 +1,25 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const rebuildTimeout = 30 * time.Minute
+
+// rebuildCmd represents the rebuild command
+var rebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Recompute data derived from the stored races",
+	Long: `Recomputes collections derived from the stored races and results. They are
+kept up to date when races are saved; rebuild them after deleting races or
+changing how they are derived.`,
+}
+
+func init() {
+	rootCmd.AddCommand(rebuildCmd)
+}
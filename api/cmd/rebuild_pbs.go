@@ -0,0 +1,80 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"aquascore/api/internal/athlete"
+	"aquascore/api/internal/crawler/persistence"
+	"aquascore/api/internal/db/mongo"
+
+	"github.com/spf13/cobra"
+)
+
+// pbBatchSize 是重新計算個人最佳成績時每次讀取成績的選手數量
+const pbBatchSize = 500
+
+// rebuildPBsCmd represents the rebuild pbs command
+var rebuildPBsCmd = &cobra.Command{
+	Use:   "pbs",
+	Short: "Recompute every athlete's personal and season bests",
+	Long: `Recomputes the personal best and per-season bests of every athlete linked to
+a result, by distance, stroke and pool, from their individual results.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return fmt.Errorf("get dry-run fail: %w", err)
+		}
+
+		closeDB, err := connectMongo()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		var athleteBestStore mongo.AthleteBestStore
+		mongo.InjectStore(func(s *mongo.Stores) {
+			athleteBestStore = s.AthleteBestStore
+		})
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), rebuildTimeout)
+		defer cancel()
+		ids, err := athleteBestStore.GetSwimAthleteIDs(ctx)
+		if err != nil {
+			return fmt.Errorf("get athlete ids fail: %w", err)
+		}
+		bests := 0
+		for start := 0; start < len(ids); start += pbBatchSize {
+			batch := ids[start:min(start+pbBatchSize, len(ids))]
+			if !dryRun {
+				if err := persistence.UpdateAthleteBests(ctx, athleteBestStore, batch); err != nil {
+					return err
+				}
+				continue
+			}
+			swims, err := athleteBestStore.GetAthleteSwims(ctx, batch)
+			if err != nil {
+				return fmt.Errorf("get athlete swims fail: %w", err)
+			}
+			for _, id := range batch {
+				bests += len(athlete.PersonalBests(id, swims, time.Now()))
+			}
+		}
+		if dryRun {
+			fmt.Printf("✅ %d 位選手，%d 項個人最佳成績\n", len(ids), bests)
+			return nil
+		}
+		fmt.Printf("✅ 已更新 %d 位選手的個人最佳成績\n", len(ids))
+		return nil
+	},
+}
+
+func init() {
+	rebuildCmd.AddCommand(rebuildPBsCmd)
+
+	rebuildPBsCmd.Flags().Bool("dry-run", false, "count the recomputed personal bests without saving")
+}
@@ -0,0 +1,77 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/crawler/persistence"
+	"aquascore/api/internal/db/mongo"
+
+	"github.com/spf13/cobra"
+)
+
+// rebuildRecordsCmd represents the rebuild records command
+var rebuildRecordsCmd = &cobra.Command{
+	Use:   "records",
+	Short: "Recompute the national and games record history",
+	Long: `Recomputes the history of every national record (全國紀錄) and games record
+(大會紀錄) from the records listed on the stored score reports and the results
+that equalled or broke them, then deletes records no race refers to any more.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return fmt.Errorf("get dry-run fail: %w", err)
+		}
+
+		closeDB, err := connectMongo()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		var store *mongo.Stores
+		mongo.InjectStore(func(s *mongo.Stores) {
+			store = s
+		})
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), rebuildTimeout)
+		defer cancel()
+		races, err := store.RaceStore.FindRacesWithResults(ctx, mongo.NewRaceQueryWithEvent())
+		if err != nil {
+			return fmt.Errorf("find races fail: %w", err)
+		}
+		records := persistence.BuildRecords(races, time.Now())
+		if dryRun {
+			for _, r := range records {
+				fmt.Printf("%s %s%s %s: %s (%d 筆歷史)\n", r.Key, r.AgeGroup, r.Gender, r.EventType,
+					crawler.FormatSwimTime(r.Record), len(r.History))
+			}
+			fmt.Printf("✅ %d 個項目，%d 項紀錄\n", len(races), len(records))
+			return nil
+		}
+		if err := store.RecordStore.SaveRecords(ctx, records); err != nil {
+			return fmt.Errorf("save records fail: %w", err)
+		}
+		keys := make([]string, len(records))
+		for i, r := range records {
+			keys[i] = r.Key
+		}
+		deleted, err := store.RecordStore.DeleteRecordsExcept(ctx, keys)
+		if err != nil {
+			return fmt.Errorf("delete stale records fail: %w", err)
+		}
+		fmt.Printf("✅ %d 個項目，更新 %d 項紀錄，刪除 %d 項\n", len(races), len(records), deleted)
+		return nil
+	},
+}
+
+func init() {
+	rebuildCmd.AddCommand(rebuildRecordsCmd)
+
+	rebuildRecordsCmd.Flags().Bool("dry-run", false, "print the recomputed records without saving")
+}
@@ -0,0 +1,48 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"aquascore/api/internal/db/mongo"
+
+	"github.com/spf13/cobra"
+)
+
+// rebuildSearchCmd represents the rebuild search command
+var rebuildSearchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "Recompute the search names, n-grams and race counts of every athlete",
+	Long: `Recomputes the normalized names, pinyin, n-grams and race counts used by
+GET /athletes/search for every athlete. They are kept up to date when athletes
+are saved, merged or split and when results are linked; rebuild them for athletes
+created before search or race counts were added, or after the pinyin table changes.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		closeDB, err := connectMongo()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		var athleteStore mongo.AthleteStore
+		mongo.InjectStore(func(s *mongo.Stores) {
+			athleteStore = s.AthleteStore
+		})
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), rebuildTimeout)
+		defer cancel()
+		updated, err := athleteStore.UpdateSearchKeys(ctx)
+		if err != nil {
+			return fmt.Errorf("update search keys fail: %w", err)
+		}
+		fmt.Printf("✅ 已更新 %d 位選手的搜尋資料與出賽次數\n", updated)
+		return nil
+	},
+}
+
+func init() {
+	rebuildCmd.AddCommand(rebuildSearchCmd)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/crawler/persistence"
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/spf13/cobra"
+)
+
+// reparseCmd represents the reparse command
+var reparseCmd = &cobra.Command{
+	Use:   "reparse",
+	Short: "Rebuild races from archived score reports",
+	Long: `Re-parses the raw score reports archived by the crawler with the current
+parser and replaces the matching race/raceResult documents, so parser fixes
+can be applied without crawling the source site again.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		year, err := cmd.Flags().GetString("year")
+		if err != nil {
+			return fmt.Errorf("get year fail: %w", err)
+		}
+		competition, err := cmd.Flags().GetString("competition")
+		if err != nil {
+			return fmt.Errorf("get competition fail: %w", err)
+		}
+		skipDetails, err := cmd.Flags().GetBool("skip-details")
+		if err != nil {
+			return fmt.Errorf("get skip-details fail: %w", err)
+		}
+
+		poolTypes, err := crawlerPoolTypes()
+		if err != nil {
+			return err
+		}
+
+		closeDB, err := connectMongo()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		var store *mongo.Stores
+		mongo.InjectStore(func(s *mongo.Stores) {
+			store = s
+		})
+		crawlerPersistence, err := newMongoPersistence(store)
+		if err != nil {
+			return err
+		}
+
+		r := &reparser{
+			persistence:  crawlerPersistence,
+			poolTypes:    poolTypes,
+			fetchDetails: !skipDetails,
+			sources:      map[string]crawler.Source{},
+			seen:         map[string]bool{},
+		}
+		query := mongo.NewRawPageQuery(year, competition)
+		var parsed, failed int
+		var last *models.RawPage
+		for {
+			findCtx, cancel := context.WithTimeout(cmd.Context(), time.Minute)
+			pages, err := store.RawPageStore.FindRawPages(findCtx, query, last, rawPageBatchSize)
+			cancel()
+			if err != nil {
+				return fmt.Errorf("find raw pages fail: %w", err)
+			}
+			if len(pages) == 0 {
+				break
+			}
+			batchParsed, batchFailed := r.reparse(cmd.Context(), pages)
+			parsed += batchParsed
+			failed += batchFailed
+			last = pages[len(pages)-1]
+		}
+		fmt.Printf("✅ 重新解析完成: 成功 %d, 失敗 %d\n", parsed, failed)
+		if failed > 0 {
+			return fmt.Errorf("reparse fail: %d of %d score reports failed", failed, parsed+failed)
+		}
+		return nil
+	},
+}
+
+// rawPageBatchSize 是每次從資料庫讀取的原始成績報告數量，避免一次將所有報告內容載入記憶體
+const rawPageBatchSize = 100
+
+type reparser struct {
+	persistence  crawler.Persistence
+	poolTypes    *crawler.PoolTypeResolver
+	fetchDetails bool
+	sources      map[string]crawler.Source
+	seen         map[string]bool // 已處理過的 URL
+}
+
+// reparse 重新解析並寫入一批成績報告，回傳成功與失敗的數量
+func (r *reparser) reparse(ctx context.Context, pages []*models.RawPage) (parsed, failed int) {
+	// pages 由新到舊讀取，同一個 URL 只取最新的一份
+	for _, rawPage := range pages {
+		if r.seen[rawPage.URL] {
+			continue
+		}
+		r.seen[rawPage.URL] = true
+		race, err := crawler.ParseArchivedPage(persistence.RawPageToArchivedPage(rawPage))
+		if err != nil {
+			log.Printf("❌ 解析失敗 %s [%s]: %v", rawPage.CompetitionName, rawPage.RaceName, err)
+			failed++
+			continue
+		}
+		crawler.LogUnparsedRows(race)
+		race.PoolType = r.poolTypes.Resolve(race.CompetitionName)
+		if r.fetchDetails {
+			r.fetchResultDetails(ctx, rawPage.Source, race)
+		}
+		// PersistRace 以項目的 natural key 覆寫既有的 race 與 raceResult
+		if err := r.persistence.PersistRace(rawPage.URL, race); err != nil {
+			log.Printf("❌ 儲存失敗 %s [%s]: %v", rawPage.CompetitionName, rawPage.RaceName, err)
+			failed++
+			continue
+		}
+		parsed++
+	}
+	return parsed, failed
+}
+
+// fetchResultDetails 重新抓取成績連結的詳細成績頁 (分段、反應時間)，詳細成績頁沒有封存
+func (r *reparser) fetchResultDetails(ctx context.Context, sourceName string, race *crawler.Race) {
+	source, ok := r.sources[sourceName]
+	if !ok {
+		var err error
+		source, err = crawler.NewSource(sourceName)
+		if err != nil {
+			log.Printf("⚠️ 無法建立成績來源 %s: %v", sourceName, err)
+			return
+		}
+		r.sources[sourceName] = source
+	}
+	crawler.FetchResultDetails(ctx, source, race)
+}
+
+func init() {
+	rootCmd.AddCommand(reparseCmd)
+
+	reparseCmd.Flags().String("year", "", "only re-parse score reports of this year")
+	reparseCmd.Flags().String("competition", "", "only re-parse score reports of competitions matching this name")
+	reparseCmd.Flags().Bool("skip-details", false,
+		"do not re-fetch the linked result detail pages (splits and reaction times), which are not archived")
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/scheduler"
+
+	"github.com/spf13/viper"
+)
+
+// scheduledCrawl 是設定檔 scheduler.crawls 中的一筆排程爬取
+type scheduledCrawl struct {
+	Name        string `mapstructure:"name"`
+	Cron        string `mapstructure:"cron"`
+	Source      string `mapstructure:"source"`
+	Years       string `mapstructure:"years"` // 空白代表來源目前的年度
+	Concurrency int    `mapstructure:"concurrency"`
+	RecheckDays int    `mapstructure:"recheck_days"` // 大於 0 時重新檢查近期比賽的成績
+}
+
+// newCrawlScheduler 依 scheduler.* 設定建立排程，需先連線 MongoDB
+func newCrawlScheduler() (*scheduler.Scheduler, error) {
+	var crawls []scheduledCrawl
+	if err := viper.UnmarshalKey("scheduler.crawls", &crawls); err != nil {
+		return nil, fmt.Errorf("read scheduler.crawls fail: %w", err)
+	}
+	if len(crawls) == 0 {
+		return nil, errors.New("scheduler.crawls is empty")
+	}
+
+	loc := time.Local
+	if tz := viper.GetString("scheduler.timezone"); tz != "" {
+		var err error
+		loc, err = time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("load scheduler.timezone fail: %w", err)
+		}
+	}
+	var leaseStore mongo.LeaseStore
+	mongo.InjectStore(func(s *mongo.Stores) {
+		leaseStore = s.LeaseStore
+	})
+	s := scheduler.New(
+		scheduler.WithLocker(leaseLocker{store: leaseStore}),
+		scheduler.WithLeaseTTL(viper.GetDuration("scheduler.lease_ttl")),
+		scheduler.WithLocation(loc),
+	)
+
+	baseOpts, err := crawlerOptions()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range crawls {
+		if c.Source == "" {
+			return nil, fmt.Errorf("scheduled crawl %s has no source", c.Name)
+		}
+		years := []string{""}
+		if c.Years != "" {
+			var err error
+			years, err = crawler.ParseYears(c.Years)
+			if err != nil {
+				return nil, fmt.Errorf("scheduled crawl %s: %w", c.Name, err)
+			}
+		}
+		concurrency := max(c.Concurrency, 1)
+		opts := baseOpts
+		if c.RecheckDays > 0 {
+			opts = append(slices.Clip(baseOpts), crawlerRecheckOption(c.RecheckDays))
+		}
+		// 爬取同一個來源的工作共用租約，避免同時對同一個網站爬取與寫入
+		err := s.Add(c.Name, c.Cron, func(ctx context.Context) error {
+			return crawler.CrawlYears(ctx, c.Source, years, concurrency, opts...)
+		}, scheduler.WithLease("crawl:"+c.Source))
+		if err != nil {
+			return nil, fmt.Errorf("add scheduled crawl %s fail: %w", c.Name, err)
+		}
+	}
+	return s, nil
+}
+
+// leaseLocker 以 MongoDB 的 lease collection 實作排程的租約鎖
+type leaseLocker struct {
+	store mongo.LeaseStore
+}
+
+func (l leaseLocker) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	return l.store.AcquireLease(ctx, name, owner, ttl)
+}
+
+func (l leaseLocker) Release(ctx context.Context, name, owner string) error {
+	return l.store.ReleaseLease(ctx, name, owner)
+}
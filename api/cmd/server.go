@@ -7,6 +7,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	"aquascore/api/internal/db/mongo"
 	"aquascore/api/internal/server"
@@ -32,7 +36,8 @@ and communicates with the Python analysis service via gRPC.`,
 			}
 		}()
 
-		ctx := cmd.Context()
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
 
 		mongoURI := viper.GetString("database.uri")
 		dbName := viper.GetString("database.db")
@@ -42,7 +47,8 @@ and communicates with the Python analysis service via gRPC.`,
 			return fmt.Errorf("failed to initialize database: %w", err)
 		}
 		defer func() {
-			if err := closeDB(ctx); err != nil {
+			// ctx 在關機時已取消，關閉連線改用新的 context
+			if err := closeDB(context.Background()); err != nil {
 				log.Printf("failed to close DB: %v", err)
 			}
 		}()
@@ -62,8 +68,28 @@ and communicates with the Python analysis service via gRPC.`,
 		defer func() {
 			_ = server.Close()
 		}()
+
+		// 排程與 API 共用同一個 ctx，關機時會取消執行中的爬取並等待結束
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		if viper.GetBool("scheduler.enabled") {
+			s, err := newCrawlScheduler()
+			if err != nil {
+				return fmt.Errorf("failed to create scheduler: %w", err)
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := s.Run(ctx); err != nil {
+					log.Printf("scheduler stopped: %v", err)
+				}
+			}()
+		}
+
 		fmt.Printf("Starting AquaScore API server on %s...\n", addr)
-		return server.Start(addr)
+		err = server.Start(ctx, addr)
+		stop()
+		return err
 	},
 }
 
@@ -0,0 +1,114 @@
+package athlete
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"time"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/db/mongo/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// PersonalBests 依項目 (距離、泳式) 與水道計算選手的個人最佳成績與每年的季最佳。
+// 只計算選手的個人項目中可列入表現分析的成績 (見 crawler.ResultStatus.HasValidTime)，
+// 成績相同時以較早的為準
+func PersonalBests(
+	athleteID bson.ObjectID, swims []*models.AggrAthleteSwim, updatedAt time.Time,
+) []*models.AthleteBest {
+	swims = slices.DeleteFunc(slices.Clone(swims), func(swim *models.AggrAthleteSwim) bool {
+		return !countsForBest(athleteID, swim)
+	})
+	slices.SortStableFunc(swims, func(a, b *models.AggrAthleteSwim) int { return a.EventDate.Compare(b.EventDate) })
+
+	type eventKey struct {
+		poolType string
+		stroke   string
+		distance int
+	}
+	byEvent := make(map[eventKey]*models.AthleteBest)
+	seasons := make(map[eventKey]map[string]*models.SeasonBest)
+	for _, swim := range swims {
+		key := eventKey{swim.PoolType, swim.Stroke, swim.Distance}
+		best, ok := byEvent[key]
+		if !ok {
+			best = models.NewAthleteBest()
+			best.AthleteID = athleteID
+			best.EventType = models.RaceEvent{Distance: swim.Distance, Stroke: swim.Stroke}.Label()
+			best.Distance = swim.Distance
+			best.Stroke = swim.Stroke
+			best.PoolType = swim.PoolType
+			best.UpdatedAt = updatedAt
+			byEvent[key] = best
+			seasons[key] = make(map[string]*models.SeasonBest)
+		}
+		// 姓名與組別以最近一次的成績為準
+		if len(swim.Name) > 0 {
+			best.Name = swim.Name[0]
+		}
+		best.Gender = swim.Gender
+		if !ok || swim.Record < best.Record {
+			best.BestSwim = bestSwim(swim)
+		}
+
+		season, ok := seasons[key][swim.Year]
+		if !ok {
+			season = &models.SeasonBest{Year: swim.Year}
+			seasons[key][swim.Year] = season
+		}
+		season.AgeGroup = swim.AgeGroup
+		if !ok || swim.Record < season.Record {
+			season.BestSwim = bestSwim(swim)
+		}
+	}
+
+	bests := make([]*models.AthleteBest, 0, len(byEvent))
+	for key, best := range byEvent {
+		for _, season := range seasons[key] {
+			best.Seasons = append(best.Seasons, *season)
+		}
+		slices.SortFunc(best.Seasons, func(a, b models.SeasonBest) int { return compareYear(b.Year, a.Year) })
+		bests = append(bests, best)
+	}
+	slices.SortFunc(bests, func(a, b *models.AthleteBest) int {
+		return cmp.Or(
+			cmp.Compare(a.PoolType, b.PoolType),
+			cmp.Compare(a.Stroke, b.Stroke),
+			cmp.Compare(a.Distance, b.Distance),
+		)
+	})
+	return bests
+}
+
+func countsForBest(athleteID bson.ObjectID, swim *models.AggrAthleteSwim) bool {
+	if swim.Relay || swim.Distance == 0 || swim.Record <= 0 || !slices.Contains(swim.AthleteIDs, athleteID) {
+		return false
+	}
+	status := crawler.ResultStatus(swim.Status)
+	if status == "" {
+		status, _ = crawler.ParseResultStatus(swim.Note, true)
+	}
+	return status.HasValidTime()
+}
+
+func bestSwim(swim *models.AggrAthleteSwim) models.BestSwim {
+	return models.BestSwim{
+		Record:          swim.Record,
+		Date:            swim.EventDate,
+		RaceID:          swim.RaceID,
+		CompetitionName: swim.CompetitionName,
+		EventName:       swim.EventName,
+	}
+}
+
+// compareYear 比較民國年，無法轉成數字時以字串比較
+func compareYear(a, b string) int {
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+	if errA != nil || errB != nil {
+		return cmp.Compare(a, b)
+	}
+	return cmp.Compare(x, y)
+}
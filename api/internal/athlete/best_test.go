@@ -0,0 +1,75 @@
+package athlete
+
+import (
+	"testing"
+	"time"
+
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestPersonalBests(t *testing.T) {
+	id := bson.NewObjectID()
+	other := bson.NewObjectID()
+	day := func(year, month, d int) time.Time { return time.Date(year, time.Month(month), d, 0, 0, 0, 0, time.UTC) }
+	swim := func(
+		year string, date time.Time, event models.RaceEvent, record time.Duration, status string,
+	) *models.AggrAthleteSwim {
+		return &models.AggrAthleteSwim{
+			RaceID:     bson.NewObjectID(),
+			AthleteIDs: []bson.ObjectID{id},
+			Name:       []string{"王小明"},
+			Year:       year,
+			EventDate:  date,
+			Gender:     "男子組",
+			AgeGroup:   year + "組",
+			PoolType:   "長水道",
+			Record:     record,
+			Status:     status,
+			RaceEvent:  event,
+		}
+	}
+	free50 := models.RaceEvent{Distance: 50, Stroke: "freestyle"}
+	back100 := models.RaceEvent{Distance: 100, Stroke: "backstroke"}
+
+	seasonBest113 := swim("113", day(2024, 5, 1), free50, 30*time.Second, "ok")
+	sameTime := swim("113", day(2024, 7, 1), free50, 30*time.Second, "ok")
+	best := swim("114", day(2025, 3, 1), free50, 29*time.Second, "ok")
+	dq := swim("114", day(2025, 4, 1), free50, 28*time.Second, "dq")
+	slower := swim("114", day(2025, 6, 1), free50, 31*time.Second, "ok")
+	otherAthlete := swim("114", day(2025, 6, 1), free50, 27*time.Second, "ok")
+	otherAthlete.AthleteIDs = []bson.ObjectID{other}
+	back := swim("114", day(2025, 6, 2), back100, time.Minute, "")
+	relayEvent := models.RaceEvent{Distance: 50, Stroke: "freestyle", Relay: true}
+	relay := swim("114", day(2025, 6, 3), relayEvent, 25*time.Second, "ok")
+
+	now := day(2025, 10, 1)
+	swims := []*models.AggrAthleteSwim{slower, best, dq, sameTime, seasonBest113, otherAthlete, back, relay}
+	bests := PersonalBests(id, swims, now)
+	require.Len(t, bests, 2)
+
+	// 沒有狀態的舊資料由備註判斷
+	assert.Equal(t, "backstroke", bests[0].Stroke)
+	assert.Equal(t, time.Minute, bests[0].Record)
+
+	freeBest := bests[1]
+	assert.Equal(t, "freestyle", freeBest.Stroke)
+	assert.Equal(t, id, freeBest.AthleteID)
+	assert.Equal(t, "王小明", freeBest.Name)
+	assert.Equal(t, "50公尺自由式", freeBest.EventType)
+	assert.Equal(t, 29*time.Second, freeBest.Record)
+	assert.Equal(t, best.RaceID, freeBest.RaceID)
+	assert.Equal(t, now, freeBest.UpdatedAt)
+	// 季最佳由新到舊，同樣的成績以較早的為準
+	require.Len(t, freeBest.Seasons, 2)
+	assert.Equal(t, "114", freeBest.Seasons[0].Year)
+	assert.Equal(t, best.RaceID, freeBest.Seasons[0].RaceID)
+	assert.Equal(t, "113", freeBest.Seasons[1].Year)
+	assert.Equal(t, "113組", freeBest.Seasons[1].AgeGroup)
+	assert.Equal(t, seasonBest113.RaceID, freeBest.Seasons[1].RaceID)
+
+	assert.Empty(t, PersonalBests(bson.NewObjectID(), swims, now))
+}
@@ -0,0 +1,540 @@
+// gen 由 Perl 內建模組 Unicode::Collate::CJK 的漢語拼音與注音排序資料 (CLDR) 產生 athlete 的拼音表。
+//
+// 排序資料依讀音排列約兩萬個漢字，但沒有標出每組字的讀音：每一組同音同調的字從新的一行開始，
+// 一行最多 10 個字。產生器先切出每一組字，再以 anchors.txt 中已知讀音的字為錨點標上音節；
+// 錨點之間的字組，由同一批字在注音排序中的位置決定屬於前後哪一個音節。無法確定的字組不列入拼音表。
+//
+// -cover 列出的目錄 (各匯入來源的測試資料) 中出現的漢字都必須有拼音，否則不產生拼音表：
+//
+//	go run ./gen -o pinyin_table.go -cover ../crawler/test_file,../sheet/test_file
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// lineWidth 是排序資料一行最多的字數，滿行的下一行可能是同一組字
+	lineWidth = 10
+	// maxIterations 是標記錨點之間字組的最多輪數
+	maxIterations = 50
+	// outputWidth 是輸出檔中每個字串的最多字數，避免單行過長
+	outputWidth = 30
+)
+
+var (
+	//go:embed anchors.txt
+	anchorsText string
+	//go:embed names.txt
+	namesText string
+)
+
+func main() {
+	pinyinPath := flag.String("pinyin", "", "path of Unicode/Collate/CJK/Pinyin.pm (default: located with perl)")
+	zhuyinPath := flag.String("zhuyin", "", "path of Unicode/Collate/CJK/Zhuyin.pm (default: located with perl)")
+	output := flag.String("o", "pinyin_table.go", "output file")
+	cover := flag.String("cover", "", "comma-separated directories whose Han characters must all have a reading")
+	flag.Parse()
+
+	pinyin, err := loadCollation(*pinyinPath, "Pinyin")
+	if err != nil {
+		log.Fatal(err)
+	}
+	zhuyin, err := loadCollation(*zhuyinPath, "Zhuyin")
+	if err != nil {
+		log.Fatal(err)
+	}
+	anchors, err := parseSyllables(anchorsText)
+	if err != nil {
+		log.Fatalf("parse anchors.txt fail: %v", err)
+	}
+	names, err := parseSyllables(namesText)
+	if err != nil {
+		log.Fatalf("parse names.txt fail: %v", err)
+	}
+
+	readings, unlabeled, err := label(pinyin, zhuyin, anchors)
+	if err != nil {
+		log.Fatal(err)
+	}
+	table := make(map[string][]rune)
+	for _, group := range readings {
+		table[group.syllable] = append(table[group.syllable], group.chars...)
+	}
+	for _, extra := range []map[rune][]string{anchors, names} {
+		// 依字碼加入，讓每次產生的拼音表相同
+		for _, char := range slices.Sorted(maps.Keys(extra)) {
+			for _, syllable := range extra[char] {
+				if !slices.Contains(table[syllable], char) {
+					table[syllable] = append(table[syllable], char)
+				}
+			}
+		}
+	}
+	if *cover != "" {
+		missing, err := uncovered(table, strings.Split(*cover, ","))
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(missing) > 0 {
+			log.Fatalf("%d 個字沒有拼音: %s", len(missing), string(missing))
+		}
+	}
+	src, err := render(table)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+	chars := 0
+	for _, group := range readings {
+		chars += len(group.chars)
+	}
+	log.Printf("%d 個音節，%d 個字，%d 個字無法判斷讀音", len(table), chars, unlabeled)
+}
+
+// uncovered 回傳 dirs 中的檔案出現但 table 中沒有讀音的漢字，不是 UTF-8 的檔案 (例如 xlsx) 略過
+func uncovered(table map[string][]rune, dirs []string) ([]rune, error) {
+	known := make(map[rune]bool)
+	for _, chars := range table {
+		for _, char := range chars {
+			known[char] = true
+		}
+	}
+	var missing []rune
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if !utf8.Valid(content) {
+				return nil
+			}
+			for _, char := range string(content) {
+				if unicode.Is(unicode.Han, char) && !known[char] {
+					known[char] = true
+					missing = append(missing, char)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("read %s fail: %w", dir, err)
+		}
+	}
+	return missing, nil
+}
+
+// section 是排序資料中同一個聲母 (拼音首字母或注音聲母) 的字，每一行一個 slice
+type section struct {
+	marker rune
+	lines  [][]rune
+}
+
+// loadCollation 讀取 Unicode::Collate::CJK 的排序資料，path 為空時以 perl 找出模組位置
+func loadCollation(path, module string) ([]section, error) {
+	if path == "" {
+		out, err := exec.Command("perl", "-MUnicode::Collate::CJK::"+module, "-e",
+			fmt.Sprintf(`print $INC{"Unicode/Collate/CJK/%s.pm"}`, module)).Output()
+		if err != nil {
+			return nil, fmt.Errorf("locate Unicode::Collate::CJK::%s fail: %w", module, err)
+		}
+		path = string(out)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s fail: %w", path, err)
+	}
+	_, body, ok := strings.Cut(string(data), "__DATA__\n")
+	if !ok {
+		return nil, fmt.Errorf("%s has no __DATA__ section", path)
+	}
+	body, _, _ = strings.Cut(body, "__END__")
+	var sections []section
+	for _, line := range strings.Split(body, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		// FDD0-xxxx 標示接下來的字的拼音首字母或注音聲母
+		if marker, ok := strings.CutPrefix(fields[0], "FDD0-"); ok {
+			r, err := strconv.ParseUint(marker, 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid marker %q: %w", fields[0], err)
+			}
+			sections = append(sections, section{marker: rune(r)})
+			continue
+		}
+		if len(sections) == 0 {
+			return nil, fmt.Errorf("%s: characters before the first marker", path)
+		}
+		chars := make([]rune, len(fields))
+		for i, field := range fields {
+			r, err := strconv.ParseUint(field, 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid code point %q: %w", field, err)
+			}
+			chars[i] = rune(r)
+		}
+		last := &sections[len(sections)-1]
+		last.lines = append(last.lines, chars)
+	}
+	return sections, nil
+}
+
+// parseSyllables 讀取 "音節 字..." 格式的對照，# 開頭的行為註解
+func parseSyllables(text string) (map[rune][]string, error) {
+	readings := make(map[rune][]string)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		syllable, chars, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("invalid line %q", line)
+		}
+		for _, char := range strings.TrimSpace(chars) {
+			readings[char] = append(readings[char], syllable)
+		}
+	}
+	return readings, nil
+}
+
+// group 是排序資料中同一組 (同音) 的字
+type group struct {
+	marker   rune
+	chars    []rune
+	syllable string
+}
+
+// sequence 是一份排序資料切出的字組與每個字所在的字組
+type sequence struct {
+	groups  []*group
+	groupOf map[rune]int
+	order   []string       // 錨點音節在排序資料中的順序
+	rank    map[string]int // 音節在 order 中的位置
+}
+
+// label 回傳標上音節的拼音字組與無法判斷讀音的字數
+func label(pinyin, zhuyin []section, anchors map[rune][]string) ([]*group, int, error) {
+	common, inZhuyin := charSet(pinyin), charSet(zhuyin)
+	for char := range common {
+		if !inZhuyin[char] {
+			delete(common, char)
+		}
+	}
+	p := newSequence(pinyin, successors(zhuyin, common), common)
+	z := newSequence(zhuyin, successors(pinyin, common), common)
+
+	// 錨點所在的拼音排序區段必須與音節的首字母相同，否則是排序資料採用了其他讀音
+	primary := make(map[rune]string, len(anchors))
+	for _, g := range p.groups {
+		for _, char := range g.chars {
+			syllables, ok := anchors[char]
+			if !ok || len(syllables) != 1 {
+				continue
+			}
+			if strings.ToUpper(syllables[0][:1]) != string(g.marker) {
+				log.Printf("⚠️ 錨點 %c (%s) 在排序資料的 %c 區段，略過", char, syllables[0], g.marker)
+				continue
+			}
+			primary[char] = syllables[0]
+		}
+	}
+	for _, s := range []*sequence{p, z} {
+		s.labelAnchors(primary)
+		if err := s.buildOrder(); err != nil {
+			return nil, 0, err
+		}
+	}
+	for _, neighbors := range []bool{false, true} {
+		for range maxIterations {
+			changed := p.fill(z, neighbors)
+			changed += z.fill(p, neighbors)
+			if changed == 0 {
+				break
+			}
+		}
+	}
+
+	var labeled []*group
+	unlabeled := 0
+	for _, g := range p.groups {
+		if g.syllable == "" {
+			unlabeled += len(g.chars)
+			continue
+		}
+		labeled = append(labeled, g)
+	}
+	return labeled, unlabeled, nil
+}
+
+func charSet(sections []section) map[rune]bool {
+	set := make(map[rune]bool)
+	for _, s := range sections {
+		for _, line := range s.lines {
+			for _, char := range line {
+				set[char] = true
+			}
+		}
+	}
+	return set
+}
+
+// successors 回傳排序資料中每個字的下一個字，只計算 keep 中的字
+func successors(sections []section, keep map[rune]bool) map[rune]rune {
+	next := make(map[rune]rune)
+	var prev rune
+	for _, s := range sections {
+		for _, line := range s.lines {
+			for _, char := range line {
+				if !keep[char] {
+					continue
+				}
+				if prev != 0 {
+					next[prev] = char
+				}
+				prev = char
+			}
+		}
+	}
+	return next
+}
+
+// newSequence 切出排序資料的字組：未滿的行一定是一組的結尾；滿行的下一行只有在另一份排序資料中
+// 也緊接在後時才視為同一組
+func newSequence(sections []section, otherNext map[rune]rune, common map[rune]bool) *sequence {
+	s := &sequence{groupOf: make(map[rune]int)}
+	for _, sec := range sections {
+		var current []rune
+		for i, line := range sec.lines {
+			current = append(current, line...)
+			if len(line) == lineWidth && i+1 < len(sec.lines) {
+				a := commonChars(line, common)
+				b := commonChars(sec.lines[i+1], common)
+				if len(a) > 0 && len(b) > 0 && otherNext[a[len(a)-1]] == b[0] {
+					continue
+				}
+			}
+			s.groups = append(s.groups, &group{marker: sec.marker, chars: current})
+			current = nil
+		}
+	}
+	for i, g := range s.groups {
+		for _, char := range g.chars {
+			s.groupOf[char] = i
+		}
+	}
+	return s
+}
+
+// commonChars 回傳也出現在另一份排序資料中的字
+func commonChars(line []rune, common map[rune]bool) []rune {
+	var chars []rune
+	for _, char := range line {
+		if common[char] {
+			chars = append(chars, char)
+		}
+	}
+	return chars
+}
+
+// labelAnchors 以字組中最多錨點的音節標記字組
+func (s *sequence) labelAnchors(primary map[rune]string) {
+	for _, g := range s.groups {
+		counts := newCounter[string]()
+		for _, char := range g.chars {
+			if syllable, ok := primary[char]; ok {
+				counts.add(syllable)
+			}
+		}
+		g.syllable, _ = counts.best()
+	}
+}
+
+// buildOrder 依錨點建立音節的排列順序，同一個音節的錨點必須連續
+func (s *sequence) buildOrder() error {
+	s.rank = make(map[string]int)
+	for _, g := range s.groups {
+		if g.syllable == "" || (len(s.order) > 0 && s.order[len(s.order)-1] == g.syllable) {
+			continue
+		}
+		if _, ok := s.rank[g.syllable]; ok {
+			return fmt.Errorf("anchors of %q are not contiguous near %q, remove the polyphonic anchor",
+				g.syllable, string(g.chars))
+		}
+		s.rank[g.syllable] = len(s.order)
+		s.order = append(s.order, g.syllable)
+	}
+	return nil
+}
+
+// fill 標記前後錨點之間的字組，回傳新標記的數量：前後音節相同時即為該音節；
+// 否則取另一份排序資料中同一批字所在字組的音節，neighbors 時再參考該字組前後字組的音節
+func (s *sequence) fill(other *sequence, neighbors bool) int {
+	n := len(s.groups)
+	prev, next := make([]string, n), make([]string, n)
+	last := ""
+	for i, g := range s.groups {
+		if i > 0 && s.groups[i-1].marker != g.marker {
+			last = ""
+		}
+		prev[i] = last
+		if g.syllable != "" {
+			last = g.syllable
+		}
+	}
+	last = ""
+	for i := n - 1; i >= 0; i-- {
+		g := s.groups[i]
+		if i < n-1 && s.groups[i+1].marker != g.marker {
+			last = ""
+		}
+		next[i] = last
+		if g.syllable != "" {
+			last = g.syllable
+		}
+	}
+
+	changed := 0
+	for i, g := range s.groups {
+		if g.syllable != "" || prev[i] == "" || next[i] == "" {
+			continue
+		}
+		from, to := s.rank[prev[i]], s.rank[next[i]]
+		if from > to {
+			continue
+		}
+		allowed := s.order[from : to+1]
+		if len(allowed) == 1 {
+			g.syllable = allowed[0]
+			changed++
+			continue
+		}
+		counts := newCounter[string]()
+		groups := newCounter[int]()
+		for _, char := range g.chars {
+			j, ok := other.groupOf[char]
+			if !ok {
+				continue
+			}
+			groups.add(j)
+			if syllable := other.groups[j].syllable; slices.Contains(allowed, syllable) {
+				counts.add(syllable)
+			}
+		}
+		if len(counts.keys) > 0 {
+			if syllable, unique := counts.best(); unique {
+				g.syllable = syllable
+				changed++
+			}
+			continue
+		}
+		if !neighbors || len(groups.keys) == 0 {
+			continue
+		}
+		j, _ := groups.best()
+		var candidates []string
+		for _, k := range []int{j - 1, j + 1} {
+			if k < 0 || k >= len(other.groups) {
+				continue
+			}
+			syllable := other.groups[k].syllable
+			if slices.Contains(allowed, syllable) && !slices.Contains(candidates, syllable) {
+				candidates = append(candidates, syllable)
+			}
+		}
+		if len(candidates) == 1 {
+			g.syllable = candidates[0]
+			changed++
+		}
+	}
+	return changed
+}
+
+// counter 計算出現次數，次數相同時以先出現的為準
+type counter[K comparable] struct {
+	keys   []K
+	counts map[K]int
+}
+
+func newCounter[K comparable]() *counter[K] {
+	return &counter[K]{counts: make(map[K]int)}
+}
+
+func (c *counter[K]) add(key K) {
+	if _, ok := c.counts[key]; !ok {
+		c.keys = append(c.keys, key)
+	}
+	c.counts[key]++
+}
+
+// best 回傳次數最多的 key，以及是否沒有其他 key 的次數相同；沒有任何 key 時回傳零值
+func (c *counter[K]) best() (K, bool) {
+	var best K
+	unique := true
+	for i, key := range c.keys {
+		switch {
+		case i == 0 || c.counts[key] > c.counts[best]:
+			best, unique = key, true
+		case c.counts[key] == c.counts[best]:
+			unique = false
+		}
+	}
+	return best, unique
+}
+
+// render 輸出拼音表的 Go 原始碼，音節依字母排列，每個音節的字依排序資料的順序
+func render(table map[string][]rune) ([]byte, error) {
+	syllables := make([]string, 0, len(table))
+	for syllable := range table {
+		syllables = append(syllables, syllable)
+	}
+	slices.Sort(syllables)
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by go run ./gen; DO NOT EDIT.\n\n")
+	b.WriteString("package athlete\n\n")
+	b.WriteString("// pinyinSyllables 是無聲調拼音 (ü 寫成 v) 與讀音的字，由 Unicode::Collate::CJK 的漢語拼音排序資料\n")
+	b.WriteString("// 產生 (見 gen)；多音字列在每個讀音下，並補上 gen/names.txt 中姓名常用的讀音\n")
+	b.WriteString("var pinyinSyllables = map[string]string{\n")
+	for _, syllable := range syllables {
+		chars := table[syllable]
+		fmt.Fprintf(&b, "%q: ", syllable)
+		for start := 0; start < len(chars); start += outputWidth {
+			if start > 0 {
+				b.WriteString(" +\n")
+			}
+			end := min(start+outputWidth, len(chars))
+			fmt.Fprintf(&b, "%q", string(chars[start:end]))
+		}
+		b.WriteString(",\n")
+	}
+	b.WriteString("}\n")
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format output fail: %w", err)
+	}
+	return src, nil
+}
@@ -0,0 +1,96 @@
+package athlete
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 正規化後的性別
+const (
+	GenderMale   = "M"
+	GenderFemale = "F"
+)
+
+var (
+	ageReg = regexp.MustCompile(`\d+`)
+	hanReg = regexp.MustCompile(`^\p{Han}+$`)
+)
+
+// BirthRange 是由年份與年齡組推估的出生年 (民國) 範圍，0 代表該端未知
+type BirthRange struct {
+	Min int
+	Max int
+}
+
+// ParseBirthRange 由比賽年份 (民國) 與年齡組推估出生年，
+// 例如 114 年的 "11&12歲級" 為 102~103，"18及以上歲級" 為 ~96，無法判斷時回傳零值
+func ParseBirthRange(year, ageGroup string) BirthRange {
+	y, err := strconv.Atoi(strings.TrimSpace(year))
+	if err != nil || y <= 0 {
+		return BirthRange{}
+	}
+	ages := ageReg.FindAllString(ageGroup, -1)
+	if len(ages) == 0 {
+		return BirthRange{}
+	}
+	minAge, _ := strconv.Atoi(ages[0])
+	maxAge, _ := strconv.Atoi(ages[len(ages)-1])
+	switch {
+	case strings.Contains(ageGroup, "以上"):
+		return BirthRange{Max: y - minAge}
+	case strings.Contains(ageGroup, "以下"):
+		return BirthRange{Min: y - maxAge}
+	}
+	return BirthRange{Min: y - maxAge, Max: y - minAge}
+}
+
+// Overlaps 表示兩個範圍可能是同一個出生年，任一端未知時視為相容
+func (r BirthRange) Overlaps(o BirthRange) bool {
+	if r.Max != 0 && o.Min != 0 && o.Min > r.Max {
+		return false
+	}
+	if r.Min != 0 && o.Max != 0 && r.Min > o.Max {
+		return false
+	}
+	return true
+}
+
+// Bounded 表示範圍的上下限都已知，例如 "18及以上歲級" 只有上限，不足以判斷是否為同一人
+func (r BirthRange) Bounded() bool {
+	return r.Min != 0 && r.Max != 0
+}
+
+// Intersect 回傳兩個範圍的交集，用來隨著出賽紀錄縮小出生年範圍
+func (r BirthRange) Intersect(o BirthRange) BirthRange {
+	result := r
+	if o.Min != 0 && (result.Min == 0 || o.Min > result.Min) {
+		result.Min = o.Min
+	}
+	if o.Max != 0 && (result.Max == 0 || o.Max < result.Max) {
+		result.Max = o.Max
+	}
+	return result
+}
+
+// NormalizeGender 將組別的性別 (例如 "女子組") 轉成 M/F，混合或無法判斷時回傳空字串
+func NormalizeGender(gender string) string {
+	female := strings.Contains(gender, "女")
+	male := strings.Contains(gender, "男")
+	switch {
+	case female && !male:
+		return GenderFemale
+	case male && !female:
+		return GenderMale
+	}
+	return ""
+}
+
+// JoinName 將分開記錄的姓與名組成選手姓名：中文姓名為姓加名 (例如 "王小明")，其他為 "名 姓"
+func JoinName(last, first string) string {
+	last, first = strings.TrimSpace(last), strings.TrimSpace(first)
+	if first == "" || last == "" || (hanReg.MatchString(last) && hanReg.MatchString(first)) {
+		return last + first
+	}
+	return first + " " + last
+}
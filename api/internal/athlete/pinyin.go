@@ -0,0 +1,154 @@
+package athlete
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+//go:generate go run ./gen -o pinyin_table.go -cover ../crawler/test_file,../sheet/test_file,../lenex/test_file,../sdif/test_file
+
+const (
+	// maxZhuyinLength 是一個注音音節最多的符號數 (不含聲調)
+	maxZhuyinLength = 3
+	// zhuyinTones 是注音的聲調符號
+	zhuyinTones = "ˉˊˇˋ˙"
+)
+
+// compoundSurnames 是常見的複姓，拼音中名在前的寫法需要知道姓有幾個字
+var compoundSurnames = []string{"歐陽", "司馬", "司徒", "上官", "諸葛", "張簡", "范姜"}
+
+// pinyinReadings 是由 pinyinSyllables 轉成的字與讀音的對照
+var pinyinReadings = func() map[rune][]string {
+	readings := make(map[rune][]string)
+	for syllable, chars := range pinyinSyllables {
+		for _, char := range chars {
+			readings[char] = append(readings[char], syllable)
+		}
+	}
+	for _, syllables := range readings {
+		slices.Sort(syllables)
+	}
+	return readings
+}()
+
+// surnameLength 回傳中文姓名中姓的字數
+func surnameLength(name string) int {
+	for _, surname := range compoundSurnames {
+		if strings.HasPrefix(name, surname) && len([]rune(name)) > len([]rune(surname)) {
+			return len([]rune(surname))
+		}
+	}
+	return 1
+}
+
+// zhuyinInitials 是拼音聲母與注音的對照，兩個字母的聲母排在前面
+var zhuyinInitials = []struct{ pinyin, zhuyin string }{
+	{"zh", "ㄓ"}, {"ch", "ㄔ"}, {"sh", "ㄕ"},
+	{"b", "ㄅ"}, {"p", "ㄆ"}, {"m", "ㄇ"}, {"f", "ㄈ"}, {"d", "ㄉ"}, {"t", "ㄊ"}, {"n", "ㄋ"}, {"l", "ㄌ"},
+	{"g", "ㄍ"}, {"k", "ㄎ"}, {"h", "ㄏ"}, {"j", "ㄐ"}, {"q", "ㄑ"}, {"x", "ㄒ"},
+	{"r", "ㄖ"}, {"z", "ㄗ"}, {"c", "ㄘ"}, {"s", "ㄙ"},
+}
+
+// zhuyinFinals 是拼音韻母 (還原 y、w 與 iu、ui、un 等縮寫後) 與注音的對照
+var zhuyinFinals = map[string]string{
+	"": "", "a": "ㄚ", "o": "ㄛ", "e": "ㄜ", "ai": "ㄞ", "ei": "ㄟ", "ao": "ㄠ", "ou": "ㄡ",
+	"an": "ㄢ", "en": "ㄣ", "ang": "ㄤ", "eng": "ㄥ", "er": "ㄦ", "ong": "ㄨㄥ",
+	"i": "ㄧ", "ia": "ㄧㄚ", "ie": "ㄧㄝ", "iao": "ㄧㄠ", "iou": "ㄧㄡ", "ian": "ㄧㄢ",
+	"io": "ㄧㄛ", "in": "ㄧㄣ", "iang": "ㄧㄤ", "ing": "ㄧㄥ", "iong": "ㄩㄥ",
+	"u": "ㄨ", "ua": "ㄨㄚ", "uo": "ㄨㄛ", "uai": "ㄨㄞ", "uei": "ㄨㄟ", "uan": "ㄨㄢ",
+	"uen": "ㄨㄣ", "uang": "ㄨㄤ", "ueng": "ㄨㄥ",
+	"v": "ㄩ", "ve": "ㄩㄝ", "van": "ㄩㄢ", "vn": "ㄩㄣ",
+}
+
+// zhuyinSyllables 是由 pinyinSyllables 的音節轉成的注音與拼音的對照
+var zhuyinSyllables = func() map[string]string {
+	syllables := make(map[string]string)
+	for syllable := range pinyinSyllables {
+		if zhuyin := toZhuyin(syllable); zhuyin != "" {
+			syllables[zhuyin] = syllable
+		}
+	}
+	return syllables
+}()
+
+// toZhuyin 將無聲調的拼音音節 (ü 寫成 v) 轉成注音，無法轉換時回傳空字串
+func toZhuyin(syllable string) string {
+	initial, final := "", syllable
+	for _, pair := range zhuyinInitials {
+		if rest, ok := strings.CutPrefix(syllable, pair.pinyin); ok {
+			initial, final = pair.zhuyin, rest
+			break
+		}
+	}
+	switch {
+	case initial == "" && strings.HasPrefix(final, "yu"):
+		final = "v" + final[2:]
+	case initial == "" && (final == "yi" || final == "yin" || final == "ying"):
+		final = final[1:]
+	case initial == "" && strings.HasPrefix(final, "y"):
+		final = "i" + final[1:]
+	case initial == "" && final == "wu":
+		final = "u"
+	case initial == "" && strings.HasPrefix(final, "w"):
+		final = "u" + final[1:]
+	case strings.ContainsAny(initial, "ㄐㄑㄒ") && strings.HasPrefix(final, "u"):
+		final = "v" + final[1:]
+	case strings.ContainsAny(initial, "ㄓㄔㄕㄖㄗㄘㄙ") && final == "i":
+		// zhi、chi、shi、ri、zi、ci、si 在注音中沒有韻母
+		final = ""
+	}
+	switch final {
+	case "iu":
+		final = "iou"
+	case "ui":
+		final = "uei"
+	case "un":
+		final = "uen"
+	}
+	zhuyin, ok := zhuyinFinals[final]
+	if !ok || initial+zhuyin == "" {
+		return ""
+	}
+	return initial + zhuyin
+}
+
+// zhuyinToPinyin 將字串中的注音轉成拼音，每個音節取最長的符合；不成音節的聲母轉成拼音的聲母，
+// 聲調符號視為音節的分隔並去掉，其他字元不變
+func zhuyinToPinyin(s string) string {
+	initials := make(map[rune]string, len(zhuyinInitials))
+	for _, pair := range zhuyinInitials {
+		initials[[]rune(pair.zhuyin)[0]] = pair.pinyin
+	}
+	var b strings.Builder
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		if !unicode.Is(unicode.Bopomofo, runes[i]) {
+			if !strings.ContainsRune(zhuyinTones, runes[i]) {
+				b.WriteRune(runes[i])
+			}
+			i++
+			continue
+		}
+		n := 1
+		for ; n <= maxZhuyinLength && i+n <= len(runes); n++ {
+			if !unicode.Is(unicode.Bopomofo, runes[i+n-1]) {
+				break
+			}
+		}
+		matched := false
+		for n--; n > 0; n-- {
+			if syllable, ok := zhuyinSyllables[string(runes[i:i+n])]; ok {
+				b.WriteString(syllable)
+				i += n
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteString(initials[runes[i]])
+			i++
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,122 @@
+package athlete
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"aquascore/api/internal/db/mongo/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Store 是 Resolver 需要的選手資料存取，由 mongo.AthleteStore 實作
+type Store interface {
+	// FindAthletesByName 回傳姓名寫法包含 name 且尚未被合併的選手
+	FindAthletesByName(ctx context.Context, name string) ([]*models.Athlete, error)
+	// SaveAthlete 將 athlete 的姓名寫法與單位加入已儲存的選手 (不存在時新增)，並更新性別與出生年範圍；
+	// 不會移除已儲存的姓名寫法與單位，避免覆蓋同時進行的合併或拆分
+	SaveAthlete(ctx context.Context, athlete *models.Athlete) error
+}
+
+// Appearance 是選手在一筆成績中出現的資訊
+type Appearance struct {
+	Name     string // 選手姓名
+	Unit     string // 代表單位
+	Gender   string // 組別性別，例如 "女子組"
+	AgeGroup string // 年齡組，例如 "11&12歲級"
+	Year     string // 比賽年份 (民國)
+}
+
+// Resolver 將成績中的選手姓名連結到 athlete，沒有相符的選手時建立新的選手
+type Resolver struct {
+	store Store
+	// mu 讓同一個姓名不會因為同時爬取多個項目而建立重複的選手
+	mu sync.Mutex
+}
+
+func NewResolver(store Store) *Resolver {
+	return &Resolver{store: store}
+}
+
+// Resolve 回傳 appearance 所屬選手的 ID，並以這次出賽補充選手的單位、性別與出生年範圍
+func (r *Resolver) Resolve(ctx context.Context, appearance Appearance) (bson.ObjectID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	candidates, err := r.store.FindAthletesByName(ctx, appearance.Name)
+	if err != nil {
+		return bson.ObjectID{}, fmt.Errorf("find athletes of %s fail: %w", appearance.Name, err)
+	}
+	gender := NormalizeGender(appearance.Gender)
+	birth := ParseBirthRange(appearance.Year, appearance.AgeGroup)
+
+	athlete := Match(candidates, appearance.Unit, gender, birth)
+	if athlete == nil {
+		athlete = models.NewAthlete()
+		athlete.Name = appearance.Name
+		athlete.CreatedAt = time.Now()
+	} else if !needsUpdate(athlete, appearance.Unit, gender, birth) {
+		return athlete.ID, nil
+	}
+	// 只寫入這次出賽新增的資訊，store 以加入的方式更新姓名寫法與單位
+	update := &models.Athlete{
+		ID:        athlete.ID,
+		Name:      athlete.Name,
+		Names:     []string{appearance.Name},
+		Gender:    athlete.Gender,
+		CreatedAt: athlete.CreatedAt,
+		UpdatedAt: time.Now(),
+	}
+	if appearance.Unit != "" && !slices.Contains(athlete.Units, appearance.Unit) {
+		update.Units = []string{appearance.Unit}
+	}
+	if update.Gender == "" {
+		update.Gender = gender
+	}
+	known := BirthRange{Min: athlete.BirthYearMin, Max: athlete.BirthYearMax}.Intersect(birth)
+	update.BirthYearMin, update.BirthYearMax = known.Min, known.Max
+	if err := r.store.SaveAthlete(ctx, update); err != nil {
+		return bson.ObjectID{}, fmt.Errorf("save athlete %s fail: %w", appearance.Name, err)
+	}
+	return athlete.ID, nil
+}
+
+// Match 從同名的選手中找出這筆成績的選手：性別與出生年必須相容，
+// 優先選擇曾代表同一單位的選手；沒有同單位的選手時，只有唯一相容的選手，
+// 且這筆成績與該選手的出生年範圍都已知並重疊，才視為轉換單位的同一人，否則回傳 nil 代表應建立新的選手
+func Match(candidates []*models.Athlete, unit, gender string, birth BirthRange) *models.Athlete {
+	var compatible []*models.Athlete
+	for _, candidate := range candidates {
+		if candidate.Gender != "" && gender != "" && candidate.Gender != gender {
+			continue
+		}
+		if !birth.Overlaps(BirthRange{Min: candidate.BirthYearMin, Max: candidate.BirthYearMax}) {
+			continue
+		}
+		if unit != "" && slices.Contains(candidate.Units, unit) {
+			return candidate
+		}
+		compatible = append(compatible, candidate)
+	}
+	if len(compatible) != 1 || !birth.Bounded() {
+		return nil
+	}
+	candidate := compatible[0]
+	if !(BirthRange{Min: candidate.BirthYearMin, Max: candidate.BirthYearMax}).Bounded() {
+		return nil
+	}
+	return candidate
+}
+
+func needsUpdate(athlete *models.Athlete, unit, gender string, birth BirthRange) bool {
+	if unit != "" && !slices.Contains(athlete.Units, unit) {
+		return true
+	}
+	if athlete.Gender == "" && gender != "" {
+		return true
+	}
+	known := BirthRange{Min: athlete.BirthYearMin, Max: athlete.BirthYearMax}
+	return known.Intersect(birth) != known
+}
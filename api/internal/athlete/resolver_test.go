@@ -0,0 +1,173 @@
+package athlete
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestParseBirthRange(t *testing.T) {
+	assert.Equal(t, BirthRange{Min: 102, Max: 103}, ParseBirthRange("114", "11&12歲級"))
+	assert.Equal(t, BirthRange{Min: 104, Max: 104}, ParseBirthRange("114", "10歲級"))
+	assert.Equal(t, BirthRange{Max: 96}, ParseBirthRange("114", "18及以上歲級"))
+	assert.Equal(t, BirthRange{Min: 104}, ParseBirthRange("114", "10歲以下"))
+	assert.Equal(t, BirthRange{}, ParseBirthRange("114", "公開級"))
+	assert.Equal(t, BirthRange{}, ParseBirthRange("", "11&12歲級"))
+}
+
+func TestBirthRange(t *testing.T) {
+	r := BirthRange{Min: 102, Max: 103}
+	assert.True(t, r.Overlaps(BirthRange{Min: 103, Max: 104}))
+	assert.True(t, r.Overlaps(BirthRange{}))
+	assert.False(t, r.Overlaps(BirthRange{Min: 104, Max: 105}))
+	assert.False(t, r.Overlaps(BirthRange{Max: 101}))
+	assert.Equal(t, BirthRange{Min: 103, Max: 103}, r.Intersect(BirthRange{Min: 103, Max: 104}))
+	assert.Equal(t, r, r.Intersect(BirthRange{}))
+	assert.Equal(t, BirthRange{Min: 90, Max: 96}, BirthRange{Max: 96}.Intersect(BirthRange{Min: 90}))
+}
+
+func TestNormalizeGender(t *testing.T) {
+	assert.Equal(t, GenderFemale, NormalizeGender("女子組"))
+	assert.Equal(t, GenderMale, NormalizeGender("男子組"))
+	assert.Empty(t, NormalizeGender("男女混合組"))
+	assert.Empty(t, NormalizeGender(""))
+}
+
+func TestMatch(t *testing.T) {
+	a := &models.Athlete{Name: "王小明", Gender: GenderMale, Units: []string{"A國小"}, BirthYearMin: 102, BirthYearMax: 103}
+	b := &models.Athlete{Name: "王小明", Gender: GenderMale, Units: []string{"B國中"}, BirthYearMin: 96, BirthYearMax: 97}
+	c := &models.Athlete{Name: "王小明", Gender: GenderFemale, Units: []string{"A國小"}}
+
+	// 同單位優先
+	assert.Same(t, b, Match([]*models.Athlete{a, b, c}, "B國中", GenderMale, BirthRange{}))
+	// 性別不同的同名選手不會被比對到
+	assert.Same(t, a, Match([]*models.Athlete{a, c}, "A國小", GenderMale, BirthRange{}))
+	// 換單位但出生年只與其中一位相容
+	assert.Same(t, a, Match([]*models.Athlete{a, b}, "C國中", GenderMale, ParseBirthRange("115", "13&14歲級")))
+	// 換單位且無法區分時建立新的選手
+	assert.Nil(t, Match([]*models.Athlete{a, b}, "C國中", GenderMale, BirthRange{}))
+	// 換單位時唯一的同名選手也需要出生年範圍已知且重疊
+	assert.Nil(t, Match([]*models.Athlete{a}, "C國中", GenderMale, BirthRange{}))
+	assert.Nil(t, Match([]*models.Athlete{a}, "C國中", GenderMale, ParseBirthRange("115", "13及以上歲級")))
+	assert.Nil(t, Match([]*models.Athlete{c}, "B國中", GenderFemale, ParseBirthRange("115", "13&14歲級")))
+	// 出生年不相容時即使同單位也不是同一人
+	assert.Nil(t, Match([]*models.Athlete{a}, "A國小", GenderMale, ParseBirthRange("114", "18及以上歲級")))
+	assert.Nil(t, Match(nil, "A國小", GenderMale, BirthRange{}))
+}
+
+type memoryStore struct {
+	athletes []*models.Athlete
+	saved    int
+	last     *models.Athlete // 最後一次 SaveAthlete 收到的資料
+}
+
+func (m *memoryStore) FindAthletesByName(_ context.Context, name string) ([]*models.Athlete, error) {
+	var result []*models.Athlete
+	for _, a := range m.athletes {
+		for _, n := range a.Names {
+			if n == name {
+				result = append(result, a)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryStore) SaveAthlete(_ context.Context, athlete *models.Athlete) error {
+	m.saved++
+	m.last = athlete
+	for _, a := range m.athletes {
+		if a.ID == athlete.ID {
+			for _, name := range athlete.Names {
+				if !slices.Contains(a.Names, name) {
+					a.Names = append(a.Names, name)
+				}
+			}
+			for _, unit := range athlete.Units {
+				if !slices.Contains(a.Units, unit) {
+					a.Units = append(a.Units, unit)
+				}
+			}
+			a.Gender = athlete.Gender
+			a.BirthYearMin, a.BirthYearMax = athlete.BirthYearMin, athlete.BirthYearMax
+			return nil
+		}
+	}
+	m.athletes = append(m.athletes, athlete)
+	return nil
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	store := &memoryStore{}
+	r := NewResolver(store)
+	ctx := context.Background()
+
+	first, err := r.Resolve(ctx, Appearance{
+		Name: "林小美", Unit: "A國小", Gender: "女子組", AgeGroup: "11&12歲級", Year: "113",
+	})
+	require.NoError(t, err)
+	require.Len(t, store.athletes, 1)
+	assert.Equal(t, GenderFemale, store.athletes[0].Gender)
+	assert.Equal(t, 101, store.athletes[0].BirthYearMin)
+	assert.Equal(t, 102, store.athletes[0].BirthYearMax)
+
+	// 隔年換到國中，年齡組連續所以是同一人，出生年範圍縮小
+	second, err := r.Resolve(ctx, Appearance{
+		Name: "林小美", Unit: "B國中", Gender: "女子組", AgeGroup: "13歲級", Year: "114",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, []string{"A國小", "B國中"}, store.athletes[0].Units)
+	assert.Equal(t, 101, store.athletes[0].BirthYearMin)
+	assert.Equal(t, 101, store.athletes[0].BirthYearMax)
+
+	// 沒有新資訊時不寫入
+	saved := store.saved
+	again, err := r.Resolve(ctx, Appearance{Name: "林小美", Unit: "B國中", Gender: "女子組", Year: "114"})
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+	assert.Equal(t, saved, store.saved)
+
+	// 出生年不相容的同名選手建立新的 athlete
+	other, err := r.Resolve(ctx, Appearance{
+		Name: "林小美", Unit: "C高中", Gender: "女子組", AgeGroup: "18及以上歲級", Year: "114",
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, first, other)
+	assert.NotEqual(t, bson.ObjectID{}, other)
+	assert.Len(t, store.athletes, 2)
+
+	// 換單位但沒有年齡組，無法確認是同一人時建立新的 athlete
+	unknown, err := r.Resolve(ctx, Appearance{Name: "林小美", Unit: "D高中", Gender: "女子組", Year: "114"})
+	require.NoError(t, err)
+	assert.NotEqual(t, first, unknown)
+	assert.NotEqual(t, other, unknown)
+	assert.Len(t, store.athletes, 3)
+}
+
+func TestResolver_Resolve_savesOnlyNewInfo(t *testing.T) {
+	store := &memoryStore{}
+	r := NewResolver(store)
+	ctx := context.Background()
+
+	id, err := r.Resolve(ctx, Appearance{
+		Name: "陳大文", Unit: "A國小", Gender: "男子組", AgeGroup: "11&12歲級", Year: "113",
+	})
+	require.NoError(t, err)
+	_, err = r.Resolve(ctx, Appearance{
+		Name: "陳大文", Unit: "B國中", Gender: "男子組", AgeGroup: "13歲級", Year: "114",
+	})
+	require.NoError(t, err)
+	// 只送出這次出賽新增的單位，不會把拆分時移除的單位加回去
+	assert.Equal(t, id, store.last.ID)
+	assert.Equal(t, []string{"B國中"}, store.last.Units)
+	assert.Equal(t, []string{"陳大文"}, store.last.Names)
+	assert.Equal(t, []string{"A國小", "B國中"}, store.athletes[0].Units)
+}
@@ -0,0 +1,148 @@
+package athlete
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxRomanizations 是多音字組合出的拼音寫法上限
+	maxRomanizations = 4
+	// latinGramSize 是英文與拼音切 n-gram 的長度，較短的字串以開頭比對
+	latinGramSize = 3
+	// typoGrams 是打錯一個字最多影響的 n-gram 數：中文的該字與前後兩個兩字，或英文包含該字母的三個三字母
+	typoGrams = 3
+	// minTypoQueryGrams 是容許打錯一個字的搜尋字串最少的 n-gram 數，即三個字的中文或七個字母的英文
+	minTypoQueryGrams = typoGrams + 2
+)
+
+// NormalizeSearch 將姓名或搜尋字串轉成比對用的寫法：英文轉小寫，注音轉成拼音，去掉空白與標點
+func NormalizeSearch(s string) string {
+	if strings.ContainsFunc(s, func(r rune) bool { return unicode.Is(unicode.Bopomofo, r) }) {
+		s = zhuyinToPinyin(s)
+	}
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// SearchNames 回傳搜尋選手時比對的字串：正規化的姓名寫法，以及中文姓名的拼音 (姓在前與名在前) 與拼音首字母，
+// 例如 "王小明" 為 "王小明"、"wangxiaoming"、"xiaomingwang" 與 "wxm"。有字不在拼音表中的姓名不產生拼音
+func SearchNames(names []string) []string {
+	var result []string
+	add := func(s string) {
+		if s != "" && !slices.Contains(result, s) {
+			result = append(result, s)
+		}
+	}
+	for _, name := range names {
+		normalized := NormalizeSearch(name)
+		add(normalized)
+		surname := surnameLength(normalized)
+		for _, syllables := range romanize(normalized) {
+			var initials strings.Builder
+			for _, syllable := range syllables {
+				initials.WriteByte(syllable[0])
+			}
+			add(strings.Join(syllables, ""))
+			add(strings.Join(syllables[surname:], "") + strings.Join(syllables[:surname], ""))
+			add(initials.String())
+		}
+	}
+	return result
+}
+
+// SearchGrams 回傳 SearchNames 的 n-gram，存在選手資料上作為搜尋的索引：
+// 中文取每一個字與相鄰兩個字，英文與拼音取開頭的一、兩個字母與每相鄰三個字母
+func SearchGrams(searchNames []string) []string {
+	var grams []string
+	for _, name := range searchNames {
+		runes := []rune(name)
+		if !hasHan(runes) {
+			for n := 1; n < latinGramSize && n <= len(runes); n++ {
+				grams = append(grams, string(runes[:n]))
+			}
+		}
+		grams = append(grams, nGrams(runes)...)
+	}
+	slices.Sort(grams)
+	return slices.Compact(grams)
+}
+
+// QueryGrams 回傳正規化後搜尋字串的 n-gram，切法與 SearchGrams 相同，
+// 英文短於三個字母時以整個字串比對姓名開頭
+func QueryGrams(query string) []string {
+	runes := []rune(query)
+	if !hasHan(runes) && len(runes) < latinGramSize {
+		if len(runes) == 0 {
+			return nil
+		}
+		return []string{query}
+	}
+	grams := nGrams(runes)
+	slices.Sort(grams)
+	return slices.Compact(grams)
+}
+
+// MinFuzzyGrams 是模糊比對時搜尋字串的 n-gram 至少要有幾個出現在選手的 n-gram 中。
+// n-gram 夠多時容許打錯一個字 (例如三個字的中文姓名錯一個字時，五個 n-gram 至少還有兩個相同)；
+// 兩個字的中文或六個字母以下的英文錯一個字後可能只剩一個相同的 n-gram，同姓或包含同樣三個字母的選手都會符合，
+// 因此需要全部相同，不做模糊比對
+func MinFuzzyGrams(queryGrams []string) int {
+	if len(queryGrams) < minTypoQueryGrams {
+		return len(queryGrams)
+	}
+	return len(queryGrams) - typoGrams
+}
+
+// nGrams 切出中文的單字與兩字，或英文每相鄰三個字母
+func nGrams(runes []rune) []string {
+	var grams []string
+	if hasHan(runes) {
+		for i := range runes {
+			grams = append(grams, string(runes[i]))
+			if i+1 < len(runes) {
+				grams = append(grams, string(runes[i:i+2]))
+			}
+		}
+		return grams
+	}
+	for i := 0; i+latinGramSize <= len(runes); i++ {
+		grams = append(grams, string(runes[i:i+latinGramSize]))
+	}
+	return grams
+}
+
+func hasHan(runes []rune) bool {
+	return slices.ContainsFunc(runes, func(r rune) bool { return unicode.Is(unicode.Han, r) })
+}
+
+// romanize 回傳中文姓名每個字的拼音，多音字展開成多種寫法 (最多 maxRomanizations 種)，
+// 不是中文或有字不在拼音表中時回傳 nil
+func romanize(name string) [][]string {
+	if !hanReg.MatchString(name) {
+		return nil
+	}
+	combos := [][]string{{}}
+	for _, char := range name {
+		readings, ok := pinyinReadings[char]
+		if !ok {
+			return nil
+		}
+		next := make([][]string, 0, len(combos)*len(readings))
+		for _, combo := range combos {
+			for _, reading := range readings {
+				if len(next) < maxRomanizations {
+					next = append(next, append(slices.Clone(combo), reading))
+				}
+			}
+		}
+		combos = next
+	}
+	return combos
+}
@@ -0,0 +1,96 @@
+package athlete
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeSearch(t *testing.T) {
+	assert.Equal(t, "王小明", NormalizeSearch(" 王 小明 "))
+	assert.Equal(t, "xiaomingwang", NormalizeSearch("Xiao-Ming Wang"))
+	assert.Equal(t, "安娜李", NormalizeSearch("安娜．李"))
+	// 注音轉成拼音，聲調符號視為分隔，不成音節的聲母轉成拼音的聲母
+	assert.Equal(t, "wangxiaoming", NormalizeSearch("ㄨㄤˊ ㄒㄧㄠˇ ㄇㄧㄥˊ"))
+	assert.Equal(t, "wangx", NormalizeSearch("ㄨㄤˊㄒ"))
+	assert.Equal(t, "xiaoming", NormalizeSearch("ㄒㄧㄠㄇㄧㄥ"))
+}
+
+func TestToZhuyin(t *testing.T) {
+	cases := map[string]string{
+		"wang": "ㄨㄤ", "xiao": "ㄒㄧㄠ", "ming": "ㄇㄧㄥ", "yi": "ㄧ", "yu": "ㄩ", "yuan": "ㄩㄢ",
+		"you": "ㄧㄡ", "yong": "ㄩㄥ", "wei": "ㄨㄟ", "wu": "ㄨ", "qiu": "ㄑㄧㄡ", "gui": "ㄍㄨㄟ",
+		"lun": "ㄌㄨㄣ", "jun": "ㄐㄩㄣ", "xue": "ㄒㄩㄝ", "lv": "ㄌㄩ", "zhi": "ㄓ", "si": "ㄙ",
+		"zhuang": "ㄓㄨㄤ", "dong": "ㄉㄨㄥ", "weng": "ㄨㄥ",
+	}
+	for pinyin, zhuyin := range cases {
+		assert.Equal(t, zhuyin, toZhuyin(pinyin), pinyin)
+	}
+	// 表中的每個音節都能轉成注音，且不會互相衝突
+	assert.Len(t, zhuyinSyllables, len(pinyinSyllables))
+}
+
+func TestSearchNames(t *testing.T) {
+	assert.Equal(t, []string{"王小明", "wangxiaoming", "xiaomingwang", "wxm"}, SearchNames([]string{"王小明"}))
+	// 多音字展開，複姓的名在前寫法
+	assert.Equal(t, []string{
+		"單雅", "danya", "yadan", "dy", "shanya", "yashan", "sy",
+	}, SearchNames([]string{"單雅"}))
+	assert.Contains(t, SearchNames([]string{"歐陽娜娜"}), "nanaouyang")
+	// 不在拼音表中的字不產生拼音，英文姓名只正規化
+	assert.Equal(t, []string{"王𠀋"}, SearchNames([]string{"王𠀋"}))
+	assert.Equal(t, []string{"emmachen"}, SearchNames([]string{"Emma Chen"}))
+}
+
+func TestSearchGrams(t *testing.T) {
+	assert.Equal(t, []string{"w", "wx", "wxm", "x", "xm", "xmw", "小", "小明", "明", "王", "王小"},
+		SearchGrams([]string{"王小明", "wxm", "xmw"}))
+}
+
+func TestQueryGrams(t *testing.T) {
+	assert.Equal(t, []string{"小", "小明", "明"}, QueryGrams("小明"))
+	assert.Equal(t, []string{"wx"}, QueryGrams("wx"))
+	assert.Equal(t, []string{"ang", "ngx", "wan"}, QueryGrams("wangx"))
+	assert.Empty(t, QueryGrams(""))
+}
+
+// 三個字以上的中文與七個字母以上的英文打錯一個字時仍有足夠的 n-gram 相同，較短的搜尋字串不做模糊比對
+func TestMinFuzzyGrams(t *testing.T) {
+	matches := func(name, query string) bool {
+		nameGrams := SearchGrams(SearchNames([]string{name}))
+		grams := QueryGrams(NormalizeSearch(query))
+		matched := 0
+		for _, gram := range grams {
+			if slices.Contains(nameGrams, gram) {
+				matched++
+			}
+		}
+		return matched >= MinFuzzyGrams(grams)
+	}
+	typos := map[string][]string{
+		"王小明":          {"王曉明", "黃小明", "王小民"},
+		"wangxiaoming": {"wangxiaomin", "wengxiaoming", "wangxaoming"},
+	}
+	for name, queries := range typos {
+		for _, query := range queries {
+			assert.True(t, matches(name, query), "%s -> %s", query, name)
+		}
+	}
+	// 只有一個字或三個字母相同的選手不符合
+	unrelated := map[string][]string{
+		"王小明":       {"王明", "小名"},
+		"王大明":       {"王明"},
+		"Ohnson Li": {"john"},
+		"Johan Wu":  {"john"},
+	}
+	for name, queries := range unrelated {
+		for _, query := range queries {
+			assert.False(t, matches(name, query), "%s -> %s", query, name)
+		}
+	}
+	assert.Equal(t, 2, MinFuzzyGrams(QueryGrams("王小明")))
+	assert.Equal(t, 3, MinFuzzyGrams(QueryGrams("王明")))
+	assert.Equal(t, 2, MinFuzzyGrams(QueryGrams("john")))
+	assert.Equal(t, 1, MinFuzzyGrams(QueryGrams("王")))
+}
@@ -1,20 +1,53 @@
 package crawler
 
-import "time"
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
 
 type Race struct {
+	Source          string // 成績來源名稱
 	Organizer       string
+	Venue           string // 比賽場地，來源沒有提供時為空
 	Year            string
 	Type            string
 	CompetitionName string
+	CompetitionID   string // 來源網站上的比賽 ID，見 CompetitionInfo
 	Gender          string
+	PoolType        string // 水道，見 PoolTypeShortCourse/PoolTypeLongCourse
 	AgeGroup        string
 	EventType       string
 	EventName       string
+	Event           Event // 由 EventName 解析出的結構化項目
 	GamesRecord     time.Duration
 	NationalRecord  time.Duration
 	Time            time.Time
 	Results         []*RaceResult
+	Unparsed        []UnparsedRow // 無法解析而略過的成績列
+}
+
+// UnparsedRow 是成績報告中無法解析而略過的一列
+type UnparsedRow struct {
+	Row    int    // 成績表中的列序 (從 1 開始)，0 代表表頭的紀錄欄位
+	Text   string // 原始文字
+	Reason string
+}
+
+func (r UnparsedRow) String() string {
+	if r.Row == 0 {
+		return fmt.Sprintf("表頭 %q: %s", r.Text, r.Reason)
+	}
+	return fmt.Sprintf("第 %d 列 %q: %s", r.Row, r.Text, r.Reason)
 }
 
 type RaceResult struct {
@@ -24,17 +57,385 @@ type RaceResult struct {
 	Rank   int32
 	Score  int32
 	Note   string
+	// Status 由成績欄位與備註解析出的成績狀態，StatusReason 是狀態的說明 (例如犯規原因)
+	Status       ResultStatus
+	StatusReason string
+	Team         string     // 接力隊伍名稱，個人項目為空
+	Legs         []RelayLeg // 接力各棒，個人項目為 nil
+	// Splits 是依距離排列的累計分段，來源沒有提供時為 nil
+	Splits       []Split
+	ReactionTime time.Duration // 出發反應時間，來源沒有提供時為 0
+	DetailURL    string        // 詳細成績頁 (分段、反應時間) 的絕對 URL，沒有連結時為空
 }
 
 type Persistence interface {
-	PersistRace(race *Race) error
-	CrawlLog(url string) error
+	// PersistRace 儲存一個項目的成績並記錄 url 已爬取，同一個項目重複儲存會覆寫而不會重複
+	PersistRace(url string, race *Race) error
 	IsCrawled(url string) (bool, error)
 }
 
-func sendNonBlockingError(err error, errChan chan error) {
-	select {
-	case errChan <- err:
-	default:
+// ArchivedPage 是一份抓取到的原始成績報告
+type ArchivedPage struct {
+	Source    string
+	Info      RaceInfo
+	Year      string
+	Hash      string // 內容的 sha256 (hex)
+	Body      []byte
+	FetchedAt time.Time
+}
+
+// Archive 保存抓取到的原始成績報告，解析器有問題時可以不必重新爬取來源網站
+type Archive interface {
+	ArchivePage(page *ArchivedPage) error
+}
+
+// CompetitionInfo 是成績來源網站上的一場比賽 (例如 CTSA 的一個活動)
+type CompetitionInfo struct {
+	ID   string
+	Name string
+}
+
+type RaceInfo struct {
+	CompetitionID   string // 來源網站上的比賽 ID，例如 CTSA 的活動 ID
+	CompetitionName string // 例如：114年全國南區(1)游泳錦標賽
+	RaceName        string // 例如：11 & 12歲級女子組游泳 200公尺自由式 計時決賽
+	URL             string // 成績報告的絕對 URL 連結
+}
+
+var competitionYearReg = regexp.MustCompile(`^(\d+)年`)
+
+// Year 回傳比賽名稱開頭的民國年，例如 "114年全國南區(1)游泳錦標賽" 回傳 "114"
+func (info *RaceInfo) Year() string {
+	const expectMatchSize = 2
+	matches := competitionYearReg.FindStringSubmatch(strings.ReplaceAll(info.CompetitionName, " ", ""))
+	if len(matches) < expectMatchSize {
+		return ""
+	}
+	return matches[1]
+}
+
+// Source 是一個成績來源 (泳協、賽會管理系統...)，由 Crawler 負責走訪與儲存
+type Source interface {
+	// Name 回傳來源註冊時的名稱
+	Name() string
+	// ListCompetitions 列出來源上所有的比賽
+	ListCompetitions(ctx context.Context) ([]CompetitionInfo, error)
+	// ListRaces 列出單一比賽底下的所有項目
+	ListRaces(ctx context.Context, competition CompetitionInfo) ([]RaceInfo, error)
+	// FetchRace 下載並解析單一項目的成績
+	FetchRace(ctx context.Context, info RaceInfo) (*Race, error)
+}
+
+// RaceParser 將一份原始成績報告解析成 Race，重新解析封存資料時使用
+type RaceParser interface {
+	ParseRace(info RaceInfo, body io.Reader) (*Race, error)
+}
+
+// RawSource 是可以把下載與解析分開的 Source，Crawler 會在解析前先封存原始內容
+type RawSource interface {
+	Source
+	RaceParser
+	FetchPage(ctx context.Context, info RaceInfo) ([]byte, error)
+}
+
+// ParseArchivedPage 以目前版本的解析器重新解析一份封存的成績報告
+func ParseArchivedPage(page *ArchivedPage) (*Race, error) {
+	source, err := NewSource(page.Source)
+	if err != nil {
+		return nil, err
+	}
+	parser, ok := source.(RaceParser)
+	if !ok {
+		return nil, fmt.Errorf("source %s does not support parsing archived pages", page.Source)
+	}
+	race, err := parser.ParseRace(page.Info, bytes.NewReader(page.Body))
+	if err != nil {
+		return nil, err
+	}
+	race.Source = page.Source
+	return race, nil
+}
+
+// Crawler 走訪 Source 上的所有項目，並將尚未爬取過的成績交給 Persistence 儲存
+type Crawler struct {
+	source      Source
+	year        string
+	persistence Persistence
+	archive     Archive
+	jobStore    JobStore
+	recheck     time.Duration
+	poolTypes   *PoolTypeResolver
+}
+
+func New(source Source, opts ...Option) (*Crawler, error) {
+	if source == nil {
+		return nil, errors.New("source is nil")
+	}
+	o := newOptions(opts...)
+	if o.persistence == nil {
+		return nil, errors.New("persistence is nil")
+	}
+	if _, ok := o.persistence.(RaceUpdater); o.recheck > 0 && !ok {
+		return nil, errors.New("persistence does not support recheck")
+	}
+	return &Crawler{
+		source:      source,
+		year:        o.year,
+		persistence: o.persistence,
+		archive:     o.archive,
+		jobStore:    o.jobStore,
+		recheck:     o.recheck,
+		poolTypes:   o.poolTypes,
+	}, nil
+}
+
+// Crawl 建立一個新的爬取工作並走訪來源上的所有比賽
+func (c *Crawler) Crawl(ctx context.Context) error {
+	job := &Job{
+		Source:    c.source.Name(),
+		Year:      c.year,
+		Status:    JobStatusRunning,
+		StartedAt: time.Now(),
+	}
+	competitions, err := c.source.ListCompetitions(ctx)
+	if err == nil && len(competitions) == 0 {
+		err = errors.New("未能成功獲取任何比賽 ID")
+	}
+	if err != nil {
+		job.Status = JobStatusFailed
+		job.Error = err.Error()
+		job.FinishedAt = time.Now()
+		c.saveJob(job)
+		return fmt.Errorf("取得比賽列表失敗: %w", err)
+	}
+	for _, competition := range competitions {
+		job.Competitions = append(job.Competitions, &JobCompetition{
+			Competition: competition,
+			Status:      JobStatusPending,
+		})
+	}
+	c.saveJob(job)
+	fmt.Printf("✅ 成功找到 %d 個比賽 ID (工作 %s)，開始逐一 POST 請求...\n", len(competitions), job.ID)
+	fmt.Println("---------------------------------------------------------")
+	return c.run(ctx, job)
+}
+
+// Resume 重新執行一個既有的爬取工作，只會重試失敗或尚未完成的項目
+func (c *Crawler) Resume(ctx context.Context, jobID string) error {
+	if c.jobStore == nil {
+		return errors.New("job store is nil")
+	}
+	job, err := c.jobStore.GetJob(jobID)
+	if err != nil {
+		return fmt.Errorf("get job %s fail: %w", jobID, err)
+	}
+	if job.Source != c.source.Name() {
+		return fmt.Errorf("job %s belongs to source %s, not %s", jobID, job.Source, c.source.Name())
+	}
+	job.Status = JobStatusRunning
+	job.Error = ""
+	job.FinishedAt = time.Time{}
+	progress := job.Progress()
+	fmt.Printf("✅ 繼續工作 %s，剩餘 %d 個比賽、%d 個失敗項目\n", job.ID,
+		len(job.Competitions)-progress.Competitions[JobStatusDone], progress.Races[JobStatusFailed])
+	return c.run(ctx, job)
+}
+
+func (c *Crawler) run(ctx context.Context, job *Job) error {
+	job.Attempts++
+	for _, competition := range job.Competitions {
+		if isFinished(competition.Status) {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		c.crawlCompetition(ctx, competition)
+		if competition.Status == JobStatusDone {
+			log.Printf("✅ POST 請求成功: %s", competition.Competition.Name)
+		} else {
+			log.Printf("❌ 比賽未完成: %s %s", competition.Competition.Name, competition.Error)
+		}
+		c.saveJob(job)
+	}
+
+	job.FinishedAt = time.Now()
+	progress := job.Progress()
+	if unfinished := len(job.Competitions) - progress.Competitions[JobStatusDone]; unfinished > 0 {
+		job.Status = JobStatusFailed
+		job.Error = fmt.Sprintf("%d competitions and %d races failed", unfinished, progress.Races[JobStatusFailed])
+		c.saveJob(job)
+		return fmt.Errorf("%w: job %s: %s", ErrIncomplete, job.ID, job.Error)
+	}
+	job.Status = JobStatusDone
+	c.saveJob(job)
+	return nil
+}
+
+func (c *Crawler) saveJob(job *Job) {
+	if c.jobStore == nil {
+		return
+	}
+	if err := c.jobStore.SaveJob(job); err != nil {
+		log.Printf("⚠️ 儲存工作進度失敗: %v", err)
+	}
+}
+
+func (c *Crawler) crawlCompetition(ctx context.Context, competition *JobCompetition) {
+	competition.Attempts++
+	// 沒有任何項目代表尚未 (或未能) 取得項目列表
+	if len(competition.Races) == 0 {
+		races, err := c.source.ListRaces(ctx, competition.Competition)
+		if err != nil {
+			competition.fail(err)
+			return
+		}
+		for _, race := range races {
+			competition.Races = append(competition.Races, &JobRace{Race: race, Status: JobStatusPending})
+		}
+	}
+	// 舊版的工作進度沒有記錄比賽 ID
+	for _, race := range competition.Races {
+		if race.Race.CompetitionID == "" {
+			race.Race.CompetitionID = competition.Competition.ID
+		}
+	}
+	c.processRaces(ctx, competition.Races)
+	competition.finish()
+}
+
+func (c *Crawler) processRaces(ctx context.Context, races []*JobRace) {
+	const maxConcurrency = 5
+	semaphore := make(chan struct{}, maxConcurrency)
+	var wg sync.WaitGroup
+
+	for _, race := range races {
+		if isFinished(race.Status) {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			c.processSingleRace(ctx, race)
+		}()
+	}
+	wg.Wait()
+}
+
+func (c *Crawler) processSingleRace(ctx context.Context, jobRace *JobRace) {
+	race := jobRace.Race
+	jobRace.Attempts++
+
+	ok, err := c.persistence.IsCrawled(race.URL)
+	if err != nil {
+		jobRace.fail(fmt.Errorf("check crawled fail: %w", err))
+		return
+	}
+	if ok {
+		if c.recheck > 0 {
+			c.recheckRace(ctx, jobRace)
+			return
+		}
+		jobRace.finish(JobStatusSkipped)
+		return
+	}
+	dbrace, err := c.fetchRace(ctx, race)
+	if err != nil {
+		jobRace.fail(fmt.Errorf("generate race %s [%s] fail: %w", race.CompetitionName, race.RaceName, err))
+		return
+	}
+	err = c.persistence.PersistRace(race.URL, dbrace)
+	if err != nil {
+		jobRace.fail(fmt.Errorf("persistence race fail: %w", err))
+		return
+	}
+	jobRace.setUnparsed(dbrace.Unparsed)
+	jobRace.finish(JobStatusDone)
+}
+
+// recheckRace 重新抓取近期比賽的項目，比對後套用來源網站的更正。
+// 以比賽日期而非爬取時間判斷是否為近期，重新爬取舊的比賽不會觸發重新檢查；找不到比賽日期時略過
+func (c *Crawler) recheckRace(ctx context.Context, jobRace *JobRace) {
+	race := jobRace.Race
+	updater, _ := c.persistence.(RaceUpdater)
+	raceDate, err := updater.RaceDate(race.URL)
+	if err != nil {
+		jobRace.fail(fmt.Errorf("get race date fail: %w", err))
+		return
+	}
+	if raceDate.IsZero() || raceDate.Before(time.Now().Add(-c.recheck)) {
+		jobRace.finish(JobStatusSkipped)
+		return
+	}
+	dbrace, err := c.fetchRace(ctx, race)
+	if err != nil {
+		jobRace.fail(fmt.Errorf("generate race %s [%s] fail: %w", race.CompetitionName, race.RaceName, err))
+		return
+	}
+	changes, err := updater.UpdateRace(race.URL, dbrace)
+	if err != nil {
+		jobRace.fail(fmt.Errorf("update race fail: %w", err))
+		return
+	}
+	if len(changes) > 0 {
+		log.Printf("🔁 %s [%s] 發現 %d 筆更正", race.CompetitionName, race.RaceName, len(changes))
+	}
+	jobRace.setUnparsed(dbrace.Unparsed)
+	jobRace.finish(JobStatusDone)
+}
+
+// fetchRace 下載並解析單一項目，若有設定 Archive 且來源支援，會先封存原始內容
+func (c *Crawler) fetchRace(ctx context.Context, info RaceInfo) (*Race, error) {
+	race, err := c.fetchAndParseRace(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	if race.Source == "" {
+		race.Source = c.source.Name()
+	}
+	if race.CompetitionID == "" {
+		race.CompetitionID = info.CompetitionID
+	}
+	LogUnparsedRows(race)
+	FetchResultDetails(ctx, c.source, race)
+	if race.PoolType == "" {
+		race.PoolType = c.poolTypes.Resolve(race.CompetitionName)
+	}
+	return race, nil
+}
+
+// LogUnparsedRows 記錄項目中無法解析而略過的列
+func LogUnparsedRows(race *Race) {
+	for _, row := range race.Unparsed {
+		log.Printf("⚠️ %s [%s] 無法解析 %s", race.CompetitionName, race.EventName, row)
+	}
+}
+
+func (c *Crawler) fetchAndParseRace(ctx context.Context, info RaceInfo) (*Race, error) {
+	raw, ok := c.source.(RawSource)
+	if !ok || c.archive == nil {
+		return c.source.FetchRace(ctx, info)
+	}
+	body, err := raw.FetchPage(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(body)
+	err = c.archive.ArchivePage(&ArchivedPage{
+		Source:    c.source.Name(),
+		Info:      info,
+		Year:      info.Year(),
+		Hash:      hex.EncodeToString(sum[:]),
+		Body:      body,
+		FetchedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("archive page fail: %w", err)
 	}
+	return raw.ParseRace(info, bytes.NewReader(body))
 }
@@ -6,14 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/antchfx/htmlquery"
@@ -21,10 +19,11 @@ import (
 )
 
 const (
-	apiSleepDuration             = 100 * time.Millisecond
-	defaultDelay                 = time.Second * 5
+	ctsaSourceName               = "ctsa"
+	ctsaCurrentURL               = "https://ctsa.utk.com.tw/CTSA/public/race/game_data.aspx"
+	ctsaYearURLFormat            = "https://ctsa.utk.com.tw/CTSA_%s/public/race/game_data.aspx"
+	ctsaFirstYear                = 100 // 探測封存年度網站的起始民國年
 	notApplicable                = "N/A"
-	expectedTimeSplitParts       = 2
 	expectedDateRegexMatchGroups = 2
 	maxDateSplitLimit            = 3
 	expectedDateSplitParts       = 3
@@ -33,19 +32,31 @@ const (
 	minAgeGenderRegexMatches     = 2
 )
 
-func NewCtsaCrawler(opts ...Option) (*ctsaCrawler, error) {
-	crawler := &ctsaCrawler{}
-	var err error
-	for _, opt := range opts {
-		opt(crawler)
+var (
+	gameRecordReg     = regexp.MustCompile(`大會紀錄[：:]\s*([0-9０-９:：.．]+)`)
+	nationalRecordReg = regexp.MustCompile(`全國紀錄[：:]\s*([0-9０-９:：.．]+)`)
+)
+
+func init() {
+	RegisterSource(ctsaSourceName, func(opts ...Option) (Source, error) {
+		return newCtsaSource(opts...)
+	})
+}
+
+func newCtsaSource(opts ...Option) (*ctsaSource, error) {
+	o := newOptions(opts...)
+	source := &ctsaSource{
+		baseUrl:         o.baseURL,
+		yearURLFormat:   ctsaYearURLFormat,
+		mockGetResponse: o.mockGetResponse,
 	}
-	if crawler.persistence == nil {
-		return nil, errors.New("persistence is nil")
+	if source.baseUrl == "" {
+		source.baseUrl = ctsaURL(o.year)
 	}
 
 	jar, err := cookiejar.New(nil)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("init cookie jar fail: %w", err)
 	}
 
 	// 2. 創建一個帶有 Jar 的 http.Client
@@ -53,84 +64,41 @@ func NewCtsaCrawler(opts ...Option) (*ctsaCrawler, error) {
 	client := &http.Client{
 		Jar: jar, // 將 Jar 設置給 Client
 	}
-	crawler.client = client
-	return crawler, nil
+	source.fetcher = newFetcher(client, o.retry, o.rateLimiter())
+	return source, nil
+}
+
+// ctsaURL 回傳 CTSA 指定年度 (民國年) 的賽事查詢頁，空字串代表當前年度
+func ctsaURL(year string) string {
+	if year == "" {
+		return ctsaCurrentURL
+	}
+	return fmt.Sprintf(ctsaYearURLFormat, year)
 }
 
-func (c *ctsaCrawler) getResponse(ctx context.Context, url string) (io.Reader, error) {
+func (c *ctsaSource) getResponse(ctx context.Context, url string) (io.Reader, error) {
 	if c.mockGetResponse != nil {
 		return c.mockGetResponse(url)
 	}
-	myctx, cancel := context.WithTimeout(ctx, defaultDelay)
-	defer cancel()
-	req, err := http.NewRequestWithContext(myctx, "GET", url, nil)
+	body, err := c.fetcher.Get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GET 請求返回非預期狀態碼: %d %s", resp.StatusCode, resp.Status)
-	}
-	// 建立一個記憶體暫存區
-	var buf bytes.Buffer
-
-	// io.Copy 會在 context 有效期間，把資料從網路串流搬到記憶體 buf
-	// 如果此時 context cancel，io.Copy 會回傳錯誤
-	if _, err := io.Copy(&buf, resp.Body); err != nil {
-		return nil, fmt.Errorf("暫存資料失敗 (可能超時或連線中斷): %w", err)
-	}
-	return bytes.NewReader(buf.Bytes()), nil
+	return bytes.NewReader(body), nil
 }
 
-type ctsaCrawler struct {
+type ctsaSource struct {
 	baseUrl         string
-	persistence     Persistence
-	client          *http.Client
+	yearURLFormat   string
+	fetcher         *fetcher
 	mockGetResponse func(url string) (io.Reader, error)
 }
 
-func (c *ctsaCrawler) Crawl(ctx context.Context) error {
-	raceIDs := c.getRaceIDs(ctx)
-
-	if len(raceIDs) == 0 {
-		log.Fatal("❌ 未能成功獲取任何比賽 ID，程序終止。")
-	}
-	fmt.Printf("✅ 成功找到 %d 個比賽 ID，開始逐一 POST 請求...\n", len(raceIDs))
-	fmt.Println("---------------------------------------------------------")
-	for _, raceID := range raceIDs {
-		err := c.postForDetails(ctx, raceID)
-		if err != nil {
-			log.Printf("❌ POST 請求失敗: %v", err)
-		} else {
-			log.Printf("✅ POST 請求成功: %s", raceID.Name)
-		}
-		time.Sleep(apiSleepDuration)
-	}
-	return nil
-}
-
-type activeInfo struct {
-	ID   string
-	Name string
-}
-
-type raceInfo struct {
-	CompetitionName string // 例如：114年全國南區(1)游泳錦標賽
-	RaceName        string // 例如：11 & 12歲級女子組游泳 200公尺自由式 計時決賽
-	ScoreReportURL  string // 成績報告的絕對 URL 連結
+func (info *RaceInfo) IsQualifier() bool {
+	return ParseRound(info.RaceName).IsQualifier()
 }
 
-func (info *raceInfo) IsQualifier() bool {
-	return strings.Contains(info.RaceName, "預賽") || strings.Contains(info.RaceName, "快組計時決賽")
-}
-
-func (c *ctsaCrawler) getInitialData(ctx context.Context) (map[string]string, error) {
+func (c *ctsaSource) getInitialData(ctx context.Context) (map[string]string, error) {
 	body, err := c.getResponse(ctx, c.baseUrl)
 	if err != nil {
 		return nil, fmt.Errorf("GET 請求失敗: %w", err)
@@ -155,17 +123,37 @@ func (c *ctsaCrawler) getInitialData(ctx context.Context) (map[string]string, er
 	return hiddenFields, nil
 }
 
-func (c *ctsaCrawler) getRaceIDs(ctx context.Context) []activeInfo {
-	body, err := c.getResponse(ctx, c.baseUrl)
+func (c *ctsaSource) ListCompetitions(ctx context.Context) ([]CompetitionInfo, error) {
+	return c.listCompetitions(ctx, c.baseUrl)
+}
+
+// DiscoverYears 探測 CTSA_<year> 封存網站，回傳有比賽資料的年度
+func (c *ctsaSource) DiscoverYears(ctx context.Context) ([]string, error) {
+	currentYear := time.Now().Year() - rocYearOffset
+	var years []string
+	for year := ctsaFirstYear; year <= currentYear; year++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		yearStr := strconv.Itoa(year)
+		competitions, err := c.listCompetitions(ctx, fmt.Sprintf(c.yearURLFormat, yearStr))
+		if err != nil || len(competitions) == 0 {
+			continue
+		}
+		years = append(years, yearStr)
+	}
+	return years, nil
+}
+
+func (c *ctsaSource) listCompetitions(ctx context.Context, pageURL string) ([]CompetitionInfo, error) {
+	body, err := c.getResponse(ctx, pageURL)
 	if err != nil {
-		log.Printf("GET 請求失敗: %v", err)
-		return nil
+		return nil, fmt.Errorf("GET 請求失敗: %w", err)
 	}
 
 	doc, err := htmlquery.Parse(body)
 	if err != nil {
-		log.Printf("HTML 解析失敗: %v", err)
-		return nil
+		return nil, fmt.Errorf("HTML 解析失敗: %w", err)
 	}
 
 	// 假設 <select> 的 ID 是 "ddlRace" 或其他類似名稱
@@ -175,29 +163,21 @@ func (c *ctsaCrawler) getRaceIDs(ctx context.Context) []activeInfo {
 	// 這裡使用更通用的 XPath: 尋找所有具有 value 屬性的 <option>
 	list := htmlquery.Find(doc, "//select[@name='ctl00$ContentPlaceHolder1$DD_Activity_ID']/option")
 
-	var actives []activeInfo
+	var actives []CompetitionInfo
 	for _, n := range list {
 		// 提取 value 屬性
 		id := htmlquery.SelectAttr(n, "value")
 		// 忽略第一個通常是 "請選擇" 或空值的 option
 		name := htmlquery.InnerText(n)
 		if id != "" && id != "0" {
-			actives = append(actives, activeInfo{ID: id, Name: name})
+			actives = append(actives, CompetitionInfo{ID: id, Name: name})
 		}
 	}
 
-	return actives
+	return actives, nil
 }
 
-func (c *ctsaCrawler) postForDetails(ctx context.Context, active activeInfo) error {
-	races, err := c.fetchRaceList(ctx, active)
-	if err != nil {
-		return err
-	}
-	return c.processRaces(ctx, races)
-}
-
-func (c *ctsaCrawler) fetchRaceList(ctx context.Context, active activeInfo) ([]raceInfo, error) {
+func (c *ctsaSource) ListRaces(ctx context.Context, active CompetitionInfo) ([]RaceInfo, error) {
 	hiddenFields, err := c.getInitialData(ctx)
 	if err != nil {
 		return nil, err
@@ -209,41 +189,23 @@ func (c *ctsaCrawler) fetchRaceList(ctx context.Context, active activeInfo) ([]r
 		form.Add(k, v)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultDelay)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseUrl, strings.NewReader(form.Encode()))
-	if err != nil {
-		return nil, fmt.Errorf("構造請求失敗: %w", err)
-	}
-
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Add("Content-Length", fmt.Sprintf("%d", len(form.Encode())))
-
-	resp, err := c.client.Do(req)
+	body, err := c.fetcher.PostForm(ctx, c.baseUrl, form)
 	if err != nil {
-		return nil, fmt.Errorf("POST 請求失敗: %w", err)
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("POST 請求返回非預期狀態碼: %d %s", resp.StatusCode, resp.Status)
+		return nil, err
 	}
 
-	doc, err := htmlquery.Parse(resp.Body)
+	doc, err := htmlquery.Parse(bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("HTML 解析失敗: %w", err)
 	}
 
-	return c.parseRaceList(doc, active.Name), nil
+	return c.parseRaceList(doc, active), nil
 }
 
-func (c *ctsaCrawler) parseRaceList(doc *html.Node, competitionName string) []raceInfo {
+func (c *ctsaSource) parseRaceList(doc *html.Node, active CompetitionInfo) []RaceInfo {
 	xpath := "//table[@id='ctl00_ContentPlaceHolder1_GridView1']/tbody/tr[position() > 1]"
 	dataRows := htmlquery.Find(doc, xpath)
-	var races []raceInfo
+	var races []RaceInfo
 	base, _ := url.Parse(c.baseUrl)
 
 	for _, trNode := range dataRows {
@@ -265,84 +227,38 @@ func (c *ctsaCrawler) parseRaceList(doc *html.Node, competitionName string) []ra
 		}
 
 		if absoluteURL != notApplicable && strings.TrimSpace(raceName) != "" {
-			races = append(races, raceInfo{
-				CompetitionName: competitionName,
+			races = append(races, RaceInfo{
+				CompetitionID:   active.ID,
+				CompetitionName: active.Name,
 				RaceName:        strings.TrimSpace(raceName),
-				ScoreReportURL:  absoluteURL,
+				URL:             absoluteURL,
 			})
 		}
 	}
 	return races
 }
 
-func (c *ctsaCrawler) processRaces(ctx context.Context, races []raceInfo) error {
-	const maxConcurrency = 5
-	semaphore := make(chan struct{}, maxConcurrency)
-	var wg sync.WaitGroup
-	errChan := make(chan error, 1)
-
-	for _, race := range races {
-		select {
-		case err := <-errChan:
-			close(semaphore)
-			wg.Wait()
-			return err
-		default:
-		}
-
-		wg.Add(1)
-		semaphore <- struct{}{}
-		go c.processSingleRace(ctx, race, &wg, semaphore, errChan)
-	}
-	wg.Wait()
-	close(errChan)
-
-	if err, ok := <-errChan; ok {
-		return err
-	}
-	return nil
+func (*ctsaSource) Name() string {
+	return ctsaSourceName
 }
 
-func (c *ctsaCrawler) processSingleRace(
-	ctx context.Context,
-	race raceInfo,
-	wg *sync.WaitGroup,
-	semaphore chan struct{},
-	errChan chan error,
-) {
-	defer wg.Done()
-	defer func() { <-semaphore }()
-
-	ok, err := c.persistence.IsCrawled(race.ScoreReportURL)
+func (c *ctsaSource) FetchRace(ctx context.Context, info RaceInfo) (*Race, error) {
+	body, err := c.getResponse(ctx, info.URL)
 	if err != nil {
-		sendNonBlockingError(fmt.Errorf("check crawled fail: %w", err), errChan)
-		return
-	}
-	if ok {
-		return
-	}
-	dbrace, err := c.createRace(ctx, race)
-	if err != nil {
-		sendNonBlockingError(fmt.Errorf("generate race %s [%s] fail: %w", race.CompetitionName, race.RaceName, err), errChan)
-		return
-	}
-	err = c.persistence.PersistRace(dbrace)
-	if err != nil {
-		sendNonBlockingError(fmt.Errorf("persistence race fail: %w", err), errChan)
-		return
-	}
-	err = c.persistence.CrawlLog(race.ScoreReportURL)
-	if err != nil {
-		sendNonBlockingError(fmt.Errorf("persistence crawl log fail: %w", err), errChan)
-		return
+		return nil, fmt.Errorf("GET 請求失敗: %w", err)
 	}
+	return c.ParseRace(info, body)
 }
 
-func (c *ctsaCrawler) createRace(ctx context.Context, info raceInfo) (*Race, error) {
-	body, err := c.getResponse(ctx, info.ScoreReportURL)
+func (c *ctsaSource) FetchPage(ctx context.Context, info RaceInfo) ([]byte, error) {
+	body, err := c.getResponse(ctx, info.URL)
 	if err != nil {
 		return nil, fmt.Errorf("GET 請求失敗: %w", err)
 	}
+	return io.ReadAll(body)
+}
+
+func (*ctsaSource) ParseRace(info RaceInfo, body io.Reader) (*Race, error) {
 	doc, err := htmlquery.Parse(body)
 	if err != nil {
 		return nil, fmt.Errorf("HTML 解析失敗: %w", err)
@@ -355,13 +271,14 @@ func (c *ctsaCrawler) createRace(ctx context.Context, info raceInfo) (*Race, err
 	return race, nil
 }
 
-func newRaceBuilder(doc *html.Node, info raceInfo) *raceBuilder {
+func newRaceBuilder(doc *html.Node, info RaceInfo) *raceBuilder {
 	return &raceBuilder{doc: doc, info: info}
 }
 
 type raceBuilder struct {
-	info raceInfo
-	doc  *html.Node
+	info     RaceInfo
+	doc      *html.Node
+	unparsed []UnparsedRow
 }
 
 type raceRecord struct {
@@ -369,22 +286,6 @@ type raceRecord struct {
 	nationalRecord time.Duration
 }
 
-func parseTimeDuration(timeStr string) (time.Duration, error) {
-	// 格式範例: "05:34.22"
-	parts := strings.Split(timeStr, ":")
-	if len(parts) != expectedTimeSplitParts {
-		return 0, fmt.Errorf("時間格式錯誤，預期為 mm:ss.SS，實際為: %s", timeStr)
-	}
-
-	minutes := parts[0]
-	secondsWithMillis := parts[1]
-
-	// 構造 Duration 字串: Go 的 time.ParseDuration 接受 "5m34.22s" 這樣的格式
-	durationString := fmt.Sprintf("%sm%ss", minutes, secondsWithMillis)
-
-	return time.ParseDuration(durationString)
-}
-
 func (b *raceBuilder) getRecord() (*raceRecord, error) {
 	// 3. 提取並清洗時間字串
 	// 完整的文字內容是 " 大會紀錄：05:34.22   全國紀錄：04:40.21 " (包含換行和空格)
@@ -397,26 +298,24 @@ func (b *raceBuilder) getRecord() (*raceRecord, error) {
 	}
 
 	var records raceRecord
+	records.gameRecord = b.parseRecordMark(text, gameRecordReg, "大會紀錄")
+	records.nationalRecord = b.parseRecordMark(text, nationalRecordReg, "全國紀錄")
+	return &records, nil
+}
 
-	gameRecordReg := regexp.MustCompile(`大會紀錄：\s*(\d{2}:\d{2}\.\d{2})`)
-	gameMatch := gameRecordReg.FindStringSubmatch(text)
+// parseRecordMark 解析表頭中的紀錄時間，無法解析時記錄在 b.unparsed 並回傳 0
+func (b *raceBuilder) parseRecordMark(text string, reg *regexp.Regexp, label string) time.Duration {
+	match := reg.FindStringSubmatch(text)
 	const expectMatchSize = 2
-	if len(gameMatch) == expectMatchSize {
-		records.gameRecord, err = parseTimeDuration(gameMatch[1])
-		if err != nil {
-			return nil, fmt.Errorf("轉換大會紀錄失敗: %w", err)
-		}
+	if len(match) != expectMatchSize {
+		return 0
 	}
-
-	nationalRecordReg := regexp.MustCompile(`全國紀錄：\s*(\d{2}:\d{2}\.\d{2})`)
-	nationalMatch := nationalRecordReg.FindStringSubmatch(text)
-	if len(nationalMatch) == expectMatchSize {
-		records.nationalRecord, err = parseTimeDuration(nationalMatch[1])
-		if err != nil {
-			return nil, fmt.Errorf("轉換全國紀錄失敗: %w", err)
-		}
+	d, err := ParseSwimTime(match[1])
+	if err != nil {
+		b.unparsed = append(b.unparsed, UnparsedRow{Text: match[0], Reason: fmt.Sprintf("轉換%s失敗: %v", label, err)})
+		return 0
 	}
-	return &records, nil
+	return d
 }
 
 func (b *raceBuilder) getOrganizer() (string, error) {
@@ -472,6 +371,7 @@ func (b *raceBuilder) getTime() (time.Time, error) {
 	return t, nil
 }
 
+// getResult 解析成績表，無法解析的列記錄在 b.unparsed 並略過，不會讓整個項目失敗
 func (b *raceBuilder) getResult() ([]*RaceResult, error) {
 	list, err := b.listElement(
 		"/html/body/form/div[3]/span/div[2]/table/tbody/tr[position() > 1]",
@@ -479,56 +379,82 @@ func (b *raceBuilder) getResult() ([]*RaceResult, error) {
 	if err != nil {
 		return nil, err
 	}
+	columns := b.getResultColumns()
 	results := make([]*RaceResult, 0, len(list))
-	for _, n := range list {
+	for i, n := range list {
 		tds := htmlquery.Find(n, "/td/font") // 選擇 tr 下所有 td 內的 font 標籤
 
 		if len(tds) < minRaceResultColumns {
 			// 跳過格式不正確的行
 			continue
 		}
-		result := RaceResult{
-			Unit: strings.TrimSpace(htmlquery.InnerText(tds[2])),
-			Name: strings.Split(strings.TrimSpace(htmlquery.InnerText(tds[3])), " "),
-			Note: strings.TrimSpace(htmlquery.InnerText(tds[7])),
+		result, err := b.parseResultRow(tds)
+		if err != nil {
+			b.unparsed = append(b.unparsed, UnparsedRow{Row: i + 1, Text: rowText(tds), Reason: err.Error()})
+			continue
 		}
-		recordStr := strings.TrimSpace(htmlquery.InnerText(tds[4]))
-		rankStr := strings.TrimSpace(htmlquery.InnerText(tds[5]))
-		scoreStr := strings.TrimSpace(htmlquery.InnerText(tds[6]))
-		if recordStr != "" {
-			duration, err := parseTimeDuration(recordStr)
-			if err != nil {
-				return nil, err
-			}
+		columns.apply(result, n, b.info.URL)
+		if len(result.Name) != 0 {
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+func (b *raceBuilder) parseResultRow(tds []*html.Node) (*RaceResult, error) {
+	result := RaceResult{
+		Unit: strings.TrimSpace(htmlquery.InnerText(tds[2])),
+		Name: strings.Fields(htmlquery.InnerText(tds[3])),
+		Note: strings.TrimSpace(htmlquery.InnerText(tds[7])),
+	}
+	recordStr := strings.TrimSpace(htmlquery.InnerText(tds[4]))
+	rankStr := strings.TrimSpace(htmlquery.InnerText(tds[5]))
+	scoreStr := strings.TrimSpace(htmlquery.InnerText(tds[6]))
+	statusText := result.Note
+	if recordStr != "" {
+		duration, err := ParseSwimTime(recordStr)
+		switch {
+		case err == nil:
 			result.Record = duration
-			// 如果解析失敗 (如 "逾時" 的空字串)，Record 保持為 0 (零值)
+		case IsResultStatusText(recordStr):
+			// 成績欄位直接寫著 "犯規"、"棄權" 等狀態，Record 保持為 0 (零值)
+			statusText = recordStr + " " + statusText
+		default:
+			return nil, err
 		}
-		// 處理 Rank (名次)
-		if !b.info.IsQualifier() && rankStr != "" {
-			rank, err := stringToInt32(rankStr)
-			if err != nil {
-				return nil, fmt.Errorf("convert rank to int failed: %w", err)
-			}
-			result.Rank = rank
+	}
+	result.Status, result.StatusReason = ParseResultStatus(statusText, result.Record > 0)
+	// 處理 Rank (名次)
+	if !b.info.IsQualifier() && rankStr != "" {
+		rank, err := stringToInt32(rankStr)
+		if err != nil {
+			return nil, fmt.Errorf("convert rank to int failed: %w", err)
 		}
+		result.Rank = rank
+	}
 
-		// 處理 Score (積點)
-		if !b.info.IsQualifier() && scoreStr != "" {
-			score, err := stringToInt32(scoreStr)
-			if err != nil {
-				return nil, fmt.Errorf("convert score to int failed: %w", err)
-			}
-			result.Score = score
-		}
-		if len(result.Name) != 0 {
-			results = append(results, &result)
+	// 處理 Score (積點)
+	if !b.info.IsQualifier() && scoreStr != "" {
+		score, err := stringToInt32(scoreStr)
+		if err != nil {
+			return nil, fmt.Errorf("convert score to int failed: %w", err)
 		}
+		result.Score = score
 	}
-	return results, nil
+	return &result, nil
+}
+
+// rowText 以 " | " 串接一列中各欄的文字，用於回報無法解析的列
+func rowText(tds []*html.Node) string {
+	texts := make([]string, len(tds))
+	for i, td := range tds {
+		texts[i] = strings.TrimSpace(htmlquery.InnerText(td))
+	}
+	return strings.Join(texts, " | ")
 }
 
 func stringToInt32(s string) (int32, error) {
-	val, err := strconv.ParseInt(s, 10, 32)
+	val, err := strconv.ParseInt(toHalfWidth(s), 10, 32)
 	if err != nil {
 		return 0, err
 	}
@@ -556,6 +482,7 @@ func (b *raceBuilder) CreateRace() (*Race, error) {
 	reAgeGender := regexp.MustCompile(`(([\s\d]+[\s&~及]+[\s\d\p{Han}]+歲級)|([\s\p{Han}]+級)|(排名賽))(.+?組)`)
 	matches := reAgeGender.FindStringSubmatch(b.info.RaceName)
 	r.EventName = b.info.RaceName
+	r.Event = ParseEvent(b.info.RaceName)
 	remainingStr := b.info.RaceName
 	if len(matches) > minAgeGenderRegexMatches {
 		r.AgeGroup = strings.ReplaceAll(matches[1], " ", "") // "11&12"
@@ -591,7 +518,15 @@ func (b *raceBuilder) CreateRace() (*Race, error) {
 	if err != nil {
 		return nil, err
 	}
+	if r.Event.Relay {
+		for _, result := range results {
+			result.Team = result.Unit
+			result.Legs = RelayLegs(r.Event, result.Name)
+			ApplyLegSplits(r.Event, result)
+		}
+	}
 	r.Results = results
+	r.Unparsed = b.unparsed
 	return &r, nil
 }
 
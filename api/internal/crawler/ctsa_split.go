@@ -0,0 +1,172 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/antchfx/htmlquery"
+	"golang.org/x/net/html"
+)
+
+const (
+	// ctsaSplitInterval 是 "分段" 欄位只列出時間時，每一段的距離 (公尺)
+	ctsaSplitInterval = 50
+	// ctsaRecordColumn 是成績欄位的位置，成績可能連結到詳細成績頁
+	ctsaRecordColumn = 4
+)
+
+var splitHeaderReg = regexp.MustCompile(`^(\d+)(?:公尺|[mM])$`)
+
+// resultColumns 是成績表中基本欄位 (單位、姓名、成績、名次、積分、備註) 以外的欄位位置，
+// 舊的成績報告沒有這些欄位
+type resultColumns struct {
+	splits   map[int]int // 欄位位置對應的分段距離，例如標題為 "50m" 的欄位
+	lapList  int         // 一格列出所有分段的 "分段" 欄位，-1 代表沒有
+	reaction int         // 反應時間欄位，-1 代表沒有
+}
+
+func (b *raceBuilder) getResultColumns() resultColumns {
+	columns := resultColumns{splits: map[int]int{}, lapList: -1, reaction: -1}
+	header := htmlquery.FindOne(b.doc, "/html/body/form/div[3]/span/div[2]/table/tbody/tr[1]")
+	if header == nil {
+		header = htmlquery.FindOne(b.doc, "/html/body/form/div[1]/span/div[2]/table/tbody/tr[1]")
+	}
+	if header == nil {
+		return columns
+	}
+	for i, td := range htmlquery.Find(header, "/td") {
+		title := cellText(td)
+		switch {
+		case strings.Contains(title, "反應") || strings.EqualFold(title, "RT"):
+			columns.reaction = i
+		case strings.HasPrefix(title, "分段"):
+			columns.lapList = i
+		default:
+			if matches := splitHeaderReg.FindStringSubmatch(title); matches != nil {
+				columns.splits[i], _ = strconv.Atoi(matches[1])
+			}
+		}
+	}
+	return columns
+}
+
+// apply 讀取一行成績的分段、反應時間與詳細成績頁連結，無法解析的格子會被忽略
+func (columns resultColumns) apply(result *RaceResult, row *html.Node, reportURL string) {
+	tds := htmlquery.Find(row, "/td")
+	var splits []Split
+	for i, distance := range columns.splits {
+		if i >= len(tds) {
+			continue
+		}
+		if d, err := ParseSwimTime(cellText(tds[i])); err == nil {
+			splits = append(splits, Split{Distance: distance, Time: d})
+		}
+	}
+	if columns.lapList >= 0 && columns.lapList < len(tds) {
+		splits = append(splits, parseSplitList(textLines(tds[columns.lapList]))...)
+	}
+	result.Splits = NormalizeSplits(splits, result.Record)
+	if columns.reaction >= 0 && columns.reaction < len(tds) {
+		if d, err := ParseSwimTime(cellText(tds[columns.reaction])); err == nil {
+			result.ReactionTime = d
+		}
+	}
+	link := htmlquery.FindOne(row, ".//a[@href][contains(., '分段') or contains(., '詳細')]")
+	if link == nil && ctsaRecordColumn < len(tds) {
+		link = htmlquery.FindOne(tds[ctsaRecordColumn], ".//a[@href]")
+	}
+	if link != nil {
+		result.DetailURL = resolveURL(reportURL, htmlquery.SelectAttr(link, "href"))
+	}
+}
+
+// parseSplitList 解析一格內以空白、"," 或 "/" 分隔的分段時間，依序視為每 50 公尺一段
+func parseSplitList(text string) []Split {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == '/' || r == ',' || unicode.IsSpace(r)
+	})
+	splits := make([]Split, 0, len(fields))
+	for _, field := range fields {
+		d, err := ParseSwimTime(field)
+		if err != nil {
+			continue
+		}
+		splits = append(splits, Split{Distance: (len(splits) + 1) * ctsaSplitInterval, Time: d})
+	}
+	return splits
+}
+
+// FetchResultDetail 下載並解析詳細成績頁
+func (c *ctsaSource) FetchResultDetail(ctx context.Context, detailURL string) (*ResultDetail, error) {
+	body, err := c.getResponse(ctx, detailURL)
+	if err != nil {
+		return nil, fmt.Errorf("GET 請求失敗: %w", err)
+	}
+	doc, err := htmlquery.Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("HTML 解析失敗: %w", err)
+	}
+	return parseResultDetail(doc), nil
+}
+
+// parseResultDetail 從詳細成績頁的表格中找出 "50m | 00:31.20" 形式的分段與反應時間
+func parseResultDetail(doc *html.Node) *ResultDetail {
+	detail := &ResultDetail{}
+	for _, row := range htmlquery.Find(doc, "//tr") {
+		tds := htmlquery.Find(row, "/td")
+		const minDetailColumns = 2
+		if len(tds) < minDetailColumns {
+			continue
+		}
+		title := cellText(tds[0])
+		value := cellText(tds[len(tds)-1])
+		if strings.Contains(title, "反應") {
+			if d, err := ParseSwimTime(value); err == nil {
+				detail.ReactionTime = d
+			}
+			continue
+		}
+		matches := splitHeaderReg.FindStringSubmatch(title)
+		if matches == nil {
+			continue
+		}
+		distance, _ := strconv.Atoi(matches[1])
+		if d, err := ParseSwimTime(value); err == nil {
+			detail.Splits = append(detail.Splits, Split{Distance: distance, Time: d})
+		}
+	}
+	return detail
+}
+
+// textLines 以換行串接格子內的每一段文字，避免 <br/> 前後的文字黏在一起
+func textLines(n *html.Node) string {
+	var lines []string
+	for node := range n.Descendants() {
+		if node.Type == html.TextNode {
+			lines = append(lines, node.Data)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// cellText 回傳表格格子去除所有空白 (含 &nbsp;) 後的文字
+func cellText(n *html.Node) string {
+	return strings.Join(strings.Fields(htmlquery.InnerText(n)), "")
+}
+
+func resolveURL(base, ref string) string {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return baseURL.ResolveReference(refURL).String()
+}
@@ -0,0 +1,65 @@
+package crawler
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSplitList(t *testing.T) {
+	assert.Equal(t, []Split{
+		{Distance: 50, Time: 33500 * time.Millisecond},
+		{Distance: 100, Time: 70200 * time.Millisecond},
+		{Distance: 150, Time: 107 * time.Second},
+	}, parseSplitList("00:33.50 / 01:10.20\n01:47.00"))
+	assert.Empty(t, parseSplitList(""))
+}
+
+func TestCtsaSource_splits(t *testing.T) {
+	report, err := os.ReadFile("test_file/ctsa/record_splits.html")
+	require.NoError(t, err)
+	detail, err := os.ReadFile("test_file/ctsa/result_detail.html")
+	require.NoError(t, err)
+	source, err := newCtsaSource(withGetResponse(func(url string) (io.Reader, error) {
+		if strings.Contains(url, "result_detail.aspx") {
+			return bytes.NewReader(detail), nil
+		}
+		return bytes.NewReader(report), nil
+	}))
+	require.NoError(t, err)
+	c, err := New(source, WithPersistence(&mockPersistence{}))
+	require.NoError(t, err)
+
+	race, err := c.fetchRace(t.Context(), RaceInfo{
+		CompetitionName: "114年全國南區(1)游泳錦標賽",
+		RaceName:        "11 & 12歲級女子組200公尺自由式 計時決賽",
+		URL:             "https://ctsa.utk.com.tw/CTSA/public/race/report.aspx?id=1",
+	})
+	require.NoError(t, err)
+	require.Len(t, race.Results, 36)
+
+	first := race.Results[0]
+	assert.Equal(t, 680*time.Millisecond, first.ReactionTime)
+	assert.Equal(t, []Split{
+		{Distance: 50, Time: 33500 * time.Millisecond},
+		{Distance: 100, Time: 70200 * time.Millisecond},
+		{Distance: 150, Time: 107 * time.Second},
+		{Distance: 200, Time: first.Record},
+	}, first.Splits)
+	assert.Empty(t, first.DetailURL)
+
+	second := race.Results[1]
+	assert.Equal(t, "https://ctsa.utk.com.tw/CTSA/public/race/result_detail.aspx?id=2", second.DetailURL)
+	assert.Equal(t, 710*time.Millisecond, second.ReactionTime)
+	require.Len(t, second.Splits, 4)
+	assert.Equal(t, Split{Distance: 200, Time: second.Record}, second.Splits[3])
+
+	assert.Nil(t, race.Results[2].Splits)
+	assert.Zero(t, race.Results[2].ReactionTime)
+}
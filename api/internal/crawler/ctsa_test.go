@@ -2,12 +2,13 @@ package crawler
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"sync"
 	"testing"
-	"time"
 
 	"github.com/antchfx/htmlquery"
 	"github.com/stretchr/testify/assert"
@@ -15,12 +16,7 @@ import (
 )
 
 func Test_getRaceIDs(t *testing.T) {
-	mockP := &mockPersistence{
-		persisRace:      func(*Race) error { return nil },
-		persistCrawlLog: func(string) error { return nil },
-		isCrawled:       func(string) (bool, error) { return false, nil },
-	}
-	crawler, err := NewCtsaCrawler(
+	crawler, err := newCtsaSource(
 		withGetResponse(func(string) (io.Reader, error) {
 			file, err := os.Open("test_file/ctsa/get_race_ids.html")
 			if err != nil {
@@ -34,22 +30,17 @@ func Test_getRaceIDs(t *testing.T) {
 			}
 			return bytes.NewReader(data), nil
 		}),
-		WithPersistence(mockP),
 	)
 	require.NoError(t, err)
-	info := crawler.getRaceIDs(t.Context())
+	info, err := crawler.ListCompetitions(t.Context())
+	require.NoError(t, err)
 	assert.Len(t, info, 15)
 	assert.Equal(t, "114年全國中區(1)游泳錦標賽", info[0].Name)
 	assert.Equal(t, "151", info[0].ID)
 }
 
 func Test_createRace(t *testing.T) {
-	mockP := &mockPersistence{
-		persisRace:      func(*Race) error { return nil },
-		persistCrawlLog: func(string) error { return nil },
-		isCrawled:       func(string) (bool, error) { return false, nil },
-	}
-	crawler, err := NewCtsaCrawler(
+	crawler, err := newCtsaSource(
 		withGetResponse(func(string) (io.Reader, error) {
 			file, err := os.Open("test_file/ctsa/record_1.html")
 			if err != nil {
@@ -63,20 +54,19 @@ func Test_createRace(t *testing.T) {
 			}
 			return bytes.NewReader(data), nil
 		}),
-		WithPersistence(mockP),
 	)
 	require.NoError(t, err)
-	race, err := crawler.createRace(t.Context(), raceInfo{
+	race, err := crawler.FetchRace(t.Context(), RaceInfo{
 		CompetitionName: "114年全國南區(1)游泳錦標賽",
 		RaceName:        "11 & 12歲級女子組200公尺自由式 計時決賽",
 	})
 	require.NoError(t, err)
 	assert.Equal(t, "11&12歲級", race.AgeGroup)
-	expectTimeDuration, _ := parseTimeDuration("01:59.93")
+	expectTimeDuration, _ := ParseSwimTime("01:59.93")
 	assert.Equal(t, expectTimeDuration, race.NationalRecord)
 	assert.Len(t, race.Results, 36)
 
-	crawler, err = NewCtsaCrawler(
+	crawler, err = newCtsaSource(
 		withGetResponse(func(string) (io.Reader, error) {
 			file, err := os.Open("test_file/ctsa/record_2.html")
 			if err != nil {
@@ -90,51 +80,48 @@ func Test_createRace(t *testing.T) {
 			}
 			return bytes.NewReader(data), nil
 		}),
-		WithPersistence(mockP),
 	)
 	require.NoError(t, err)
-	race, err = crawler.createRace(t.Context(), raceInfo{
+	race, err = crawler.FetchRace(t.Context(), RaceInfo{
 		CompetitionName: "114年全國春季游泳錦標賽",
 		RaceName:        "18及以上歲級男子組400公尺混合式 計時決賽",
 	})
 	require.NoError(t, err)
 	assert.Equal(t, "18及以上歲級", race.AgeGroup)
-	expectTimeDuration, _ = parseTimeDuration("04:15.86")
+	assert.Equal(t, Event{Distance: 400, Stroke: StrokeMedley, Round: RoundTimedFinal}, race.Event)
+	expectTimeDuration, _ = ParseSwimTime("04:15.86")
 	assert.Equal(t, expectTimeDuration, race.NationalRecord)
 	assert.Len(t, race.Results, 14)
+	assert.Equal(t, ResultStatusOK, race.Results[0].Status)
+	assert.Equal(t, ResultStatusDNF, race.Results[13].Status)
+	assert.Equal(t, "逾時", race.Results[13].Note)
+	assert.Zero(t, race.Results[13].Record)
 }
 
-func TestParseTimeDuration(t *testing.T) {
-	tests := []struct {
-		input    string
-		expected time.Duration
-		wantErr  bool
-	}{
-		{"05:34.22", 5*time.Minute + 34*time.Second + 220*time.Millisecond, false},
-		{"00:55.00", 55 * time.Second, false},
-		{"invalid", 0, true},
-		{"44.3", 0, true}, // Missing dot part or wrong format based on Split(":") length check
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			got, err := parseTimeDuration(tt.input)
-			if tt.wantErr {
-				assert.Error(t, err)
-			} else {
-				require.NoError(t, err)
-				assert.Equal(t, tt.expected, got)
-			}
-		})
-	}
+func Test_createRace_unparsed(t *testing.T) {
+	report, err := os.ReadFile("test_file/ctsa/record_unparsed.html")
+	require.NoError(t, err)
+	source, err := newCtsaSource(withGetResponse(func(string) (io.Reader, error) {
+		return bytes.NewReader(report), nil
+	}))
+	require.NoError(t, err)
+	race, err := source.FetchRace(t.Context(), RaceInfo{
+		CompetitionName: "114年全國春季游泳錦標賽",
+		RaceName:        "18及以上歲級男子組400公尺混合式 計時決賽",
+	})
+	require.NoError(t, err)
+	assert.Len(t, race.Results, 13)
+	expectTimeDuration, _ := ParseSwimTime("04:15.86")
+	assert.Equal(t, expectTimeDuration, race.NationalRecord)
+	assert.Zero(t, race.GamesRecord)
+	require.Len(t, race.Unparsed, 2)
+	assert.Equal(t, 0, race.Unparsed[0].Row)
+	assert.Equal(t, "大會紀錄：4:23.2.7", race.Unparsed[0].Text)
+	assert.Equal(t, 1, race.Unparsed[1].Row)
+	assert.Contains(t, race.Unparsed[1].Text, "傅堃銘 | 04:27.2x")
 }
 
 func Test_parseRaceList(t *testing.T) {
-	mockP := &mockPersistence{
-		persisRace:      func(*Race) error { return nil },
-		persistCrawlLog: func(string) error { return nil },
-		isCrawled:       func(string) (bool, error) { return false, nil },
-	}
 	file, err := os.Open("test_file/ctsa/get_race.html")
 	require.NoError(t, err)
 	defer file.Close()
@@ -142,52 +129,142 @@ func Test_parseRaceList(t *testing.T) {
 	doc, err := htmlquery.Parse(file)
 	require.NoError(t, err)
 
-	crawler, err := NewCtsaCrawler(
-		WithPersistence(mockP),
-	)
+	crawler, err := newCtsaSource()
 	require.NoError(t, err)
-	raceSlice := crawler.parseRaceList(doc, "114年全國春季游泳錦標賽")
+	raceSlice := crawler.parseRaceList(doc, CompetitionInfo{ID: "399", Name: "114年全國春季游泳錦標賽"})
 	assert.Len(t, raceSlice, 176)
+	assert.Equal(t, "399", raceSlice[0].CompetitionID)
 	assert.Equal(t, "11 & 12歲級女子組游泳 400公尺混合式 計時決賽", raceSlice[0].RaceName)
 }
 
 // mockPersistence for testing crawler functions that need Persistence interface
 type mockPersistence struct {
-	persisRace      func(race *Race) error
-	persistCrawlLog func(url string) error
-	isCrawled       func(url string) (bool, error)
-}
-
-func (m *mockPersistence) PersistRace(race *Race) error {
-	return m.persisRace(race)
+	persistRace func(url string, race *Race) error
+	isCrawled   func(url string) (bool, error)
 }
 
-func (m *mockPersistence) CrawlLog(url string) error {
-	return m.persistCrawlLog(url)
+func (m *mockPersistence) PersistRace(url string, race *Race) error {
+	return m.persistRace(url, race)
 }
 
 func (m *mockPersistence) IsCrawled(url string) (bool, error) {
 	return m.isCrawled(url)
 }
 
-// TestCtsaCrawler_Crawl is a basic integration-style test for the crawler setup
-// It uses mock persistence and ensures the crawler can be initialized and called.
-func TestCtsaCrawler_Crawl(t *testing.T) {
+func TestNewSource(t *testing.T) {
+	assert.Contains(t, SourceNames(), "ctsa")
+
+	source, err := NewSource("ctsa", WithYear("113"))
+	require.NoError(t, err)
+	ctsa, ok := source.(*ctsaSource)
+	require.True(t, ok)
+	assert.Equal(t, "https://ctsa.utk.com.tw/CTSA_113/public/race/game_data.aspx", ctsa.baseUrl)
+
+	_, err = NewSource("unknown")
+	assert.Error(t, err)
+}
+
+// mockSource for testing Crawler without any network access
+type mockSource struct {
+	competitions []CompetitionInfo
+	races        map[string][]RaceInfo
+
+	mu       sync.Mutex
+	failures map[string]int // URL -> 剩餘失敗次數
+}
+
+func (*mockSource) Name() string {
+	return "mock"
+}
+
+func (m *mockSource) ListCompetitions(context.Context) ([]CompetitionInfo, error) {
+	return m.competitions, nil
+}
+
+func (m *mockSource) ListRaces(_ context.Context, competition CompetitionInfo) ([]RaceInfo, error) {
+	return m.races[competition.ID], nil
+}
+
+func (m *mockSource) FetchRace(_ context.Context, info RaceInfo) (*Race, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failures[info.URL] > 0 {
+		m.failures[info.URL]--
+		return nil, fmt.Errorf("fetch %s fail", info.URL)
+	}
+	return &Race{CompetitionName: info.CompetitionName, EventName: info.RaceName}, nil
+}
+
+func TestCrawler_Crawl(t *testing.T) {
+	source := &mockSource{
+		competitions: []CompetitionInfo{{ID: "1", Name: "A"}, {ID: "2", Name: "B"}},
+		races: map[string][]RaceInfo{
+			"1": {{CompetitionName: "A", RaceName: "a1", URL: "u1"}, {CompetitionName: "A", RaceName: "a2", URL: "u2"}},
+			"2": {{CompetitionName: "B", RaceName: "b1", URL: "u3"}},
+		},
+	}
+	var mu sync.Mutex
+	var persisted, logged, sources, competitionIDs []string
 	mockP := &mockPersistence{
-		persisRace:      func(*Race) error { return nil },
-		persistCrawlLog: func(string) error { return nil },
-		isCrawled:       func(string) (bool, error) { return false, nil },
+		persistRace: func(url string, race *Race) error {
+			mu.Lock()
+			defer mu.Unlock()
+			persisted = append(persisted, race.EventName)
+			logged = append(logged, url)
+			sources = append(sources, race.Source)
+			competitionIDs = append(competitionIDs, race.CompetitionID)
+			return nil
+		},
+		isCrawled: func(url string) (bool, error) { return url == "u2", nil },
 	}
 
-	// Using a dummy URL for initialization, actual HTTP calls will be mocked in a true integration test.
-	crawler, err := NewCtsaCrawler(WithBaseURL("http://dummy.url"), WithPersistence(mockP))
+	_, err := New(source)
+	require.Error(t, err)
+
+	c, err := New(source, WithPersistence(mockP))
 	require.NoError(t, err)
-	assert.NotNil(t, crawler)
+	require.NoError(t, c.Crawl(t.Context()))
+	assert.ElementsMatch(t, []string{"a1", "b1"}, persisted)
+	assert.ElementsMatch(t, []string{"u1", "u3"}, logged)
+	assert.Equal(t, []string{"mock", "mock"}, sources)
+	assert.ElementsMatch(t, []string{"1", "2"}, competitionIDs)
+}
 
-	// Note: Actual Crawl() method requires external network calls,
-	// which are outside the scope of a strict unit test.
-	// This test primarily checks the setup and ensures no immediate panics.
-	// For full functionality testing, network calls would need to be mocked extensively
-	// or an integration test would be required.
-	// As this is a placeholder/setup check, we don't call Crawl() here.
+type mockArchive struct {
+	pages []*ArchivedPage
+}
+
+func (m *mockArchive) ArchivePage(page *ArchivedPage) error {
+	m.pages = append(m.pages, page)
+	return nil
+}
+
+func TestCrawler_archive(t *testing.T) {
+	data, err := os.ReadFile("test_file/ctsa/record_1.html")
+	require.NoError(t, err)
+	source, err := newCtsaSource(withGetResponse(func(string) (io.Reader, error) {
+		return bytes.NewReader(data), nil
+	}))
+	require.NoError(t, err)
+	archive := &mockArchive{}
+	c, err := New(source, WithPersistence(&mockPersistence{}), WithArchive(archive))
+	require.NoError(t, err)
+
+	info := RaceInfo{
+		CompetitionName: "114年全國南區(1)游泳錦標賽",
+		RaceName:        "11 & 12歲級女子組200公尺自由式 計時決賽",
+		URL:             "http://dummy.url/report",
+	}
+	race, err := c.fetchRace(t.Context(), info)
+	require.NoError(t, err)
+	require.Len(t, archive.pages, 1)
+	page := archive.pages[0]
+	assert.Equal(t, "ctsa", page.Source)
+	assert.Equal(t, "114", page.Year)
+	assert.Equal(t, data, page.Body)
+	assert.Len(t, page.Hash, 64)
+
+	reparsed, err := ParseArchivedPage(page)
+	require.NoError(t, err)
+	assert.Equal(t, race, reparsed)
 }
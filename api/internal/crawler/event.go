@@ -0,0 +1,152 @@
+package crawler
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Stroke 是正規化後的泳式
+type Stroke string
+
+const (
+	StrokeFreestyle    Stroke = "freestyle"    // 自由式
+	StrokeBackstroke   Stroke = "backstroke"   // 仰式
+	StrokeBreaststroke Stroke = "breaststroke" // 蛙式
+	StrokeButterfly    Stroke = "butterfly"    // 蝶式
+	StrokeMedley       Stroke = "medley"       // 混合式 (個人混合式或混合式接力)
+)
+
+// Round 是正規化後的賽次
+type Round string
+
+const (
+	RoundHeat               Round = "heat"                  // 預賽
+	RoundSemifinal          Round = "semifinal"             // 準決賽
+	RoundFinal              Round = "final"                 // 決賽
+	RoundTimedFinal         Round = "timed_final"           // 計時決賽
+	RoundFastHeatTimedFinal Round = "fast_heat_timed_final" // 快組計時決賽
+)
+
+// Event 是由項目名稱解析出的結構化項目，無法解析的欄位保持零值
+type Event struct {
+	Distance   int    // 距離 (公尺)，接力為每一棒的距離
+	Stroke     Stroke // 泳式
+	Relay      bool   // 是否為接力
+	RelayCount int    // 接力棒數
+	Round      Round  // 賽次
+}
+
+var (
+	eventReg = regexp.MustCompile(`(?:(\d+)\s*[×xX*]\s*)?(\d+)\s*(?:公尺|M|m)\s*(?:個人)?(自由式|仰式|蛙式|蝶式|混合式)\s*(接力)?`)
+
+	strokeNames = map[string]Stroke{
+		"自由式": StrokeFreestyle,
+		"仰式":  StrokeBackstroke,
+		"蛙式":  StrokeBreaststroke,
+		"蝶式":  StrokeButterfly,
+		"混合式": StrokeMedley,
+	}
+
+	strokeLabels = map[Stroke]string{
+		StrokeFreestyle:    "自由式",
+		StrokeBackstroke:   "仰式",
+		StrokeBreaststroke: "蛙式",
+		StrokeButterfly:    "蝶式",
+		StrokeMedley:       "混合式",
+	}
+
+	// roundNames 依比對順序排列，較長的名稱必須在前面 (例如 "快組計時決賽" 包含 "決賽")
+	roundNames = []struct {
+		name  string
+		round Round
+	}{
+		{"快組計時決賽", RoundFastHeatTimedFinal},
+		{"計時決賽", RoundTimedFinal},
+		{"準決賽", RoundSemifinal},
+		{"複賽", RoundSemifinal},
+		{"預賽", RoundHeat},
+		{"決賽", RoundFinal},
+	}
+)
+
+// ParseEvent 解析項目名稱，例如 "11 & 12歲級女子組游泳 4×50公尺混合式接力 計時決賽"
+func ParseEvent(raceName string) Event {
+	var event Event
+	event.Round = ParseRound(raceName)
+	const expectMatchSize = 5
+	matches := eventReg.FindStringSubmatch(raceName)
+	if len(matches) < expectMatchSize {
+		return event
+	}
+	event.Distance, _ = strconv.Atoi(matches[2])
+	event.Stroke = strokeNames[matches[3]]
+	if matches[1] != "" || matches[4] != "" {
+		event.Relay = true
+		event.RelayCount, _ = strconv.Atoi(matches[1])
+	}
+	return event
+}
+
+// ParseRound 由項目名稱找出賽次，忽略空白 (例如 "決 賽")，找不到時回傳空字串
+func ParseRound(raceName string) Round {
+	raceName = strings.Join(strings.Fields(raceName), "")
+	for _, r := range roundNames {
+		if strings.Contains(raceName, r.name) {
+			return r.round
+		}
+	}
+	return ""
+}
+
+// Label 回傳賽次的中文名稱，例如 "計時決賽"，未知的賽次回傳空字串
+func (r Round) Label() string {
+	for _, name := range roundNames {
+		if name.round == r {
+			return name.name
+		}
+	}
+	return ""
+}
+
+// IsQualifier 表示此賽次的名次與積分不是最終結果
+func (r Round) IsQualifier() bool {
+	return r == RoundHeat || r == RoundFastHeatTimedFinal
+}
+
+// Label 回傳 CTSA 寫法的項目名稱 (不含賽次)，例如 "200公尺自由式"、"200公尺個人混合式"、"4×50公尺混合式接力"，
+// 距離或泳式不明時回傳空字串
+func (e Event) Label() string {
+	stroke, ok := strokeLabels[e.Stroke]
+	if !ok || e.Distance <= 0 {
+		return ""
+	}
+	switch {
+	case e.Relay:
+		return fmt.Sprintf("%d×%d公尺%s接力", e.RelayCount, e.Distance, stroke)
+	case e.Stroke == StrokeMedley:
+		return fmt.Sprintf("%d公尺個人%s", e.Distance, stroke)
+	default:
+		return fmt.Sprintf("%d公尺%s", e.Distance, stroke)
+	}
+}
+
+// AgeGroupLabel 回傳 CTSA 寫法的年齡組，例如 "11&12歲級"、"18及以上歲級"、"10及以下歲級"，
+// 負數代表該端不限，兩端都不限時回傳空字串
+func AgeGroupLabel(minAge, maxAge int) string {
+	switch {
+	case minAge < 0 && maxAge < 0:
+		return ""
+	case maxAge < 0:
+		return fmt.Sprintf("%d及以上歲級", minAge)
+	case minAge < 0:
+		return fmt.Sprintf("%d及以下歲級", maxAge)
+	case minAge == maxAge:
+		return fmt.Sprintf("%d歲級", minAge)
+	case maxAge == minAge+1:
+		return fmt.Sprintf("%d&%d歲級", minAge, maxAge)
+	default:
+		return fmt.Sprintf("%d~%d歲級", minAge, maxAge)
+	}
+}
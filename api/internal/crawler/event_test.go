@@ -0,0 +1,89 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected Event
+	}{
+		{
+			"11 & 12歲級女子組游泳 200公尺自由式 計時決賽",
+			Event{Distance: 200, Stroke: StrokeFreestyle, Round: RoundTimedFinal},
+		},
+		{
+			"10及以下歲級男子組游泳 4×50公尺混合式接力 計時決賽",
+			Event{Distance: 50, Stroke: StrokeMedley, Relay: true, RelayCount: 4, Round: RoundTimedFinal},
+		},
+		{
+			"13 & 14歲級女子組游泳 4x100公尺自由式接力 決賽",
+			Event{Distance: 100, Stroke: StrokeFreestyle, Relay: true, RelayCount: 4, Round: RoundFinal},
+		},
+		{
+			"公開組男子組 200公尺個人混合式 預賽",
+			Event{Distance: 200, Stroke: StrokeMedley, Round: RoundHeat},
+		},
+		{
+			"18及以上歲級女子組 50公尺蝶式 快組計時決賽",
+			Event{Distance: 50, Stroke: StrokeButterfly, Round: RoundFastHeatTimedFinal},
+		},
+		{
+			"公開組男子組 100公尺仰式 準決賽",
+			Event{Distance: 100, Stroke: StrokeBackstroke, Round: RoundSemifinal},
+		},
+		{
+			"公開組女子組 100公尺蛙式",
+			Event{Distance: 100, Stroke: StrokeBreaststroke},
+		},
+		{
+			"開幕典禮",
+			Event{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseEvent(tt.name))
+		})
+	}
+}
+
+func TestRaceInfo_IsQualifier(t *testing.T) {
+	assert.True(t, (&RaceInfo{RaceName: "公開組男子組 50公尺自由式 預賽"}).IsQualifier())
+	assert.True(t, (&RaceInfo{RaceName: "公開組男子組 50公尺自由式 快組計時決賽"}).IsQualifier())
+	assert.False(t, (&RaceInfo{RaceName: "公開組男子組 50公尺自由式 計時決賽"}).IsQualifier())
+	assert.False(t, (&RaceInfo{RaceName: "公開組男子組 50公尺自由式 決賽"}).IsQualifier())
+}
+
+func TestParseRound(t *testing.T) {
+	assert.Equal(t, RoundFinal, ParseRound("公開組男子組 50公尺自由式 決賽"))
+	assert.Equal(t, RoundFinal, ParseRound("公開組男子組 50公尺自由式 決 賽"))
+	assert.Equal(t, RoundTimedFinal, ParseRound("計時　決賽"))
+	assert.Empty(t, ParseRound("公開組男子組 50公尺自由式"))
+}
+
+func TestRound_Label(t *testing.T) {
+	assert.Equal(t, "計時決賽", RoundTimedFinal.Label())
+	assert.Equal(t, "準決賽", RoundSemifinal.Label())
+	assert.Equal(t, "快組計時決賽", RoundFastHeatTimedFinal.Label())
+	assert.Empty(t, Round("").Label())
+}
+
+func TestEvent_Label(t *testing.T) {
+	for _, name := range []string{"200公尺自由式", "200公尺個人混合式", "4×50公尺混合式接力", "4×100公尺自由式接力"} {
+		assert.Equal(t, name, ParseEvent(name).Label())
+	}
+	assert.Empty(t, Event{}.Label())
+}
+
+func TestAgeGroupLabel(t *testing.T) {
+	assert.Equal(t, "11&12歲級", AgeGroupLabel(11, 12))
+	assert.Equal(t, "18及以上歲級", AgeGroupLabel(18, -1))
+	assert.Equal(t, "10及以下歲級", AgeGroupLabel(-1, 10))
+	assert.Equal(t, "9歲級", AgeGroupLabel(9, 9))
+	assert.Equal(t, "13~15歲級", AgeGroupLabel(13, 15))
+	assert.Empty(t, AgeGroupLabel(-1, -1))
+}
@@ -0,0 +1,303 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTimeout        = 5 * time.Second
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = 30 * time.Second
+	defaultBackoffFactor  = 2
+	defaultRatePerSecond  = 5
+	defaultRateBurst      = 1
+)
+
+// RetryPolicy 決定單一請求失敗 (逾時、5xx、429) 時的重試方式
+type RetryPolicy struct {
+	MaxAttempts    int           // 包含第一次請求的總次數
+	Timeout        time.Duration // 每次請求的逾時
+	InitialBackoff time.Duration // 第一次重試前的等待時間
+	MaxBackoff     time.Duration // 等待時間上限，也是 Retry-After 的上限
+	Multiplier     float64       // 每次重試等待時間的倍數
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    defaultMaxAttempts,
+		Timeout:        defaultTimeout,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		Multiplier:     defaultBackoffFactor,
+	}
+}
+
+// backoff 回傳第 attempt 次 (從 1 開始) 失敗後的等待時間
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := float64(p.InitialBackoff)
+	for range attempt - 1 {
+		wait *= p.Multiplier
+		if p.MaxBackoff > 0 && wait >= float64(p.MaxBackoff) {
+			return p.MaxBackoff
+		}
+	}
+	return time.Duration(wait)
+}
+
+// retryWait 回傳第 attempt 次失敗後重試前的等待時間：伺服器要求的 Retry-After 較長時以它為準，
+// 但不超過 MaxBackoff，避免過大的 Retry-After 讓 worker 長時間停住
+func (p RetryPolicy) retryWait(attempt int, retryAfter time.Duration) time.Duration {
+	if p.MaxBackoff > 0 {
+		retryAfter = min(retryAfter, p.MaxBackoff)
+	}
+	return max(p.backoff(attempt), retryAfter)
+}
+
+// RateLimit 是對單一主機的 token bucket 限流設定，RequestsPerSecond <= 0 代表不限流
+type RateLimit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+func DefaultRateLimit() RateLimit {
+	return RateLimit{
+		RequestsPerSecond: defaultRatePerSecond,
+		Burst:             defaultRateBurst,
+	}
+}
+
+// HostRateLimiter 對每個主機各自維護一個 token bucket，可以由多個來源共用
+type HostRateLimiter struct {
+	rate RateLimit
+
+	mu       sync.Mutex
+	limiters map[string]*tokenBucket
+}
+
+func NewHostRateLimiter(rate RateLimit) *HostRateLimiter {
+	return &HostRateLimiter{
+		rate:     rate,
+		limiters: make(map[string]*tokenBucket),
+	}
+}
+
+// Wait 阻塞直到可以對 host 送出下一個請求或 ctx 取消
+func (l *HostRateLimiter) Wait(ctx context.Context, host string) error {
+	l.mu.Lock()
+	limiter, ok := l.limiters[host]
+	if !ok {
+		limiter = newTokenBucket(l.rate)
+		l.limiters[host] = limiter
+	}
+	l.mu.Unlock()
+	return limiter.Wait(ctx)
+}
+
+// fetcher 是爬蟲共用的 HTTP 層，負責重試、Retry-After 與每個主機的限流
+type fetcher struct {
+	client  *http.Client
+	retry   RetryPolicy
+	limiter *HostRateLimiter
+}
+
+func newFetcher(client *http.Client, retry RetryPolicy, limiter *HostRateLimiter) *fetcher {
+	if retry.MaxAttempts < 1 {
+		retry.MaxAttempts = 1
+	}
+	return &fetcher{
+		client:  client,
+		retry:   retry,
+		limiter: limiter,
+	}
+}
+
+// Get 送出 GET 請求並回傳完整的回應內容
+func (f *fetcher) Get(ctx context.Context, target string) ([]byte, error) {
+	return f.do(ctx, func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	})
+}
+
+// PostForm 送出表單 POST 請求並回傳完整的回應內容
+func (f *fetcher) PostForm(ctx context.Context, target string, form url.Values) ([]byte, error) {
+	body := form.Encode()
+	return f.do(ctx, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	})
+}
+
+// retryableError 表示可以重試的失敗，retryAfter 為伺服器要求的等待時間
+type retryableError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+func (f *fetcher) do(ctx context.Context, newRequest func(ctx context.Context) (*http.Request, error)) ([]byte, error) {
+	var lastErr error
+	for attempt := 1; attempt <= f.retry.MaxAttempts; attempt++ {
+		body, err := f.attempt(ctx, newRequest)
+		if err == nil {
+			return body, nil
+		}
+		lastErr = err
+		var retryErr *retryableError
+		if !errors.As(err, &retryErr) || attempt == f.retry.MaxAttempts {
+			break
+		}
+		wait := f.retry.retryWait(attempt, retryErr.retryAfter)
+		log.Printf("⚠️ 請求失敗，%s 後重試 (%d/%d): %v", wait, attempt, f.retry.MaxAttempts, err)
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+func (f *fetcher) attempt(
+	ctx context.Context, newRequest func(ctx context.Context) (*http.Request, error),
+) ([]byte, error) {
+	req, err := newRequest(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("構造請求失敗: %w", err)
+	}
+	// 先等待限流再開始計算逾時，排隊的時間不算在請求的逾時內
+	if err := f.limiter.Wait(ctx, req.URL.Host); err != nil {
+		return nil, err
+	}
+	if f.retry.Timeout > 0 {
+		attemptCtx, cancel := context.WithTimeout(ctx, f.retry.Timeout)
+		defer cancel()
+		req = req.WithContext(attemptCtx)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, classifyError(ctx, fmt.Errorf("%s 請求失敗: %w", req.Method, err))
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("%s 請求返回非預期狀態碼: %d %s", req.Method, resp.StatusCode, resp.Status)
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			return nil, &retryableError{err: err, retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+		}
+		return nil, err
+	}
+
+	// 建立一個記憶體暫存區
+	var buf bytes.Buffer
+
+	// io.Copy 會在 context 有效期間，把資料從網路串流搬到記憶體 buf
+	// 如果此時 context cancel，io.Copy 會回傳錯誤
+	if _, err := io.Copy(&buf, resp.Body); err != nil {
+		return nil, classifyError(ctx, fmt.Errorf("暫存資料失敗 (可能超時或連線中斷): %w", err))
+	}
+	return buf.Bytes(), nil
+}
+
+// classifyError 判斷網路錯誤是否可以重試，呼叫端的 ctx 已取消時不重試
+func classifyError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return &retryableError{err: err}
+	}
+	return err
+}
+
+// parseRetryAfter 解析 Retry-After header (秒數或 HTTP 日期)
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	burst := float64(max(limit.Burst, 1))
+	return &tokenBucket{
+		rate:   limit.RequestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// Wait 阻塞直到取得一個 token 或 ctx 取消
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	if b.rate <= 0 {
+		return nil
+	}
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
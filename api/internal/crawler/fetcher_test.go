@@ -0,0 +1,213 @@
+package crawler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		Timeout:        time.Second,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+		Multiplier:     2,
+	}
+}
+
+func TestFetcher_retryOn5xx(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	f := newFetcher(server.Client(), testRetryPolicy(), NewHostRateLimiter(RateLimit{}))
+	body, err := f.Get(t.Context(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestFetcher_giveUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	f := newFetcher(server.Client(), testRetryPolicy(), NewHostRateLimiter(RateLimit{}))
+	_, err := f.Get(t.Context(), server.URL)
+	require.Error(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestFetcher_noRetryOn4xx(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	f := newFetcher(server.Client(), testRetryPolicy(), NewHostRateLimiter(RateLimit{}))
+	_, err := f.Get(t.Context(), server.URL)
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestFetcher_retryAfter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	policy := testRetryPolicy()
+	policy.MaxBackoff = 2 * time.Second
+	f := newFetcher(server.Client(), policy, NewHostRateLimiter(RateLimit{}))
+	start := time.Now()
+	_, err := f.Get(t.Context(), server.URL)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestFetcher_retryAfterCappedAtMaxBackoff(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	f := newFetcher(server.Client(), testRetryPolicy(), NewHostRateLimiter(RateLimit{}))
+	start := time.Now()
+	_, err := f.Get(t.Context(), server.URL)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, 50*time.Millisecond, testRetryPolicy().retryWait(1, time.Hour))
+	assert.Equal(t, 20*time.Millisecond, testRetryPolicy().retryWait(2, 0))
+}
+
+func TestFetcher_retryOnTimeout(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	policy := testRetryPolicy()
+	policy.Timeout = 50 * time.Millisecond
+	f := newFetcher(server.Client(), policy, NewHostRateLimiter(RateLimit{}))
+	body, err := f.Get(t.Context(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestFetcher_postFormReplaysBody(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "151", r.PostForm.Get("id"))
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	f := newFetcher(server.Client(), testRetryPolicy(), NewHostRateLimiter(RateLimit{}))
+	_, err := f.PostForm(t.Context(), server.URL, url.Values{"id": {"151"}})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestFetcher_rateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	f := newFetcher(server.Client(), testRetryPolicy(), NewHostRateLimiter(RateLimit{RequestsPerSecond: 20, Burst: 1}))
+	start := time.Now()
+	for range 5 {
+		_, err := f.Get(t.Context(), server.URL)
+		require.NoError(t, err)
+	}
+	// 第一個請求使用 burst，其餘 4 個每個至少等待 50ms
+	assert.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+}
+
+func TestFetcher_rateLimitWaitNotCountedInTimeout(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	// 第二個請求在限流排隊約 200ms，超過每次請求 50ms 的逾時，仍不應逾時重試
+	policy := testRetryPolicy()
+	policy.Timeout = 50 * time.Millisecond
+	f := newFetcher(server.Client(), policy, NewHostRateLimiter(RateLimit{RequestsPerSecond: 5, Burst: 1}))
+	for range 2 {
+		_, err := f.Get(t.Context(), server.URL)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(""))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon"))
+	future := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	assert.Greater(t, parseRetryAfter(future), 59*time.Minute)
+}
+
+func TestCtsaSource_httptest(t *testing.T) {
+	data, err := os.ReadFile("test_file/ctsa/get_race_ids.html")
+	require.NoError(t, err)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	source, err := newCtsaSource(WithBaseURL(server.URL), WithRetryPolicy(testRetryPolicy()))
+	require.NoError(t, err)
+	competitions, err := source.ListCompetitions(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, competitions, 15)
+}
@@ -0,0 +1,115 @@
+package crawler
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrIncomplete 表示爬取工作結束時仍有失敗的項目，可以用 Resume 重試
+var ErrIncomplete = errors.New("crawl job incomplete")
+
+type JobStatus string
+
+const (
+	JobStatusPending JobStatus = "pending"
+	JobStatusRunning JobStatus = "running"
+	JobStatusDone    JobStatus = "done"
+	JobStatusFailed  JobStatus = "failed"
+	JobStatusSkipped JobStatus = "skipped" // 已經爬取過
+)
+
+// Job 記錄一次爬取工作的進度，中斷或失敗後可以只重試未完成的項目
+type Job struct {
+	ID           string
+	Source       string
+	Year         string
+	Status       JobStatus
+	Error        string
+	Attempts     int
+	StartedAt    time.Time
+	FinishedAt   time.Time
+	Competitions []*JobCompetition
+}
+
+// JobCompetition 是工作中單一比賽的進度
+type JobCompetition struct {
+	Competition CompetitionInfo
+	Status      JobStatus
+	Error       string
+	Attempts    int
+	Races       []*JobRace
+}
+
+// JobRace 是工作中單一項目的進度
+type JobRace struct {
+	Race     RaceInfo
+	Status   JobStatus
+	Error    string
+	Attempts int
+	Unparsed []string // 最後一次解析時無法解析而略過的列
+}
+
+// JobStore 保存爬取工作的進度
+type JobStore interface {
+	// SaveJob 儲存工作進度，job.ID 為空時由 JobStore 指派
+	SaveJob(job *Job) error
+	GetJob(id string) (*Job, error)
+}
+
+// JobProgress 是各狀態的項目數量
+type JobProgress struct {
+	Competitions map[JobStatus]int
+	Races        map[JobStatus]int
+}
+
+func (j *Job) Progress() JobProgress {
+	progress := JobProgress{
+		Competitions: make(map[JobStatus]int),
+		Races:        make(map[JobStatus]int),
+	}
+	for _, competition := range j.Competitions {
+		progress.Competitions[competition.Status]++
+		for _, race := range competition.Races {
+			progress.Races[race.Status]++
+		}
+	}
+	return progress
+}
+
+func (c *JobCompetition) fail(err error) {
+	c.Status = JobStatusFailed
+	c.Error = err.Error()
+}
+
+// finish 依照項目的狀態決定比賽的狀態
+func (c *JobCompetition) finish() {
+	c.Status = JobStatusDone
+	c.Error = ""
+	for _, race := range c.Races {
+		if race.Status != JobStatusDone && race.Status != JobStatusSkipped {
+			c.Status = JobStatusFailed
+			return
+		}
+	}
+}
+
+func (r *JobRace) fail(err error) {
+	r.Status = JobStatusFailed
+	r.Error = err.Error()
+}
+
+func (r *JobRace) setUnparsed(rows []UnparsedRow) {
+	r.Unparsed = nil
+	for _, row := range rows {
+		r.Unparsed = append(r.Unparsed, row.String())
+	}
+}
+
+func (r *JobRace) finish(status JobStatus) {
+	r.Status = status
+	r.Error = ""
+}
+
+func isFinished(status JobStatus) bool {
+	return status == JobStatusDone || status == JobStatusSkipped
+}
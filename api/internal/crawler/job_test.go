@@ -0,0 +1,87 @@
+package crawler
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockJobStore keeps jobs in memory
+type mockJobStore struct {
+	jobs map[string]*Job
+}
+
+func (m *mockJobStore) SaveJob(job *Job) error {
+	if job.ID == "" {
+		job.ID = fmt.Sprintf("job-%d", len(m.jobs)+1)
+	}
+	m.jobs[job.ID] = job
+	return nil
+}
+
+func (m *mockJobStore) GetJob(id string) (*Job, error) {
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("job %s not found", id)
+	}
+	return job, nil
+}
+
+func TestCrawler_Resume(t *testing.T) {
+	source := &mockSource{
+		competitions: []CompetitionInfo{{ID: "1", Name: "A"}, {ID: "2", Name: "B"}},
+		races: map[string][]RaceInfo{
+			"1": {{CompetitionName: "A", RaceName: "a1", URL: "u1"}, {CompetitionName: "A", RaceName: "a2", URL: "u2"}},
+			"2": {{CompetitionName: "B", RaceName: "b1", URL: "u3"}},
+		},
+		failures: map[string]int{"u2": 1},
+	}
+	var mu sync.Mutex
+	fetched := map[string]int{}
+	mockP := &mockPersistence{
+		persistRace: func(_ string, race *Race) error {
+			mu.Lock()
+			defer mu.Unlock()
+			fetched[race.EventName]++
+			return nil
+		},
+		isCrawled: func(string) (bool, error) { return false, nil },
+	}
+	store := &mockJobStore{jobs: map[string]*Job{}}
+	c, err := New(source, WithPersistence(mockP), WithJobStore(store), WithYear("114"))
+	require.NoError(t, err)
+
+	err = c.Crawl(t.Context())
+	require.ErrorIs(t, err, ErrIncomplete)
+	job, err := store.GetJob("job-1")
+	require.NoError(t, err)
+	assert.Equal(t, JobStatusFailed, job.Status)
+	assert.Equal(t, "114", job.Year)
+	assert.Equal(t, "mock", job.Source)
+	progress := job.Progress()
+	assert.Equal(t, 1, progress.Competitions[JobStatusFailed])
+	assert.Equal(t, 1, progress.Competitions[JobStatusDone])
+	assert.Equal(t, 1, progress.Races[JobStatusFailed])
+	assert.Equal(t, 2, progress.Races[JobStatusDone])
+	assert.Contains(t, job.Competitions[0].Races[1].Error, "fetch u2 fail")
+
+	require.NoError(t, c.Resume(t.Context(), "job-1"))
+	assert.Equal(t, JobStatusDone, job.Status)
+	assert.Equal(t, 2, job.Attempts)
+	assert.Equal(t, 2, job.Competitions[0].Races[1].Attempts)
+	assert.Equal(t, 1, job.Competitions[0].Races[0].Attempts)
+	// 只有失敗的項目會重新抓取
+	assert.Equal(t, map[string]int{"a1": 1, "a2": 1, "b1": 1}, fetched)
+}
+
+func TestCrawler_noCompetitions(t *testing.T) {
+	store := &mockJobStore{jobs: map[string]*Job{}}
+	c, err := New(&mockSource{}, WithPersistence(&mockPersistence{}), WithJobStore(store))
+	require.NoError(t, err)
+	require.Error(t, c.Crawl(t.Context()))
+	require.Len(t, store.jobs, 1)
+	assert.Equal(t, JobStatusFailed, store.jobs["job-1"].Status)
+}
@@ -1,23 +1,117 @@
 package crawler
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
-type Option func(*ctsaCrawler)
+type options struct {
+	baseURL         string
+	year            string
+	persistence     Persistence
+	archive         Archive
+	jobStore        JobStore
+	recheck         time.Duration
+	poolTypes       *PoolTypeResolver
+	retry           RetryPolicy
+	rate            RateLimit
+	hostLimiter     *HostRateLimiter
+	mockGetResponse func(url string) (io.Reader, error)
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		retry: DefaultRetryPolicy(),
+		rate:  DefaultRateLimit(),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
 
+type Option func(*options)
+
+// WithBaseURL 覆寫來源的入口網址
 func WithBaseURL(baseURL string) Option {
-	return func(c *ctsaCrawler) {
-		c.baseUrl = baseURL
+	return func(o *options) {
+		o.baseURL = baseURL
+	}
+}
+
+// WithYear 指定要爬取的年度 (民國年)，空字串代表來源的當前年度
+func WithYear(year string) Option {
+	return func(o *options) {
+		o.year = year
 	}
 }
 
 func WithPersistence(persistence Persistence) Option {
-	return func(c *ctsaCrawler) {
-		c.persistence = persistence
+	return func(o *options) {
+		o.persistence = persistence
+	}
+}
+
+// WithArchive 在解析前將原始成績報告存入 Archive
+func WithArchive(archive Archive) Option {
+	return func(o *options) {
+		o.archive = archive
+	}
+}
+
+// WithJobStore 將爬取工作的進度保存到 JobStore，之後可以用 Crawler.Resume 繼續
+func WithJobStore(store JobStore) Option {
+	return func(o *options) {
+		o.jobStore = store
+	}
+}
+
+// WithRetryPolicy 設定 HTTP 請求失敗時的重試策略
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = policy
+	}
+}
+
+// WithRateLimit 設定對每個主機的請求速率上限
+func WithRateLimit(limit RateLimit) Option {
+	return func(o *options) {
+		o.rate = limit
+	}
+}
+
+// WithHostRateLimiter 與其他來源共用同一組主機限流，設定後 WithRateLimit 不再生效
+func WithHostRateLimiter(limiter *HostRateLimiter) Option {
+	return func(o *options) {
+		o.hostLimiter = limiter
+	}
+}
+
+// WithRecheck 開啟重新檢查模式：比賽日期在 window 內的已爬取成績報告會重新抓取，
+// 與已儲存的資料比對後套用更正，Persistence 必須實作 RaceUpdater
+func WithRecheck(window time.Duration) Option {
+	return func(o *options) {
+		o.recheck = window
+	}
+}
+
+// WithPoolTypes 使用設定的規則判斷比賽的水道，沒有設定時只依競賽名稱判斷
+func WithPoolTypes(resolver *PoolTypeResolver) Option {
+	return func(o *options) {
+		o.poolTypes = resolver
+	}
+}
+
+// rateLimiter 回傳共用的主機限流，沒有設定時依 RateLimit 建立一組新的
+func (o *options) rateLimiter() *HostRateLimiter {
+	if o.hostLimiter != nil {
+		return o.hostLimiter
 	}
+	return NewHostRateLimiter(o.rate)
 }
 
 func withGetResponse(mock func(url string) (io.Reader, error)) Option {
-	return func(c *ctsaCrawler) {
-		c.mockGetResponse = mock
+	return func(o *options) {
+		o.mockGetResponse = mock
 	}
 }
@@ -0,0 +1,55 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/db/mongo/models"
+)
+
+func NewMongoArchive(rawPageStore mongo.RawPageStore) crawler.Archive {
+	return &mongoArchive{rawPageStore}
+}
+
+type mongoArchive struct {
+	rawPageStore mongo.RawPageStore
+}
+
+func (m *mongoArchive) ArchivePage(page *crawler.ArchivedPage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	rawPage := models.NewRawPage()
+	rawPage.Source = page.Source
+	rawPage.URL = page.Info.URL
+	rawPage.Year = page.Year
+	rawPage.CompetitionID = page.Info.CompetitionID
+	rawPage.CompetitionName = page.Info.CompetitionName
+	rawPage.RaceName = page.Info.RaceName
+	rawPage.Hash = page.Hash
+	rawPage.Body = page.Body
+	rawPage.FetchedAt = page.FetchedAt
+	err := m.rawPageStore.SaveRawPage(ctx, rawPage)
+	if err != nil {
+		return fmt.Errorf("save raw page fail: %w", err)
+	}
+	return nil
+}
+
+// RawPageToArchivedPage 將封存在 MongoDB 的原始成績報告轉回 crawler.ArchivedPage
+func RawPageToArchivedPage(rawPage *models.RawPage) *crawler.ArchivedPage {
+	return &crawler.ArchivedPage{
+		Source: rawPage.Source,
+		Info: crawler.RaceInfo{
+			CompetitionID:   rawPage.CompetitionID,
+			CompetitionName: rawPage.CompetitionName,
+			RaceName:        rawPage.RaceName,
+			URL:             rawPage.URL,
+		},
+		Year:      rawPage.Year,
+		Hash:      rawPage.Hash,
+		Body:      rawPage.Body,
+		FetchedAt: rawPage.FetchedAt,
+	}
+}
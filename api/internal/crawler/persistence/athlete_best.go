@@ -0,0 +1,63 @@
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"aquascore/api/internal/athlete"
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/db/mongo/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// UpdateAthleteBests 以選手所有的個人項目成績重新計算並取代選手的個人最佳成績，見 athlete.PersonalBests
+func UpdateAthleteBests(ctx context.Context, store mongo.AthleteBestStore, athleteIDs []bson.ObjectID) error {
+	if len(athleteIDs) == 0 {
+		return nil
+	}
+	swims, err := store.GetAthleteSwims(ctx, athleteIDs)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var bests []*models.AthleteBest
+	for _, id := range athleteIDs {
+		bests = append(bests, athlete.PersonalBests(id, swims, now)...)
+	}
+	if err := store.ReplaceAthleteBests(ctx, athleteIDs, bests); err != nil {
+		return fmt.Errorf("replace athlete bests fail: %w", err)
+	}
+	return nil
+}
+
+// updateAthleteBests 重新計算項目中每位選手的個人最佳成績，previous 為更正前連結的選手。
+// 個人最佳成績是由成績計算出的資料，失敗只記錄 log，不影響項目本身的寫入
+func (m *mongoPersistence) updateAthleteBests(race *crawler.Race, links []resultLinks, previous []bson.ObjectID) {
+	if race.Event.Relay {
+		return
+	}
+	ids := slices.Clone(previous)
+	for _, link := range links {
+		ids = append(ids, link.athleteIDs...)
+	}
+	slices.SortFunc(ids, func(a, b bson.ObjectID) int { return bytes.Compare(a[:], b[:]) })
+	ids = slices.Compact(slices.DeleteFunc(ids, bson.ObjectID.IsZero))
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = "best|" + id.Hex()
+	}
+	unlock := m.derivedLocks.lock(keys...)
+	defer unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+	if err := UpdateAthleteBests(ctx, m.athleteBestStore, ids); err != nil {
+		log.Printf("⚠️ %s [%s] 更新個人最佳成績失敗: %v", race.CompetitionName, race.EventName, err)
+	}
+}
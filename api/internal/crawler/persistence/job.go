@@ -0,0 +1,132 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/db/mongo/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func NewMongoJobStore(crawlJobStore mongo.CrawlJobStore) crawler.JobStore {
+	return &mongoJobStore{crawlJobStore}
+}
+
+type mongoJobStore struct {
+	crawlJobStore mongo.CrawlJobStore
+}
+
+func (m *mongoJobStore) SaveJob(job *crawler.Job) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	crawlJob, err := JobToModelCrawlJob(job)
+	if err != nil {
+		return err
+	}
+	crawlJob.UpdatedAt = time.Now()
+	err = m.crawlJobStore.SaveCrawlJob(ctx, crawlJob)
+	if err != nil {
+		return fmt.Errorf("save crawl job fail: %w", err)
+	}
+	job.ID = crawlJob.ID.Hex()
+	return nil
+}
+
+func (m *mongoJobStore) GetJob(id string) (*crawler.Job, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	crawlJob, err := m.crawlJobStore.FindCrawlJob(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get crawl job fail: %w", err)
+	}
+	if crawlJob == nil {
+		return nil, fmt.Errorf("crawl job %s not found", id)
+	}
+	return ModelCrawlJobToJob(crawlJob), nil
+}
+
+func JobToModelCrawlJob(job *crawler.Job) (*models.CrawlJob, error) {
+	crawlJob := models.NewCrawlJob()
+	if job.ID != "" {
+		oid, err := bson.ObjectIDFromHex(job.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid job id: %w", err)
+		}
+		crawlJob.ID = oid
+	}
+	crawlJob.Source = job.Source
+	crawlJob.Year = job.Year
+	crawlJob.Status = string(job.Status)
+	crawlJob.Error = job.Error
+	crawlJob.Attempts = job.Attempts
+	crawlJob.StartedAt = job.StartedAt
+	crawlJob.FinishedAt = job.FinishedAt
+	crawlJob.Competitions = make([]*models.CrawlJobCompetition, len(job.Competitions))
+	for i, competition := range job.Competitions {
+		races := make([]*models.CrawlJobRace, len(competition.Races))
+		for j, race := range competition.Races {
+			races[j] = &models.CrawlJobRace{
+				CompetitionID:   race.Race.CompetitionID,
+				CompetitionName: race.Race.CompetitionName,
+				RaceName:        race.Race.RaceName,
+				URL:             race.Race.URL,
+				Status:          string(race.Status),
+				Error:           race.Error,
+				Attempts:        race.Attempts,
+				Unparsed:        race.Unparsed,
+			}
+		}
+		crawlJob.Competitions[i] = &models.CrawlJobCompetition{
+			ID:       competition.Competition.ID,
+			Name:     competition.Competition.Name,
+			Status:   string(competition.Status),
+			Error:    competition.Error,
+			Attempts: competition.Attempts,
+			Races:    races,
+		}
+	}
+	return crawlJob, nil
+}
+
+func ModelCrawlJobToJob(crawlJob *models.CrawlJob) *crawler.Job {
+	job := &crawler.Job{
+		ID:           crawlJob.ID.Hex(),
+		Source:       crawlJob.Source,
+		Year:         crawlJob.Year,
+		Status:       crawler.JobStatus(crawlJob.Status),
+		Error:        crawlJob.Error,
+		Attempts:     crawlJob.Attempts,
+		StartedAt:    crawlJob.StartedAt,
+		FinishedAt:   crawlJob.FinishedAt,
+		Competitions: make([]*crawler.JobCompetition, len(crawlJob.Competitions)),
+	}
+	for i, competition := range crawlJob.Competitions {
+		races := make([]*crawler.JobRace, len(competition.Races))
+		for j, race := range competition.Races {
+			races[j] = &crawler.JobRace{
+				Race: crawler.RaceInfo{
+					CompetitionID:   race.CompetitionID,
+					CompetitionName: race.CompetitionName,
+					RaceName:        race.RaceName,
+					URL:             race.URL,
+				},
+				Status:   crawler.JobStatus(race.Status),
+				Error:    race.Error,
+				Attempts: race.Attempts,
+				Unparsed: race.Unparsed,
+			}
+		}
+		job.Competitions[i] = &crawler.JobCompetition{
+			Competition: crawler.CompetitionInfo{ID: competition.ID, Name: competition.Name},
+			Status:      crawler.JobStatus(competition.Status),
+			Error:       competition.Error,
+			Attempts:    competition.Attempts,
+			Races:       races,
+		}
+	}
+	return job
+}
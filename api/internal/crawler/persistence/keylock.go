@@ -0,0 +1,28 @@
+package persistence
+
+import (
+	"slices"
+	"sync"
+)
+
+// keyLocks 是依鍵區分的鎖，讓不同項目與選手的推算資料可以同時更新，同一個鍵則依序更新。
+// 鍵的數量以項目與選手數為上限，鎖建立後不會移除
+type keyLocks struct {
+	locks sync.Map
+}
+
+// lock 依排序後的順序取得所有鍵的鎖 (避免互相等待)，回傳釋放鎖的函式
+func (k *keyLocks) lock(keys ...string) (unlock func()) {
+	keys = slices.Compact(slices.Sorted(slices.Values(keys)))
+	mutexes := make([]*sync.Mutex, len(keys))
+	for i, key := range keys {
+		mu, _ := k.locks.LoadOrStore(key, &sync.Mutex{})
+		mutexes[i] = mu.(*sync.Mutex)
+		mutexes[i].Lock()
+	}
+	return func() {
+		for i := len(mutexes) - 1; i >= 0; i-- {
+			mutexes[i].Unlock()
+		}
+	}
+}
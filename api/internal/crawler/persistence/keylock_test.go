@@ -0,0 +1,45 @@
+package persistence
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyLocks_lock(t *testing.T) {
+	var locks keyLocks
+	unlock := locks.lock("b", "a", "a")
+
+	// 不同的鍵不需要等待
+	done := make(chan struct{})
+	go func() {
+		locks.lock("c")()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("lock of another key blocked")
+	}
+
+	// 同一個鍵等到釋放後才取得
+	var wg sync.WaitGroup
+	acquired := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		locks.lock("a", "c")()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("lock of a held key acquired")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	wg.Wait()
+	_, ok := <-acquired
+	assert.False(t, ok)
+}
@@ -3,55 +3,123 @@ package persistence
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
+	"aquascore/api/internal/athlete"
 	"aquascore/api/internal/crawler"
 	"aquascore/api/internal/db/mongo"
 	"aquascore/api/internal/db/mongo/models"
+	"aquascore/api/internal/team"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
-const defaultTimeout = time.Second * 5
+const (
+	defaultTimeout = time.Second * 5
+	// resolveTimeout 是比對一個項目所有選手的時間上限，每位選手都需要查詢與更新 athlete
+	resolveTimeout = time.Second * 30
+)
 
-func NewMongoPersistence(raceStore mongo.RaceStore, crawlLogStore mongo.CrawlLogStore) crawler.Persistence {
-	return &mongoPersistence{raceStore, crawlLogStore}
+func NewMongoPersistence(
+	raceStore mongo.RaceStore, crawlLogStore mongo.CrawlLogStore, competitionStore mongo.CompetitionStore,
+	recordStore mongo.RecordStore, athleteBestStore mongo.AthleteBestStore,
+	athleteResolver *athlete.Resolver, teamNormalizer *team.Normalizer,
+) crawler.Persistence {
+	return &mongoPersistence{
+		raceStore:        raceStore,
+		crawlLogStore:    crawlLogStore,
+		competitionStore: competitionStore,
+		recordStore:      recordStore,
+		athleteBestStore: athleteBestStore,
+		athleteResolver:  athleteResolver,
+		teamNormalizer:   teamNormalizer,
+	}
 }
 
 type mongoPersistence struct {
-	raceStore     mongo.RaceStore
-	crawlLogStore mongo.CrawlLogStore
+	raceStore        mongo.RaceStore
+	crawlLogStore    mongo.CrawlLogStore
+	competitionStore mongo.CompetitionStore
+	recordStore      mongo.RecordStore
+	athleteBestStore mongo.AthleteBestStore
+	athleteResolver  *athlete.Resolver
+	teamNormalizer   *team.Normalizer
+	// writeMu 讓寫入項目的交易依序執行：交易中新建立的選手與隊伍在提交前不會被其他交易看到，
+	// 同時寫入會重複建立同一位選手，同一場比賽的項目也會在 competition 上發生寫入衝突。
+	// 交易提交後才推算紀錄與個人最佳成績，推算時不持有 writeMu
+	writeMu sync.Mutex
+	// derivedLocks 讓同一個項目的紀錄與同一位選手的個人最佳成績依序推算，
+	// 後推算的一方一定讀得到先提交的項目，不會以舊的資料覆蓋
+	derivedLocks keyLocks
 }
 
-func (m *mongoPersistence) PersistRace(race *crawler.Race) error {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
-	defer cancel()
-	raceId, err := m.raceStore.SaveRace(ctx, raceToModelRace(race))
-	if err != nil {
-		return fmt.Errorf("save race fail: %w", err)
-	}
-	raceResults := make([]*models.RaceResult, len(race.Results))
-	for i, raceResult := range race.Results {
-		raceResults[i] = raceResultToModelRaceResult(raceId, raceResult)
-	}
-	err = m.raceStore.SaveRaceResults(ctx, raceResults)
+// written 是寫入一個項目後推算資料需要的選手連結，previous 為更正前連結的選手
+type written struct {
+	links    []resultLinks
+	previous []bson.ObjectID
+}
+
+// PersistRace 在同一個交易中連結選手與隊伍、寫入比賽、race、raceResult 與爬取紀錄，
+// 任何一步失敗都不會留下新建立的選手或隊伍；重複寫入同一個項目會覆寫。
+// 交易提交後重新推算項目的紀錄與選手的個人最佳成績
+func (m *mongoPersistence) PersistRace(url string, race *crawler.Race) error {
+	m.writeMu.Lock()
+	links, err := m.persistRace(url, race)
+	m.writeMu.Unlock()
 	if err != nil {
-		return fmt.Errorf("save race results fail: %w", err)
+		return err
 	}
+	m.updateDerived(race, &written{links: links})
 	return nil
 }
 
-func (m *mongoPersistence) CrawlLog(url string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+// persistRace 在交易中寫入項目，呼叫者必須持有 writeMu
+func (m *mongoPersistence) persistRace(url string, race *crawler.Race) ([]resultLinks, error) {
+	var links []resultLinks
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
 	defer cancel()
-	crawlLog := models.NewCrawlLog()
-	crawlLog.URL = url
-	crawlLog.CreatedAt = time.Now()
-	err := m.crawlLogStore.SaveCrawlLog(ctx, crawlLog)
+	err := mongo.RunInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		links, err = m.resolveResults(ctx, race)
+		if err != nil {
+			return err
+		}
+		competitionID, err := m.saveCompetition(ctx, race)
+		if err != nil {
+			return err
+		}
+		modelRace := raceToModelRace(race)
+		modelRace.CompetitionID = competitionID
+		raceId, err := m.raceStore.UpsertRace(ctx, modelRace)
+		if err != nil {
+			return fmt.Errorf("save race fail: %w", err)
+		}
+		raceResults := raceResultsToModelRaceResults(raceId, race.Results, links)
+		err = m.raceStore.ReplaceRaceResults(ctx, raceId, raceResults)
+		if err != nil {
+			return fmt.Errorf("save race results fail: %w", err)
+		}
+		crawlLog := models.NewCrawlLog()
+		crawlLog.URL = url
+		crawlLog.RaceID = raceId
+		crawlLog.CreatedAt = time.Now()
+		err = m.crawlLogStore.SaveCrawlLog(ctx, crawlLog)
+		if err != nil {
+			return fmt.Errorf("save crawl log fail: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("save crawl log fail: %w", err)
+		return nil, err
 	}
-	return nil
+	return links, nil
+}
+
+// updateDerived 重新推算項目的紀錄與選手的個人最佳成績
+func (m *mongoPersistence) updateDerived(race *crawler.Race, w *written) {
+	m.updateRecords(race)
+	m.updateAthleteBests(race, w.links, w.previous)
 }
 
 func (m *mongoPersistence) IsCrawled(url string) (bool, error) {
@@ -67,16 +135,241 @@ func (m *mongoPersistence) IsCrawled(url string) (bool, error) {
 	return true, nil
 }
 
+// RaceDate 由爬取紀錄連結的 race 回傳項目的日期，項目沒有日期時改用比賽最後一天。
+// 舊的爬取紀錄沒有連結 race，改以爬取時間代替
+func (m *mongoPersistence) RaceDate(url string) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	crawlLog, err := m.crawlLogStore.FindOneCrawlLog(ctx, mongo.NewCrawlLogQueryByUrl(url))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("get crawl log fail: %w", err)
+	}
+	if crawlLog == nil {
+		return time.Time{}, nil
+	}
+	if crawlLog.RaceID.IsZero() {
+		return crawlLog.CreatedAt, nil
+	}
+	race, err := m.raceStore.GetRaceWithResultsByID(ctx, crawlLog.RaceID.Hex())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("find race fail: %w", err)
+	}
+	if race == nil {
+		return time.Time{}, nil
+	}
+	if !race.Time.IsZero() || race.CompetitionID.IsZero() {
+		return race.Time, nil
+	}
+	competition, err := m.competitionStore.FindCompetition(ctx, race.CompetitionID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("find competition fail: %w", err)
+	}
+	if competition == nil {
+		return time.Time{}, nil
+	}
+	return competition.EndDate, nil
+}
+
+// UpdateRace 以自然鍵 (來源、年份、競賽名稱、項目名稱與賽次) 找出已儲存的 race，有差異時在同一個交易中連結選手與隊伍、
+// 寫入比賽並套用更正、記錄變更歷史，交易提交後再重新推算紀錄與更正前後選手的個人最佳成績
+func (m *mongoPersistence) UpdateRace(url string, race *crawler.Race) ([]crawler.RaceChange, error) {
+	m.writeMu.Lock()
+	changes, w, err := m.correctRace(url, race)
+	m.writeMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if w != nil {
+		m.updateDerived(race, w)
+	}
+	return changes, nil
+}
+
+// correctRace 在交易中套用更正，沒有寫入時回傳 nil 的 written，呼叫者必須持有 writeMu
+func (m *mongoPersistence) correctRace(url string, race *crawler.Race) ([]crawler.RaceChange, *written, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+	stored, err := m.raceStore.FindRaceWithResults(ctx, mongo.NewRaceQueryByKey(raceToModelRace(race).NaturalKey()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("find race fail: %w", err)
+	}
+	// 已爬取但找不到 race (例如已被刪除)，直接重新寫入
+	if stored == nil {
+		links, err := m.persistRace(url, race)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, &written{links: links}, nil
+	}
+	changes := crawler.DiffRace(AggrRaceToRace(stored), race)
+	if len(changes) == 0 {
+		return nil, nil, nil
+	}
+
+	var links []resultLinks
+	err = mongo.RunInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		links, err = m.resolveResults(ctx, race)
+		if err != nil {
+			return err
+		}
+		competitionID, err := m.saveCompetition(ctx, race)
+		if err != nil {
+			return err
+		}
+		modelRace := raceToModelRace(race)
+		modelRace.ID = stored.ID
+		modelRace.CompetitionID = competitionID
+		modelRace.CreatedAt = stored.CreatedAt
+		raceResults := raceResultsToModelRaceResults(stored.ID, race.Results, links)
+		now := time.Now()
+		modelChanges := make([]*models.RaceChange, len(changes))
+		for i, change := range changes {
+			modelChanges[i] = raceChangeToModelRaceChange(stored.ID, url, race, change, now)
+		}
+		err = m.raceStore.ApplyRaceCorrection(ctx, modelRace, raceResults, modelChanges)
+		if err != nil {
+			return fmt.Errorf("apply race correction fail: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	var previous []bson.ObjectID
+	for _, result := range stored.Results {
+		previous = append(previous, result.AthleteIDs...)
+	}
+	return changes, &written{links: links, previous: previous}, nil
+}
+
+// saveCompetition 建立或更新項目所屬的比賽並回傳比賽 ID
+func (m *mongoPersistence) saveCompetition(ctx context.Context, race *crawler.Race) (bson.ObjectID, error) {
+	competitionID, err := m.competitionStore.UpsertCompetition(ctx, raceToModelCompetition(race))
+	if err != nil {
+		return bson.NilObjectID, fmt.Errorf("save competition fail: %w", err)
+	}
+	return competitionID, nil
+}
+
+// resultLinks 是一筆成績連結到的選手與隊伍
+type resultLinks struct {
+	athleteIDs []bson.ObjectID
+	teamID     bson.ObjectID
+}
+
+// resolveResults 依序回傳每筆成績的選手與隊伍
+func (m *mongoPersistence) resolveResults(ctx context.Context, race *crawler.Race) ([]resultLinks, error) {
+	links := make([]resultLinks, len(race.Results))
+	teamIDs := make(map[string]bson.ObjectID)
+	for i, result := range race.Results {
+		ids, err := ResolveResultAthletes(ctx, m.athleteResolver, race.Year, race.Gender, race.AgeGroup,
+			result.Unit, result.Name)
+		if err != nil {
+			return nil, err
+		}
+		links[i].athleteIDs = ids
+		teamID, ok := teamIDs[result.Unit]
+		if !ok {
+			t, err := m.teamNormalizer.Resolve(ctx, result.Unit)
+			if err != nil {
+				return nil, fmt.Errorf("resolve team fail: %w", err)
+			}
+			if t != nil {
+				teamID = t.ID
+			}
+			teamIDs[result.Unit] = teamID
+		}
+		links[i].teamID = teamID
+	}
+	return links, nil
+}
+
+// ResolveResultAthletes 依姓名順序回傳一筆成績中各選手的 athlete ID
+func ResolveResultAthletes(
+	ctx context.Context, resolver *athlete.Resolver, year, gender, ageGroup, unit string, names []string,
+) ([]bson.ObjectID, error) {
+	ids := make([]bson.ObjectID, 0, len(names))
+	for _, name := range names {
+		id, err := resolver.Resolve(ctx, athlete.Appearance{
+			Name: name, Unit: unit, Gender: gender, AgeGroup: ageGroup, Year: year,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("resolve athlete fail: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// AggrRaceToRace 將資料庫中的項目與成績轉回爬蟲解析的結構
+func AggrRaceToRace(aggr *models.AggrRaceWithResult) *crawler.Race {
+	race := &crawler.Race{
+		Source:          aggr.Source,
+		Organizer:       aggr.Organizer,
+		Year:            aggr.Year,
+		Type:            aggr.Type,
+		CompetitionName: aggr.CompetitionName,
+		Gender:          aggr.Gender,
+		PoolType:        aggr.PoolType,
+		AgeGroup:        aggr.AgeGroup,
+		EventType:       aggr.EventType,
+		EventName:       aggr.EventName,
+		Event:           modelRaceEventToEvent(aggr.RaceEvent),
+		GamesRecord:     aggr.GamesRecord,
+		NationalRecord:  aggr.NationalRecord,
+		Time:            aggr.Time,
+		Results:         make([]*crawler.RaceResult, len(aggr.Results)),
+	}
+	for i, result := range aggr.Results {
+		race.Results[i] = &crawler.RaceResult{
+			Unit:         result.Unit,
+			Name:         result.Name,
+			Record:       result.Record,
+			Rank:         result.Rank,
+			Score:        result.Score,
+			Note:         result.Note,
+			Status:       crawler.ResultStatus(result.Status),
+			StatusReason: result.StatusReason,
+			Team:         result.Team,
+			Legs:         modelRelayLegsToRelayLegs(result.Legs),
+			Splits:       modelSplitsToSplits(result.Splits),
+			ReactionTime: result.ReactionTime,
+		}
+	}
+	return race
+}
+
+func raceChangeToModelRaceChange(
+	raceId bson.ObjectID, url string, race *crawler.Race, change crawler.RaceChange, detectedAt time.Time,
+) *models.RaceChange {
+	modelChange := models.NewRaceChange()
+	modelChange.RaceId = raceId
+	modelChange.Year = race.Year
+	modelChange.CompetitionName = race.CompetitionName
+	modelChange.EventName = race.EventName
+	modelChange.URL = url
+	modelChange.Athlete = change.Athlete
+	modelChange.Field = change.Field
+	modelChange.OldValue = change.OldValue
+	modelChange.NewValue = change.NewValue
+	modelChange.DetectedAt = detectedAt
+	return modelChange
+}
+
 func raceToModelRace(race *crawler.Race) *models.Race {
 	modelRace := models.NewRace()
+	modelRace.Source = race.Source
 	modelRace.Organizer = race.Organizer
 	modelRace.Type = race.Type
 	modelRace.Year = race.Year
 	modelRace.CompetitionName = race.CompetitionName
 	modelRace.Gender = race.Gender
+	modelRace.PoolType = race.PoolType
 	modelRace.AgeGroup = race.AgeGroup
 	modelRace.EventType = race.EventType
 	modelRace.EventName = race.EventName
+	modelRace.RaceEvent = EventToModelRaceEvent(race.Event)
 	modelRace.GamesRecord = race.GamesRecord
 	modelRace.NationalRecord = race.NationalRecord
 	modelRace.Time = race.Time
@@ -84,6 +377,56 @@ func raceToModelRace(race *crawler.Race) *models.Race {
 	return modelRace
 }
 
+func raceToModelCompetition(race *crawler.Race) *models.Competition {
+	competition := models.NewCompetition()
+	competition.Source = race.Source
+	competition.SourceID = race.CompetitionID
+	competition.Name = race.CompetitionName
+	competition.Year = race.Year
+	competition.ADYear = crawler.ADYear(race.Year)
+	competition.StartDate = race.Time
+	competition.EndDate = race.Time
+	competition.Organizer = race.Organizer
+	competition.Venue = race.Venue
+	competition.PoolType = race.PoolType
+	return competition
+}
+
+// EventToModelRaceEvent 將爬蟲解析的項目轉成儲存用的結構
+func EventToModelRaceEvent(event crawler.Event) models.RaceEvent {
+	return models.RaceEvent{
+		Distance:   event.Distance,
+		Stroke:     string(event.Stroke),
+		Relay:      event.Relay,
+		RelayCount: event.RelayCount,
+		Round:      string(event.Round),
+	}
+}
+
+func modelRaceEventToEvent(event models.RaceEvent) crawler.Event {
+	return crawler.Event{
+		Distance:   event.Distance,
+		Stroke:     crawler.Stroke(event.Stroke),
+		Relay:      event.Relay,
+		RelayCount: event.RelayCount,
+		Round:      crawler.Round(event.Round),
+	}
+}
+
+func raceResultsToModelRaceResults(
+	raceId bson.ObjectID, results []*crawler.RaceResult, links []resultLinks,
+) []*models.RaceResult {
+	raceResults := make([]*models.RaceResult, len(results))
+	for i, raceResult := range results {
+		raceResults[i] = raceResultToModelRaceResult(raceId, raceResult)
+		if raceResults[i] != nil {
+			raceResults[i].AthleteIDs = links[i].athleteIDs
+			raceResults[i].TeamID = links[i].teamID
+		}
+	}
+	return raceResults
+}
+
 func raceResultToModelRaceResult(raceId bson.ObjectID, raceResult *crawler.RaceResult) *models.RaceResult {
 	if len(raceResult.Name) == 0 {
 		return nil
@@ -96,5 +439,55 @@ func raceResultToModelRaceResult(raceId bson.ObjectID, raceResult *crawler.RaceR
 	modelRaceResult.Rank = raceResult.Rank
 	modelRaceResult.Record = raceResult.Record
 	modelRaceResult.Score = raceResult.Score
+	modelRaceResult.Team = raceResult.Team
+	modelRaceResult.Legs = relayLegsToModelRelayLegs(raceResult.Legs)
+	modelRaceResult.Splits = splitsToModelSplits(raceResult.Splits)
+	modelRaceResult.ReactionTime = raceResult.ReactionTime
+	modelRaceResult.Status = string(raceResult.Status)
+	modelRaceResult.StatusReason = raceResult.StatusReason
 	return modelRaceResult
 }
+
+func relayLegsToModelRelayLegs(legs []crawler.RelayLeg) []models.RelayLeg {
+	if len(legs) == 0 {
+		return nil
+	}
+	modelLegs := make([]models.RelayLeg, len(legs))
+	for i, leg := range legs {
+		modelLegs[i] = models.RelayLeg{Swimmer: leg.Swimmer, Stroke: string(leg.Stroke), Split: leg.Split}
+	}
+	return modelLegs
+}
+
+func modelRelayLegsToRelayLegs(legs []models.RelayLeg) []crawler.RelayLeg {
+	if len(legs) == 0 {
+		return nil
+	}
+	crawlerLegs := make([]crawler.RelayLeg, len(legs))
+	for i, leg := range legs {
+		crawlerLegs[i] = crawler.RelayLeg{Swimmer: leg.Swimmer, Stroke: crawler.Stroke(leg.Stroke), Split: leg.Split}
+	}
+	return crawlerLegs
+}
+
+func splitsToModelSplits(splits []crawler.Split) []models.Split {
+	if len(splits) == 0 {
+		return nil
+	}
+	modelSplits := make([]models.Split, len(splits))
+	for i, split := range splits {
+		modelSplits[i] = models.Split{Distance: split.Distance, Time: split.Time}
+	}
+	return modelSplits
+}
+
+func modelSplitsToSplits(splits []models.Split) []crawler.Split {
+	if len(splits) == 0 {
+		return nil
+	}
+	crawlerSplits := make([]crawler.Split, len(splits))
+	for i, split := range splits {
+		crawlerSplits[i] = crawler.Split{Distance: split.Distance, Time: split.Time}
+	}
+	return crawlerSplits
+}
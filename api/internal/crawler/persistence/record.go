@@ -0,0 +1,91 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/db/mongo/models"
+	"aquascore/api/internal/record"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// recordTimeout 是重新推算一個項目紀錄的時間上限，需要讀取同一項目歷年所有的成績
+const recordTimeout = time.Second * 30
+
+// updateRecords 以同一性別、年齡組別、水道與項目的所有 race 重新推算紀錄。
+// 紀錄是由項目推算出的資料，失敗只記錄 log，不影響項目本身的寫入
+func (m *mongoPersistence) updateRecords(race *crawler.Race) {
+	if race.Event.Distance == 0 || race.Event.Stroke == "" {
+		return
+	}
+	event := EventToModelRaceEvent(race.Event)
+	event.Round = ""
+	unlock := m.derivedLocks.lock(fmt.Sprintf("record|%s|%s|%s|%+v", race.Gender, race.AgeGroup, race.PoolType, event))
+	defer unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+	defer cancel()
+	races, err := m.raceStore.FindRacesWithResults(ctx,
+		mongo.NewRaceQueryBySwimEvent(race.Gender, race.AgeGroup, race.PoolType, event))
+	if err != nil {
+		log.Printf("⚠️ %s [%s] 讀取紀錄的項目失敗: %v", race.CompetitionName, race.EventName, err)
+		return
+	}
+	if err := m.recordStore.SaveRecords(ctx, BuildRecords(races, time.Now())); err != nil {
+		log.Printf("⚠️ %s [%s] 更新紀錄失敗: %v", race.CompetitionName, race.EventName, err)
+	}
+}
+
+// BuildRecords 由已儲存的項目與成績推算紀錄的歷史，見 record.Build
+func BuildRecords(races []*models.AggrRaceWithResult, updatedAt time.Time) []*models.Record {
+	input := make([]record.Race, len(races))
+	for i, race := range races {
+		input[i] = record.Race{ID: race.ID.Hex(), Race: AggrRaceToRace(race)}
+	}
+	built := record.Build(input)
+	records := make([]*models.Record, len(built))
+	for i, r := range built {
+		records[i] = recordToModelRecord(r, updatedAt)
+	}
+	return records
+}
+
+func recordToModelRecord(r *record.Record, updatedAt time.Time) *models.Record {
+	modelRecord := models.NewRecord()
+	modelRecord.Key = r.Key.String()
+	modelRecord.Type = string(r.Key.Type)
+	modelRecord.CompetitionName = r.Key.CompetitionName
+	modelRecord.Gender = r.Key.Gender
+	modelRecord.AgeGroup = r.Key.AgeGroup
+	modelRecord.PoolType = r.Key.PoolType
+	modelRecord.EventType = r.Key.Event.Label()
+	modelRecord.Distance = r.Key.Event.Distance
+	modelRecord.Stroke = string(r.Key.Event.Stroke)
+	modelRecord.Relay = r.Key.Event.Relay
+	modelRecord.RelayCount = r.Key.Event.RelayCount
+	modelRecord.History = make([]models.RecordEntry, len(r.Entries))
+	for i, entry := range r.Entries {
+		raceID, _ := bson.ObjectIDFromHex(entry.RaceID)
+		modelRecord.History[i] = models.RecordEntry{
+			Record:          entry.Record,
+			Date:            entry.Date,
+			Source:          string(entry.Source),
+			RaceID:          raceID,
+			Year:            entry.Year,
+			CompetitionName: entry.CompetitionName,
+			EventName:       entry.EventName,
+			Athletes:        entry.Athletes,
+			Unit:            entry.Unit,
+		}
+	}
+	if current := r.Current(); current != nil {
+		modelRecord.Record = current.Record
+		modelRecord.Date = current.Date
+	}
+	modelRecord.UpdatedAt = updatedAt
+	return modelRecord
+}
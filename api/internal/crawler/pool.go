@@ -0,0 +1,72 @@
+package crawler
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 水道類型，與 EventType 一起作為分析時區分項目的依據
+const (
+	PoolTypeShortCourse = "25m" // 短水道
+	PoolTypeLongCourse  = "50m" // 長水道
+)
+
+var (
+	shortCourseReg = regexp.MustCompile(`(?i)短水道|短池|25\s*(m|公尺|米)`)
+	longCourseReg  = regexp.MustCompile(`(?i)長水道|長池|50\s*(m|公尺|米)`)
+)
+
+// PoolTypeRule 是設定檔中指定比賽水道的規則，競賽名稱包含 Match 時使用 PoolType
+type PoolTypeRule struct {
+	Match    string `mapstructure:"match"`
+	PoolType string `mapstructure:"pool_type"`
+}
+
+// PoolTypeResolver 依設定的規則、競賽名稱與預設值決定比賽的水道
+type PoolTypeResolver struct {
+	rules       []PoolTypeRule
+	defaultType string
+}
+
+// NewPoolTypeResolver 建立 PoolTypeResolver，defaultType 為空代表無法判斷時保持空白
+func NewPoolTypeResolver(rules []PoolTypeRule, defaultType string) (*PoolTypeResolver, error) {
+	for _, rule := range rules {
+		if rule.Match == "" {
+			return nil, fmt.Errorf("pool type rule for %q has empty match", rule.PoolType)
+		}
+		if !isValidPoolType(rule.PoolType) {
+			return nil, fmt.Errorf("pool type rule %q: invalid pool type %q", rule.Match, rule.PoolType)
+		}
+	}
+	if defaultType != "" && !isValidPoolType(defaultType) {
+		return nil, fmt.Errorf("invalid default pool type %q", defaultType)
+	}
+	return &PoolTypeResolver{rules: rules, defaultType: defaultType}, nil
+}
+
+// Resolve 回傳比賽的水道，優先順序為設定的規則、競賽名稱中的關鍵字、預設值；
+// nil 的 PoolTypeResolver 只依競賽名稱判斷
+func (r *PoolTypeResolver) Resolve(competitionName string) string {
+	if r != nil {
+		for _, rule := range r.rules {
+			if strings.Contains(competitionName, rule.Match) {
+				return rule.PoolType
+			}
+		}
+	}
+	switch {
+	case shortCourseReg.MatchString(competitionName):
+		return PoolTypeShortCourse
+	case longCourseReg.MatchString(competitionName):
+		return PoolTypeLongCourse
+	}
+	if r == nil {
+		return ""
+	}
+	return r.defaultType
+}
+
+func isValidPoolType(poolType string) bool {
+	return poolType == PoolTypeShortCourse || poolType == PoolTypeLongCourse
+}
@@ -0,0 +1,46 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolTypeResolver_Resolve(t *testing.T) {
+	resolver, err := NewPoolTypeResolver([]PoolTypeRule{
+		{Match: "縣長盃", PoolType: PoolTypeShortCourse},
+		{Match: "冬季短水道", PoolType: PoolTypeLongCourse},
+	}, PoolTypeLongCourse)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"114年全國冬季短水道游泳錦標賽", PoolTypeLongCourse}, // 設定的規則優先
+		{"113年全國短水道游泳錦標賽", PoolTypeShortCourse},
+		{"113年25M短池邀請賽", PoolTypeShortCourse},
+		{"113年50公尺長水道分齡賽", PoolTypeLongCourse},
+		{"114年新竹縣長盃游泳賽", PoolTypeShortCourse},
+		{"114年全國南區(1)游泳錦標賽", PoolTypeLongCourse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resolver.Resolve(tt.name))
+		})
+	}
+
+	var nilResolver *PoolTypeResolver
+	assert.Equal(t, PoolTypeShortCourse, nilResolver.Resolve("113年全國短水道游泳錦標賽"))
+	assert.Equal(t, "", nilResolver.Resolve("114年全國南區(1)游泳錦標賽"))
+}
+
+func TestNewPoolTypeResolver_invalid(t *testing.T) {
+	_, err := NewPoolTypeResolver([]PoolTypeRule{{Match: "", PoolType: PoolTypeLongCourse}}, "")
+	assert.Error(t, err)
+	_, err = NewPoolTypeResolver([]PoolTypeRule{{Match: "盃", PoolType: "33m"}}, "")
+	assert.Error(t, err)
+	_, err = NewPoolTypeResolver(nil, "LCM")
+	assert.Error(t, err)
+}
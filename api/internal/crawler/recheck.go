@@ -0,0 +1,154 @@
+package crawler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RaceChange 是重新檢查時發現的一個欄位變更，Athlete 為空代表項目本身的欄位
+type RaceChange struct {
+	Athlete  string
+	Field    string
+	OldValue string
+	NewValue string
+}
+
+// 成績新增或移除時使用的欄位名稱
+const changeFieldResult = "result"
+
+// RaceUpdater 是支援重新檢查模式的 Persistence，用來套用來源網站事後更正的成績
+type RaceUpdater interface {
+	// RaceDate 回傳 URL 已儲存項目的比賽日期，無法得知時回傳零值
+	RaceDate(url string) (time.Time, error)
+	// UpdateRace 比對重新解析的成績與已儲存的資料，一次套用所有差異並記錄變更歷史
+	UpdateRace(url string, race *Race) ([]RaceChange, error)
+}
+
+// DiffRace 比對已儲存的 stored 與重新解析的 fetched，成績以選手姓名對應
+func DiffRace(stored, fetched *Race) []RaceChange {
+	var changes []RaceChange
+	add := func(athlete, field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, RaceChange{
+				Athlete: athlete, Field: field, OldValue: oldValue, NewValue: newValue,
+			})
+		}
+	}
+	add("", "type", stored.Type, fetched.Type)
+	add("", "organizer", stored.Organizer, fetched.Organizer)
+	add("", "gender", stored.Gender, fetched.Gender)
+	add("", "pool_type", stored.PoolType, fetched.PoolType)
+	add("", "age_group", stored.AgeGroup, fetched.AgeGroup)
+	add("", "event_type", stored.EventType, fetched.EventType)
+	add("", "distance", strconv.Itoa(stored.Event.Distance), strconv.Itoa(fetched.Event.Distance))
+	add("", "stroke", string(stored.Event.Stroke), string(fetched.Event.Stroke))
+	add("", "relay", strconv.FormatBool(stored.Event.Relay), strconv.FormatBool(fetched.Event.Relay))
+	add("", "relay_count", strconv.Itoa(stored.Event.RelayCount), strconv.Itoa(fetched.Event.RelayCount))
+	add("", "round", string(stored.Event.Round), string(fetched.Event.Round))
+	add("", "games_record", FormatSwimTime(stored.GamesRecord), FormatSwimTime(fetched.GamesRecord))
+	add("", "national_record", FormatSwimTime(stored.NationalRecord), FormatSwimTime(fetched.NationalRecord))
+	if !stored.Time.Equal(fetched.Time) {
+		add("", "time", formatRaceTime(stored.Time), formatRaceTime(fetched.Time))
+	}
+
+	storedResults := make(map[string]*RaceResult, len(stored.Results))
+	for _, result := range stored.Results {
+		if key := resultKey(result); key != "" {
+			storedResults[key] = result
+		}
+	}
+	seen := make(map[string]bool, len(fetched.Results))
+	for _, result := range fetched.Results {
+		key := resultKey(result)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		old, ok := storedResults[key]
+		if !ok {
+			add(key, changeFieldResult, "", describeResult(result))
+			continue
+		}
+		add(key, "unit", old.Unit, result.Unit)
+		add(key, "record", FormatSwimTime(old.Record), FormatSwimTime(result.Record))
+		add(key, "rank", formatInt(old.Rank), formatInt(result.Rank))
+		add(key, "score", formatInt(old.Score), formatInt(result.Score))
+		add(key, "note", old.Note, result.Note)
+		add(key, "status", string(old.Status), string(result.Status))
+		add(key, "status_reason", old.StatusReason, result.StatusReason)
+		add(key, "team", old.Team, result.Team)
+		add(key, "splits", formatSplits(old), formatSplits(result))
+		add(key, "reaction_time", FormatSwimTime(old.ReactionTime), FormatSwimTime(result.ReactionTime))
+	}
+	for _, result := range stored.Results {
+		if key := resultKey(result); key != "" && !seen[key] {
+			add(key, changeFieldResult, describeResult(result), "")
+			seen[key] = true
+		}
+	}
+	return changes
+}
+
+// FormatSwimTime 將成績格式化為 m:ss.SS (未滿一分鐘為 ss.SS，一小時以上為 h:mm:ss.SS)，0 代表沒有成績
+func FormatSwimTime(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	hundredths := d.Round(10*time.Millisecond) / (10 * time.Millisecond)
+	hours := hundredths / 360000
+	minutes := hundredths % 360000 / 6000
+	seconds := hundredths % 6000 / 100
+	fraction := hundredths % 100
+	switch {
+	case hours > 0:
+		return fmt.Sprintf("%d:%02d:%02d.%02d", hours, minutes, seconds, fraction)
+	case minutes > 0:
+		return fmt.Sprintf("%d:%02d.%02d", minutes, seconds, fraction)
+	default:
+		return fmt.Sprintf("%d.%02d", seconds, fraction)
+	}
+}
+
+func resultKey(result *RaceResult) string {
+	if result == nil {
+		return ""
+	}
+	return strings.Join(result.Name, "、")
+}
+
+func describeResult(result *RaceResult) string {
+	return strings.TrimSpace(
+		fmt.Sprintf("%s %s #%d %s", result.Unit, FormatSwimTime(result.Record), result.Rank, result.Note))
+}
+
+// formatSplits 以 "/" 串接各棒的分段成績，沒有接力分段時改為串接累計分段 ("距離 時間")，
+// 都沒有時回傳空字串
+func formatSplits(result *RaceResult) string {
+	legSplits := make([]string, len(result.Legs))
+	var hasLegSplit bool
+	for i, leg := range result.Legs {
+		legSplits[i] = FormatSwimTime(leg.Split)
+		hasLegSplit = hasLegSplit || leg.Split > 0
+	}
+	if hasLegSplit {
+		return strings.Join(legSplits, "/")
+	}
+	splits := make([]string, len(result.Splits))
+	for i, split := range result.Splits {
+		splits[i] = fmt.Sprintf("%d %s", split.Distance, FormatSwimTime(split.Time))
+	}
+	return strings.Join(splits, "/")
+}
+
+func formatInt(v int32) string {
+	return strconv.Itoa(int(v))
+}
+
+func formatRaceTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
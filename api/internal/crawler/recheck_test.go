@@ -0,0 +1,122 @@
+package crawler
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatSwimTime(t *testing.T) {
+	assert.Equal(t, "", FormatSwimTime(0))
+	assert.Equal(t, "25.03", FormatSwimTime(25*time.Second+30*time.Millisecond))
+	assert.Equal(t, "1:05.30", FormatSwimTime(65*time.Second+300*time.Millisecond))
+	assert.Equal(t, "16:02.00", FormatSwimTime(16*time.Minute+2*time.Second))
+	assert.Equal(t, "1:02:03.45", FormatSwimTime(time.Hour+2*time.Minute+3*time.Second+450*time.Millisecond))
+}
+
+func TestDiffRace(t *testing.T) {
+	stored := &Race{
+		Type:        "決賽",
+		GamesRecord: 30 * time.Second,
+		Results: []*RaceResult{
+			{Unit: "A校", Name: []string{"王小明"}, Record: 31 * time.Second, Rank: 1, Score: 9},
+			{Unit: "B校", Name: []string{"李小華"}, Record: 32 * time.Second, Rank: 2, Score: 7},
+			{Unit: "C校", Name: []string{"陳大文"}, Record: 33 * time.Second, Rank: 3, Score: 6},
+		},
+	}
+	fetched := &Race{
+		Type:        "決賽",
+		GamesRecord: 30 * time.Second,
+		Results: []*RaceResult{
+			{Unit: "B校", Name: []string{"李小華"}, Record: 32 * time.Second, Rank: 1, Score: 9},
+			{Unit: "A校", Name: []string{"王小明"}, Record: 31 * time.Second, Rank: 0, Note: "DQ"},
+			{Unit: "D校", Name: []string{"林小美"}, Record: 34 * time.Second, Rank: 2, Score: 7},
+		},
+	}
+	changes := DiffRace(stored, fetched)
+	assert.ElementsMatch(t, []RaceChange{
+		{Athlete: "李小華", Field: "rank", OldValue: "2", NewValue: "1"},
+		{Athlete: "李小華", Field: "score", OldValue: "7", NewValue: "9"},
+		{Athlete: "王小明", Field: "rank", OldValue: "1", NewValue: "0"},
+		{Athlete: "王小明", Field: "score", OldValue: "9", NewValue: "0"},
+		{Athlete: "王小明", Field: "note", OldValue: "", NewValue: "DQ"},
+		{Athlete: "林小美", Field: "result", OldValue: "", NewValue: "D校 34.00 #2"},
+		{Athlete: "陳大文", Field: "result", OldValue: "C校 33.00 #3", NewValue: ""},
+	}, changes)
+
+	assert.Empty(t, DiffRace(stored, stored))
+}
+
+func TestDiffRace_splits(t *testing.T) {
+	stored := &Race{Results: []*RaceResult{{Name: []string{"王小明"}, Record: 65 * time.Second}}}
+	fetched := &Race{Results: []*RaceResult{{
+		Name:         []string{"王小明"},
+		Record:       65 * time.Second,
+		Splits:       []Split{{Distance: 50, Time: 31 * time.Second}, {Distance: 100, Time: 65 * time.Second}},
+		ReactionTime: 700 * time.Millisecond,
+	}}}
+	assert.ElementsMatch(t, []RaceChange{
+		{Athlete: "王小明", Field: "splits", OldValue: "", NewValue: "50 31.00/100 1:05.00"},
+		{Athlete: "王小明", Field: "reaction_time", OldValue: "", NewValue: "0.70"},
+	}, DiffRace(stored, fetched))
+}
+
+type mockUpdater struct {
+	mockPersistence
+	raceDates map[string]time.Time
+
+	mu      sync.Mutex
+	updated []string
+}
+
+func (m *mockUpdater) RaceDate(url string) (time.Time, error) {
+	return m.raceDates[url], nil
+}
+
+func (m *mockUpdater) UpdateRace(url string, _ *Race) ([]RaceChange, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.updated = append(m.updated, url)
+	return nil, nil
+}
+
+func TestCrawler_recheck(t *testing.T) {
+	source := &mockSource{
+		competitions: []CompetitionInfo{{ID: "1", Name: "A"}},
+		races: map[string][]RaceInfo{
+			"1": {
+				{CompetitionName: "A", RaceName: "a1", URL: "u1"},
+				{CompetitionName: "A", RaceName: "a2", URL: "u2"},
+				{CompetitionName: "A", RaceName: "a3", URL: "u3"},
+				{CompetitionName: "A", RaceName: "a4", URL: "u4"},
+			},
+		},
+	}
+	var persisted []string
+	updater := &mockUpdater{
+		mockPersistence: mockPersistence{
+			persistRace: func(_ string, race *Race) error {
+				persisted = append(persisted, race.EventName)
+				return nil
+			},
+			isCrawled: func(url string) (bool, error) { return url != "u2", nil },
+		},
+		// 以比賽日期判斷：u1 是近期的比賽，u3 是很久以前的比賽 (即使剛重新爬取過)，u4 無法得知日期
+		raceDates: map[string]time.Time{
+			"u1": time.Now().Add(-24 * time.Hour),
+			"u3": time.Now().Add(-300 * 24 * time.Hour),
+		},
+	}
+
+	_, err := New(source, WithPersistence(&updater.mockPersistence), WithRecheck(time.Hour))
+	require.Error(t, err, "persistence without RaceUpdater")
+
+	c, err := New(source, WithPersistence(updater), WithRecheck(7*24*time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, c.Crawl(t.Context()))
+	assert.Equal(t, []string{"u1"}, updater.updated)
+	assert.Equal(t, []string{"a2"}, persisted)
+}
@@ -0,0 +1,31 @@
+package crawler
+
+import "time"
+
+// RelayLeg 是接力隊伍中的一棒，依棒次排列在 RaceResult.Legs
+type RelayLeg struct {
+	Swimmer string        // 選手姓名
+	Stroke  Stroke        // 這一棒的泳式
+	Split   time.Duration // 這一棒的分段成績，來源沒有提供時為 0
+}
+
+// medleyRelayStrokes 是混合式接力各棒的泳式
+var medleyRelayStrokes = []Stroke{StrokeBackstroke, StrokeBreaststroke, StrokeButterfly, StrokeFreestyle}
+
+// RelayLegs 將接力成績的選手姓名依序轉成各棒，非接力項目回傳 nil
+func RelayLegs(event Event, swimmers []string) []RelayLeg {
+	if !event.Relay || len(swimmers) == 0 {
+		return nil
+	}
+	legs := make([]RelayLeg, len(swimmers))
+	for i, swimmer := range swimmers {
+		legs[i] = RelayLeg{Swimmer: swimmer, Stroke: event.Stroke}
+		if event.Stroke == StrokeMedley {
+			legs[i].Stroke = ""
+			if i < len(medleyRelayStrokes) {
+				legs[i].Stroke = medleyRelayStrokes[i]
+			}
+		}
+	}
+	return legs
+}
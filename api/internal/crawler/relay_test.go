@@ -0,0 +1,31 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelayLegs(t *testing.T) {
+	swimmers := []string{"王小明", "李小華", "陳大文", "林小美"}
+
+	assert.Nil(t, RelayLegs(Event{Distance: 100, Stroke: StrokeFreestyle}, []string{"王小明"}))
+
+	assert.Equal(t, []RelayLeg{
+		{Swimmer: "王小明", Stroke: StrokeBackstroke},
+		{Swimmer: "李小華", Stroke: StrokeBreaststroke},
+		{Swimmer: "陳大文", Stroke: StrokeButterfly},
+		{Swimmer: "林小美", Stroke: StrokeFreestyle},
+	}, RelayLegs(ParseEvent("4×50公尺混合式接力"), swimmers))
+
+	legs := RelayLegs(ParseEvent("4×100公尺自由式接力"), swimmers)
+	assert.Len(t, legs, 4)
+	for i, leg := range legs {
+		assert.Equal(t, swimmers[i], leg.Swimmer)
+		assert.Equal(t, StrokeFreestyle, leg.Stroke)
+	}
+
+	// 混合式接力超過四棒時 (資料錯誤) 多出的棒次不指定泳式
+	legs = RelayLegs(ParseEvent("200公尺混合式接力"), append(swimmers, "張三"))
+	assert.Equal(t, Stroke(""), legs[4].Stroke)
+}
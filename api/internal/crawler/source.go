@@ -0,0 +1,51 @@
+package crawler
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// SourceFactory 依照 Option 建立一個 Source
+type SourceFactory func(opts ...Option) (Source, error)
+
+var (
+	sourcesMu sync.RWMutex
+	sources   = make(map[string]SourceFactory)
+)
+
+// RegisterSource 註冊一個成績來源，name 重複註冊時會 panic
+func RegisterSource(name string, factory SourceFactory) {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	if factory == nil {
+		panic("crawler: RegisterSource factory is nil")
+	}
+	if _, dup := sources[name]; dup {
+		panic("crawler: RegisterSource called twice for source " + name)
+	}
+	sources[name] = factory
+}
+
+// NewSource 建立已註冊的成績來源
+func NewSource(name string, opts ...Option) (Source, error) {
+	sourcesMu.RLock()
+	factory, ok := sources[name]
+	sourcesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown source %q (available: %v)", name, SourceNames())
+	}
+	return factory(opts...)
+}
+
+// SourceNames 回傳所有已註冊的成績來源名稱 (已排序)
+func SourceNames() []string {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,122 @@
+package crawler
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+)
+
+// Split 是成績中的一個分段，Time 為出發到 Distance 公尺的累計時間
+type Split struct {
+	Distance int
+	Time     time.Duration
+}
+
+// ResultDetail 是詳細成績頁上的分段與反應時間
+type ResultDetail struct {
+	Splits       []Split
+	ReactionTime time.Duration
+}
+
+// ResultDetailSource 是成績可以連結到詳細成績頁 (分段、反應時間) 的 Source
+type ResultDetailSource interface {
+	FetchResultDetail(ctx context.Context, url string) (*ResultDetail, error)
+}
+
+// NormalizeSplits 依距離排序並將分段轉為累計時間，距離或時間無效的分段會被略過。
+// 來源提供的若是每一段的時間，逐段累加後會比最後一段更接近 record (或出現後一段比前一段短)
+func NormalizeSplits(splits []Split, record time.Duration) []Split {
+	normalized := make([]Split, 0, len(splits))
+	for _, split := range splits {
+		if split.Distance > 0 && split.Time > 0 {
+			normalized = append(normalized, split)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+	slices.SortStableFunc(normalized, func(a, b Split) int { return a.Distance - b.Distance })
+	if isLapTimes(normalized, record) {
+		var total time.Duration
+		for i := range normalized {
+			total += normalized[i].Time
+			normalized[i].Time = total
+		}
+	}
+	return normalized
+}
+
+func isLapTimes(splits []Split, record time.Duration) bool {
+	if len(splits) < 2 {
+		return false
+	}
+	var sum time.Duration
+	decreasing := false
+	for i, split := range splits {
+		sum += split.Time
+		if i > 0 && split.Time <= splits[i-1].Time {
+			decreasing = true
+		}
+	}
+	if decreasing {
+		return true
+	}
+	if record <= 0 {
+		return false
+	}
+	last := splits[len(splits)-1].Time
+	return absDuration(sum-record) < absDuration(last-record)
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// ApplyLegSplits 以累計分段換算接力各棒的成績，只填入沒有分段成績的棒次
+func ApplyLegSplits(event Event, result *RaceResult) {
+	if !event.Relay || event.Distance <= 0 || len(result.Splits) == 0 {
+		return
+	}
+	cumulative := map[int]time.Duration{0: 0}
+	for _, split := range result.Splits {
+		cumulative[split.Distance] = split.Time
+	}
+	for i := range result.Legs {
+		if result.Legs[i].Split > 0 {
+			continue
+		}
+		start, okStart := cumulative[i*event.Distance]
+		end, okEnd := cumulative[(i+1)*event.Distance]
+		if okStart && okEnd {
+			result.Legs[i].Split = end - start
+		}
+	}
+}
+
+// FetchResultDetails 為連結到詳細成績頁且還沒有分段的成績補上分段與反應時間。
+// 詳細成績頁是額外的資訊，抓取失敗只記錄 log，不影響項目本身
+func FetchResultDetails(ctx context.Context, source Source, race *Race) {
+	detailSource, ok := source.(ResultDetailSource)
+	if !ok {
+		return
+	}
+	for _, result := range race.Results {
+		if result.DetailURL == "" || len(result.Splits) > 0 {
+			continue
+		}
+		detail, err := detailSource.FetchResultDetail(ctx, result.DetailURL)
+		if err != nil {
+			log.Printf("⚠️ 詳細成績抓取失敗 %s: %v", result.DetailURL, err)
+			continue
+		}
+		result.Splits = NormalizeSplits(detail.Splits, result.Record)
+		if result.ReactionTime == 0 {
+			result.ReactionTime = detail.ReactionTime
+		}
+		ApplyLegSplits(race.Event, result)
+	}
+}
@@ -0,0 +1,55 @@
+package crawler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeSplits(t *testing.T) {
+	cumulative := []Split{
+		{Distance: 100, Time: 70 * time.Second},
+		{Distance: 50, Time: 33 * time.Second},
+		{Distance: 0, Time: time.Second},
+		{Distance: 150, Time: 0},
+	}
+	assert.Equal(t, []Split{
+		{Distance: 50, Time: 33 * time.Second},
+		{Distance: 100, Time: 70 * time.Second},
+	}, NormalizeSplits(cumulative, 0))
+
+	// 每一段的時間，後一段比前一段短
+	laps := []Split{{Distance: 50, Time: 33 * time.Second}, {Distance: 100, Time: 32 * time.Second}}
+	assert.Equal(t, []Split{
+		{Distance: 50, Time: 33 * time.Second},
+		{Distance: 100, Time: 65 * time.Second},
+	}, NormalizeSplits(laps, 0))
+
+	// 每一段的時間遞增，以總成績判斷
+	laps = []Split{{Distance: 50, Time: 30 * time.Second}, {Distance: 100, Time: 34 * time.Second}}
+	assert.Equal(t, 64*time.Second, NormalizeSplits(laps, 64*time.Second)[1].Time)
+	assert.Equal(t, 34*time.Second, NormalizeSplits(laps, 34*time.Second)[1].Time)
+
+	assert.Nil(t, NormalizeSplits(nil, 0))
+}
+
+func TestApplyLegSplits(t *testing.T) {
+	event := Event{Distance: 50, Stroke: StrokeFreestyle, Relay: true, RelayCount: 4}
+	result := &RaceResult{
+		Legs: RelayLegs(event, []string{"甲", "乙", "丙", "丁"}),
+		Splits: []Split{
+			{Distance: 50, Time: 30 * time.Second},
+			{Distance: 100, Time: 61 * time.Second},
+			{Distance: 200, Time: 124 * time.Second},
+		},
+	}
+	result.Legs[3].Split = 31 * time.Second
+	ApplyLegSplits(event, result)
+	assert.Equal(t, 30*time.Second, result.Legs[0].Split)
+	assert.Equal(t, 31*time.Second, result.Legs[1].Split)
+	// 缺少 150 公尺分段
+	assert.Zero(t, result.Legs[2].Split)
+	// 已有分段成績的棒次不覆寫
+	assert.Equal(t, 31*time.Second, result.Legs[3].Split)
+}
@@ -0,0 +1,74 @@
+package crawler
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ResultStatus 是正規化後的成績狀態
+type ResultStatus string
+
+const (
+	ResultStatusOK         ResultStatus = "ok"         // 正常完賽
+	ResultStatusDQ         ResultStatus = "dq"         // 犯規 (取消資格)
+	ResultStatusDNS        ResultStatus = "dns"        // 未出賽 (棄權)
+	ResultStatusDNF        ResultStatus = "dnf"        // 未完賽 (中途棄權、逾時)
+	ResultStatusScratch    ResultStatus = "scratch"    // 賽前退賽
+	ResultStatusExhibition ResultStatus = "exhibition" // 表演賽，不計名次
+)
+
+// resultStatusPatterns 依比對順序排列，較長的關鍵字必須在前面
+// (例如 "中途棄權" 包含 "棄權"、"取消資格" 包含 "取消")
+var resultStatusPatterns = []struct {
+	reg    *regexp.Regexp
+	status ResultStatus
+}{
+	{regexp.MustCompile(`中途棄權|未完成|逾時|(?i:\bDNF\b)`), ResultStatusDNF},
+	{regexp.MustCompile(`取消資格|犯規|失格|違規|(?i:\bDSQ\b|\bDQ\b)`), ResultStatusDQ},
+	{regexp.MustCompile(`棄權|缺席|未到|未出賽|(?i:\bDNS\b|\bNS\b)`), ResultStatusDNS},
+	{regexp.MustCompile(`取消|退賽|(?i:\bSCR\b)`), ResultStatusScratch},
+	{regexp.MustCompile(`表演賽|表演|示範賽|示範|不計名次|(?i:\bEXH\b)`), ResultStatusExhibition},
+}
+
+// ParseResultStatus 由成績的備註 (或成績欄位中的非時間文字) 解析成績狀態，
+// reason 是去掉狀態關鍵字後的說明 (例如犯規原因)。
+// 沒有狀態關鍵字時，有成績為 ResultStatusOK，沒有成績視為 ResultStatusDNS
+func ParseResultStatus(text string, hasTime bool) (status ResultStatus, reason string) {
+	if status, reason, ok := matchResultStatus(text); ok {
+		return status, reason
+	}
+	if hasTime {
+		return ResultStatusOK, ""
+	}
+	return ResultStatusDNS, ""
+}
+
+func matchResultStatus(text string) (ResultStatus, string, bool) {
+	for _, pattern := range resultStatusPatterns {
+		if pattern.reg.MatchString(text) {
+			reason := pattern.reg.ReplaceAllString(text, " ")
+			return pattern.status, cleanStatusReason(reason), true
+		}
+	}
+	return "", "", false
+}
+
+// IsResultStatusText 回傳文字是否包含成績狀態關鍵字，例如成績欄位寫著 "犯規" 而不是時間
+func IsResultStatusText(text string) bool {
+	_, _, ok := matchResultStatus(text)
+	return ok
+}
+
+// cleanStatusReason 去掉說明前後的空白與標點，例如 "(蛙式踢腿)" 變成 "蛙式踢腿"
+func cleanStatusReason(reason string) string {
+	reason = strings.TrimFunc(reason, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+	return strings.Join(strings.Fields(reason), " ")
+}
+
+// HasValidTime 回傳成績是否可以用於表現分析，犯規、未出賽、未完賽與退賽的成績即使有時間也不列入
+func (s ResultStatus) HasValidTime() bool {
+	return s == "" || s == ResultStatusOK || s == ResultStatusExhibition
+}
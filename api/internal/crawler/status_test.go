@@ -0,0 +1,47 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseResultStatus(t *testing.T) {
+	tests := []struct {
+		text    string
+		hasTime bool
+		status  ResultStatus
+		reason  string
+	}{
+		{"", true, ResultStatusOK, ""},
+		{"", false, ResultStatusDNS, ""},
+		{"破大會紀錄", true, ResultStatusOK, ""},
+		{"犯規(蛙式踢腿)", false, ResultStatusDQ, "蛙式踢腿"},
+		{"DQ 轉身未觸壁", true, ResultStatusDQ, "轉身未觸壁"},
+		{"取消資格：提前出發", false, ResultStatusDQ, "提前出發"},
+		{"棄權", false, ResultStatusDNS, ""},
+		{"dns", false, ResultStatusDNS, ""},
+		{"中途棄權", false, ResultStatusDNF, ""},
+		{"逾時", false, ResultStatusDNF, ""},
+		{"退賽", false, ResultStatusScratch, ""},
+		{"表演賽", true, ResultStatusExhibition, ""},
+		{"不計名次", true, ResultStatusExhibition, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			status, reason := ParseResultStatus(tt.text, tt.hasTime)
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestResultStatus_HasValidTime(t *testing.T) {
+	assert.True(t, ResultStatus("").HasValidTime())
+	assert.True(t, ResultStatusOK.HasValidTime())
+	assert.True(t, ResultStatusExhibition.HasValidTime())
+	assert.False(t, ResultStatusDQ.HasValidTime())
+	assert.False(t, ResultStatusDNS.HasValidTime())
+	assert.False(t, ResultStatusDNF.HasValidTime())
+	assert.False(t, ResultStatusScratch.HasValidTime())
+}
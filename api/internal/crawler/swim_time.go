@@ -0,0 +1,59 @@
+package crawler
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// fullWidthOffset 是全形 ASCII (U+FF01~U+FF5E) 與半形字元的差距
+	fullWidthOffset   = 0xFEE0
+	maxSwimTimeDigits = 3 // 小數最多到千分之一秒
+	secondsPerMinute  = 60
+)
+
+// swimTimeReg 比對 "ss.SS"、"m:ss.SS"、"h:mm:ss.SS"，小數可為 1~3 位
+var swimTimeReg = regexp.MustCompile(`^(?:(?:(\d{1,2}):)?(\d{1,3}):)?(\d{1,4})(?:\.(\d{1,3}))?$`)
+
+// ParseSwimTime 解析成績時間，支援 "28.35"、"1:05.30"、"01:05.30"、"1:02:03.45" 與千分之一秒 ("28.354")，
+// 全形數字與標點 (２８．３５、１：０５．３０) 會先轉為半形。沒有冒號時必須有小數，避免把名次或積分當成時間
+func ParseSwimTime(s string) (time.Duration, error) {
+	text := toHalfWidth(s)
+	match := swimTimeReg.FindStringSubmatch(text)
+	if match == nil {
+		return 0, fmt.Errorf("時間格式錯誤: %q", s)
+	}
+	hours, minutes, seconds, fraction := match[1], match[2], match[3], match[4]
+	if minutes == "" && fraction == "" {
+		return 0, fmt.Errorf("時間格式錯誤，缺少小數: %q", s)
+	}
+	h, _ := strconv.Atoi(hours)
+	m, _ := strconv.Atoi(minutes)
+	sec, _ := strconv.Atoi(seconds)
+	if (minutes != "" && sec >= secondsPerMinute) || (hours != "" && m >= secondsPerMinute) {
+		return 0, fmt.Errorf("時間格式錯誤，分或秒超過 59: %q", s)
+	}
+	fraction += strings.Repeat("0", maxSwimTimeDigits-len(fraction))
+	ms, _ := strconv.Atoi(fraction)
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(sec)*time.Second + time.Duration(ms)*time.Millisecond, nil
+}
+
+// toHalfWidth 將全形字元轉為半形並去掉空白
+func toHalfWidth(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r):
+			continue
+		case r >= '！' && r <= '～':
+			r -= fullWidthOffset
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
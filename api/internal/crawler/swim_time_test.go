@@ -0,0 +1,75 @@
+package crawler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSwimTime(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"05:34.22", 5*time.Minute + 34*time.Second + 220*time.Millisecond, false},
+		{"00:55.00", 55 * time.Second, false},
+		{"1:06.50", time.Minute + 6*time.Second + 500*time.Millisecond, false},
+		{"28.35", 28*time.Second + 350*time.Millisecond, false},
+		{" 0.68 ", 680 * time.Millisecond, false},
+		{"44.3", 44*time.Second + 300*time.Millisecond, false},
+		{"28.354", 28*time.Second + 354*time.Millisecond, false},
+		{"1:02:03.45", time.Hour + 2*time.Minute + 3*time.Second + 450*time.Millisecond, false},
+		{"2:05:00", 2*time.Hour + 5*time.Minute, false},
+		{"16:02", 16*time.Minute + 2*time.Second, false},
+		{"２８．３５", 28*time.Second + 350*time.Millisecond, false},
+		{"０１：０５．３０", time.Minute + 5*time.Second + 300*time.Millisecond, false},
+		{"", 0, true},
+		{"DQ", 0, true},
+		{"逾時", 0, true},
+		{"invalid", 0, true},
+		{"28", 0, true},
+		{"1:75.00", 0, true},
+		{"1:75:00.00", 0, true},
+		{"1:05.3456", 0, true},
+		{"-28.35", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSwimTime(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func FuzzParseSwimTime(f *testing.F) {
+	for _, seed := range []string{"28.35", "1:05.30", "01:05.30", "1:02:03.45", "28.354", "２８．３５", "逾時", ":", "1::2.3"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		d, err := ParseSwimTime(s)
+		if err != nil {
+			return
+		}
+		if d < 0 {
+			t.Fatalf("ParseSwimTime(%q) = %v, want non-negative", s, d)
+		}
+		// 格式化後再解析應得到四捨五入到百分之一秒的同一個時間
+		if formatted := FormatSwimTime(d); formatted != "" {
+			again, err := ParseSwimTime(formatted)
+			if err != nil {
+				t.Fatalf("ParseSwimTime(FormatSwimTime(%v) = %q): %v", d, formatted, err)
+			}
+			if want := d.Round(10 * time.Millisecond); again != want {
+				t.Fatalf("ParseSwimTime(%q) = %v, want %v", formatted, again, want)
+			}
+		}
+	})
+}
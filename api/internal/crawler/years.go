@@ -0,0 +1,135 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ADYear 將民國年轉為西元年，例如 "114" 回傳 2025，無法解析時回傳 0
+func ADYear(rocYear string) int {
+	year, err := strconv.Atoi(strings.TrimSpace(rocYear))
+	if err != nil || year <= 0 {
+		return 0
+	}
+	return year + rocYearOffset
+}
+
+// YearDiscoverer 是可以列出所有有資料年度的 Source
+type YearDiscoverer interface {
+	DiscoverYears(ctx context.Context) ([]string, error)
+}
+
+// ParseYears 解析年度清單，例如 "105-114" 或 "105,108,110-112"
+func ParseYears(s string) ([]string, error) {
+	var years []string
+	seen := make(map[int]bool)
+	for part := range strings.SplitSeq(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		from, to, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil {
+			return nil, fmt.Errorf("invalid year %q: %w", part, err)
+		}
+		end := start
+		if isRange {
+			end, err = strconv.Atoi(strings.TrimSpace(to))
+			if err != nil {
+				return nil, fmt.Errorf("invalid year %q: %w", part, err)
+			}
+		}
+		if start <= 0 || end < start {
+			return nil, fmt.Errorf("invalid year range %q", part)
+		}
+		for year := start; year <= end; year++ {
+			if !seen[year] {
+				seen[year] = true
+				years = append(years, strconv.Itoa(year))
+			}
+		}
+	}
+	if len(years) == 0 {
+		return nil, errors.New("no year specified")
+	}
+	return years, nil
+}
+
+// CrawlYears 同時最多以 concurrency 個年度爬取同一個來源，
+// 所有年度共用 opts 中的 Persistence 以及同一組主機限流
+func CrawlYears(ctx context.Context, sourceName string, years []string, concurrency int, opts ...Option) error {
+	o := newOptions(opts...)
+	opts = append(opts, WithHostRateLimiter(o.rateLimiter()))
+	concurrency = max(concurrency, 1)
+
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+	for _, year := range years {
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			err := crawlYear(ctx, sourceName, year, opts...)
+			if err != nil {
+				log.Printf("❌ %s 年度爬取失敗: %v", year, err)
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("year %s: %w", year, err))
+				mu.Unlock()
+				return
+			}
+			log.Printf("✅ %s 年度爬取完成", year)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func crawlYear(ctx context.Context, sourceName, year string, opts ...Option) error {
+	opts = append(opts, WithYear(year))
+	source, err := NewSource(sourceName, opts...)
+	if err != nil {
+		return err
+	}
+	c, err := New(source, opts...)
+	if err != nil {
+		return err
+	}
+	return c.Crawl(ctx)
+}
+
+// ResumeJob 依 opts 中 JobStore 記錄的來源與年度重建 Crawler，並繼續該工作
+func ResumeJob(ctx context.Context, jobID string, opts ...Option) error {
+	o := newOptions(opts...)
+	if o.jobStore == nil {
+		return errors.New("job store is nil")
+	}
+	job, err := o.jobStore.GetJob(jobID)
+	if err != nil {
+		return fmt.Errorf("get job %s fail: %w", jobID, err)
+	}
+	opts = append(opts, WithYear(job.Year))
+	source, err := NewSource(job.Source, opts...)
+	if err != nil {
+		return err
+	}
+	c, err := New(source, opts...)
+	if err != nil {
+		return err
+	}
+	return c.Resume(ctx, jobID)
+}
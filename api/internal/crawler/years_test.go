@@ -0,0 +1,100 @@
+package crawler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseYears(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+		wantErr  bool
+	}{
+		{"114", []string{"114"}, false},
+		{"105-108", []string{"105", "106", "107", "108"}, false},
+		{"105, 107-108,105", []string{"105", "107", "108"}, false},
+		{"", nil, true},
+		{"108-105", nil, true},
+		{"abc", nil, true},
+		{"105-x", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseYears(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestCtsaSource_DiscoverYears(t *testing.T) {
+	data, err := os.ReadFile("test_file/ctsa/get_race_ids.html")
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/CTSA_105/public/race/game_data.aspx", "/CTSA_107/public/race/game_data.aspx":
+			_, _ = w.Write(data)
+		case "/CTSA_106/public/race/game_data.aspx":
+			_, _ = w.Write([]byte("<html><body>維護中</body></html>"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source, err := newCtsaSource(WithRetryPolicy(testRetryPolicy()))
+	require.NoError(t, err)
+	source.yearURLFormat = server.URL + "/CTSA_%s/public/race/game_data.aspx"
+	years, err := source.DiscoverYears(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"105", "107"}, years)
+}
+
+func TestADYear(t *testing.T) {
+	assert.Equal(t, 2025, ADYear("114"))
+	assert.Equal(t, 2016, ADYear(" 105 "))
+	assert.Equal(t, 0, ADYear(""))
+	assert.Equal(t, 0, ADYear("abc"))
+}
+
+func TestCrawlYears(t *testing.T) {
+	RegisterSource("mock-years", func(opts ...Option) (Source, error) {
+		year := newOptions(opts...).year
+		name := year + "年測試賽"
+		return &mockSource{
+			competitions: []CompetitionInfo{{ID: year, Name: name}},
+			races: map[string][]RaceInfo{
+				year: {{CompetitionName: name, RaceName: "r", URL: year + "/r"}},
+			},
+		}, nil
+	})
+	var mu sync.Mutex
+	var persisted []string
+	mockP := &mockPersistence{
+		persistRace: func(_ string, race *Race) error {
+			mu.Lock()
+			defer mu.Unlock()
+			persisted = append(persisted, race.CompetitionName)
+			return nil
+		},
+		isCrawled: func(string) (bool, error) { return false, nil },
+	}
+
+	err := CrawlYears(t.Context(), "mock-years", []string{"105", "106", "107"}, 2, WithPersistence(mockP))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"105年測試賽", "106年測試賽", "107年測試賽"}, persisted)
+
+	err = CrawlYears(t.Context(), "unknown", []string{"105"}, 2, WithPersistence(mockP))
+	assert.ErrorContains(t, err, "year 105")
+}
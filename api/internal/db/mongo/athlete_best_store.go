@@ -0,0 +1,100 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type AthleteBestStore interface {
+	GetAthleteSwims(ctx context.Context, athleteIDs []bson.ObjectID) ([]*models.AggrAthleteSwim, error)
+	GetAthleteSwimHistory(ctx context.Context, athlete AthleteFilter, limit int) ([]*models.AggrAthleteSwim, error)
+	GetSwimAthleteIDs(ctx context.Context) ([]bson.ObjectID, error)
+	ReplaceAthleteBests(ctx context.Context, athleteIDs []bson.ObjectID, bests []*models.AthleteBest) error
+	FindAthleteBests(ctx context.Context, athlete AthleteFilter) ([]*models.AthleteBest, error)
+}
+
+func newAthleteBestStore() AthleteBestStore {
+	return &athleteBestStore{}
+}
+
+type athleteBestStore struct{}
+
+// GetAthleteSwims 回傳連結到任一位選手的個人項目成績
+func (*athleteBestStore) GetAthleteSwims(
+	ctx context.Context, athleteIDs []bson.ObjectID,
+) ([]*models.AggrAthleteSwim, error) {
+	swims, err := mgo.PipeFind(ctx, models.NewAggrAthleteSwim(), bson.M{"athlete_ids": bson.M{"$in": athleteIDs}})
+	if err != nil {
+		return nil, fmt.Errorf("get athlete swims error: %w", err)
+	}
+	return swims, nil
+}
+
+// GetAthleteSwimHistory 回傳選手最近 limit 筆有時間的個人項目成績，依比賽時間由新到舊排序
+func (*athleteBestStore) GetAthleteSwimHistory(
+	ctx context.Context, athlete AthleteFilter, limit int,
+) ([]*models.AggrAthleteSwim, error) {
+	query := athlete.resultQuery("")
+	query["record"] = bson.M{"$gt": 0}
+	swims, err := mgo.PipeFind(ctx, models.NewAggrAthleteSwimHistory(limit), query)
+	if err != nil {
+		return nil, fmt.Errorf("get athlete swim history error: %w", err)
+	}
+	return swims, nil
+}
+
+// GetSwimAthleteIDs 回傳成績上連結過的所有選手 ID
+func (*athleteBestStore) GetSwimAthleteIDs(ctx context.Context) ([]bson.ObjectID, error) {
+	ids, err := mgo.Distinct[bson.ObjectID](ctx, models.NewRaceResult().C(), "athlete_ids", bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("get athlete ids error: %w", err)
+	}
+	return ids, nil
+}
+
+// ReplaceAthleteBests 在同一個交易中以 bests 取代選手原本所有的個人最佳成績
+func (*athleteBestStore) ReplaceAthleteBests(
+	ctx context.Context, athleteIDs []bson.ObjectID, bests []*models.AthleteBest,
+) error {
+	return RunInTransaction(ctx, func(ctx context.Context) error {
+		_, err := mgo.DeleteMany(ctx, models.NewAthleteBest(), bson.M{"athlete_id": bson.M{"$in": athleteIDs}})
+		if err != nil {
+			return fmt.Errorf("delete athlete bests error: %w", err)
+		}
+		if len(bests) == 0 {
+			return nil
+		}
+		bulk, err := mgo.NewBulkOperation(models.NewAthleteBest().C())
+		if err != nil {
+			return fmt.Errorf("create bulk operation error: %w", err)
+		}
+		for _, best := range bests {
+			bulk = bulk.InsertOne(best)
+		}
+		if _, err := bulk.Execute(ctx); err != nil {
+			return fmt.Errorf("insert athlete bests error: %w", err)
+		}
+		return nil
+	})
+}
+
+// FindAthleteBests 依水道、泳式與距離排序回傳選手的個人最佳成績
+func (*athleteBestStore) FindAthleteBests(ctx context.Context, athlete AthleteFilter) ([]*models.AthleteBest, error) {
+	filter := bson.M{"name": athlete.Name}
+	if !athlete.ID.IsZero() {
+		filter = bson.M{"athlete_id": athlete.ID}
+	}
+	bests, err := mgo.Find(ctx, models.NewAthleteBest(), filter, options.Find().SetSort(bson.D{
+		{Key: "pool_type", Value: 1}, {Key: "stroke", Value: 1}, {Key: "distance", Value: 1},
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("find athlete bests error: %w", err)
+	}
+	return bests, nil
+}
@@ -0,0 +1,322 @@
+package mongo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"aquascore/api/internal/athlete"
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type AthleteStore interface {
+	FindAthlete(ctx context.Context, id bson.ObjectID) (*models.Athlete, error)
+	FindAthletesByName(ctx context.Context, name string) ([]*models.Athlete, error)
+	SaveAthlete(ctx context.Context, athlete *models.Athlete) error
+	LinkResultAthletes(ctx context.Context, resultID bson.ObjectID, athleteIDs []bson.ObjectID) error
+	MergeAthletes(ctx context.Context, target *models.Athlete, sources []*models.Athlete) (int64, error)
+	SplitAthlete(ctx context.Context, athlete, newAthlete *models.Athlete, units []string) (int64, error)
+	SearchAthletes(ctx context.Context, search AthleteSearch) ([]*models.AggrAthleteSearch, error)
+	UpdateSearchKeys(ctx context.Context) (int64, error)
+}
+
+func newAthleteStore() AthleteStore {
+	return &athleteStore{}
+}
+
+type athleteStore struct{}
+
+// FindAthlete 以 ID 找出選手，找不到時回傳 nil
+func (*athleteStore) FindAthlete(ctx context.Context, id bson.ObjectID) (*models.Athlete, error) {
+	athlete := models.NewAthlete()
+	err := mgo.FindOne(ctx, athlete, bson.M{"_id": id})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find athlete error: %w", err)
+	}
+	return athlete, nil
+}
+
+// FindAthletesByName 回傳姓名寫法包含 name 且尚未被合併的選手，依建立時間排序
+func (*athleteStore) FindAthletesByName(ctx context.Context, name string) ([]*models.Athlete, error) {
+	athletes, err := mgo.Find(ctx, models.NewAthlete(),
+		bson.M{"names": name, "merged_into": bson.M{"$exists": false}},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("find athletes error: %w", err)
+	}
+	return athletes, nil
+}
+
+// SaveAthlete 以 $addToSet 將姓名寫法、單位與對應的搜尋字串加入 _id 的選手，並更新性別與出生年範圍，
+// 不存在時新增；不覆寫整份資料，避免蓋掉同時進行的合併或拆分對姓名與單位的修改
+func (*athleteStore) SaveAthlete(ctx context.Context, athlete *models.Athlete) error {
+	setSearchKeys(athlete)
+	update := bson.M{
+		"$setOnInsert": bson.M{"name": athlete.Name, "created_at": athlete.CreatedAt},
+		"$set": bson.M{
+			"gender":         athlete.Gender,
+			"birth_year_min": athlete.BirthYearMin,
+			"birth_year_max": athlete.BirthYearMax,
+			"updated_at":     athlete.UpdatedAt,
+		},
+		"$addToSet": bson.M{
+			"names":        bson.M{"$each": nonNil(athlete.Names)},
+			"units":        bson.M{"$each": nonNil(athlete.Units)},
+			"search_names": bson.M{"$each": nonNil(athlete.SearchNames)},
+			"search_grams": bson.M{"$each": nonNil(athlete.SearchGrams)},
+		},
+	}
+	_, err := mgo.UpdateOne(ctx, athlete, bson.M{"_id": athlete.ID}, update, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("save athlete error: %w", err)
+	}
+	return nil
+}
+
+// LinkResultAthletes 設定成績的選手 ID，順序與成績的選手姓名相同，並更新原本與新連結選手的出賽次數
+func (*athleteStore) LinkResultAthletes(ctx context.Context, resultID bson.ObjectID, athleteIDs []bson.ObjectID) error {
+	return RunInTransaction(ctx, func(ctx context.Context) error {
+		result := models.NewRaceResult()
+		if err := mgo.FindOne(ctx, result, bson.M{"_id": resultID}); err != nil {
+			return fmt.Errorf("find result error: %w", err)
+		}
+		_, err := mgo.UpdateOne(ctx, result, bson.M{"_id": resultID},
+			bson.M{"$set": bson.M{"athlete_ids": athleteIDs}})
+		if err != nil {
+			return fmt.Errorf("link result athletes error: %w", err)
+		}
+		return updateRaceCounts(ctx, append(result.AthleteIDs, athleteIDs...))
+	})
+}
+
+// MergeAthletes 將 sources 合併到 target：target 取得 sources 的姓名寫法與單位，
+// sources 標記為已合併，原本連結到 sources 的成績改連結到 target 並重新計算出賽次數，回傳更新的成績數
+func (*athleteStore) MergeAthletes(ctx context.Context, target *models.Athlete, sources []*models.Athlete) (int64, error) {
+	now := time.Now()
+	sourceIDs := make([]bson.ObjectID, len(sources))
+	for i, source := range sources {
+		sourceIDs[i] = source.ID
+		for _, name := range source.Names {
+			if !slices.Contains(target.Names, name) {
+				target.Names = append(target.Names, name)
+			}
+		}
+		for _, unit := range source.Units {
+			if !slices.Contains(target.Units, unit) {
+				target.Units = append(target.Units, unit)
+			}
+		}
+		if target.Gender == "" {
+			target.Gender = source.Gender
+		}
+	}
+	target.UpdatedAt = now
+	setSearchKeys(target)
+
+	var updated int64
+	err := RunInTransaction(ctx, func(ctx context.Context) error {
+		updated = 0
+		_, err := mgo.UpdateOne(ctx, target, bson.M{"_id": target.ID}, bson.M{"$set": target})
+		if err != nil {
+			return fmt.Errorf("update target athlete error: %w", err)
+		}
+		// 先前已合併到 sources 的選手一併改指向 target
+		_, err = mgo.UpdateMany(ctx, models.NewAthlete(),
+			bson.M{"$or": bson.A{
+				bson.M{"_id": bson.M{"$in": sourceIDs}},
+				bson.M{"merged_into": bson.M{"$in": sourceIDs}},
+			}},
+			bson.M{"$set": bson.M{"merged_into": target.ID, "updated_at": now}})
+		if err != nil {
+			return fmt.Errorf("mark merged athletes error: %w", err)
+		}
+		for _, sourceID := range sourceIDs {
+			n, err := relinkResults(ctx, bson.M{"athlete_ids": sourceID}, target.ID)
+			if err != nil {
+				return err
+			}
+			updated += n
+		}
+		return updateRaceCounts(ctx, append(sourceIDs, target.ID))
+	})
+	return updated, err
+}
+
+// SplitAthlete 將 athlete 在 units 的成績分給 newAthlete，之後這些單位的成績會比對到 newAthlete，
+// 並重新計算兩位選手的出賽次數，回傳更新的成績數
+func (*athleteStore) SplitAthlete(
+	ctx context.Context, athlete, newAthlete *models.Athlete, units []string,
+) (int64, error) {
+	now := time.Now()
+	athlete.Units = slices.DeleteFunc(athlete.Units, func(unit string) bool {
+		return slices.Contains(units, unit)
+	})
+	athlete.UpdatedAt = now
+	newAthlete.Units = units
+	newAthlete.CreatedAt = now
+	newAthlete.UpdatedAt = now
+	setSearchKeys(athlete)
+	setSearchKeys(newAthlete)
+
+	var updated int64
+	err := RunInTransaction(ctx, func(ctx context.Context) error {
+		_, err := mgo.UpdateOne(ctx, athlete, bson.M{"_id": athlete.ID}, bson.M{"$set": athlete})
+		if err != nil {
+			return fmt.Errorf("update athlete error: %w", err)
+		}
+		_, err = mgo.UpdateOne(ctx, newAthlete, bson.M{"_id": newAthlete.ID}, bson.M{"$set": newAthlete},
+			options.UpdateOne().SetUpsert(true))
+		if err != nil {
+			return fmt.Errorf("save new athlete error: %w", err)
+		}
+		updated, err = relinkResults(ctx,
+			bson.M{"athlete_ids": athlete.ID, "unit": bson.M{"$in": units}}, newAthlete.ID)
+		if err != nil {
+			return err
+		}
+		return updateRaceCounts(ctx, []bson.ObjectID{athlete.ID, newAthlete.ID})
+	})
+	return updated, err
+}
+
+// SearchAthletes 以姓名、拼音或拼音首字母搜尋尚未被合併的選手，依比對等級 (開頭、包含、模糊) 與出賽次數排序
+func (*athleteStore) SearchAthletes(
+	ctx context.Context, search AthleteSearch,
+) ([]*models.AggrAthleteSearch, error) {
+	query := athlete.NormalizeSearch(search.Query)
+	grams := athlete.QueryGrams(query)
+	if len(grams) == 0 {
+		return nil, nil
+	}
+	filter := bson.M{"search_grams": bson.M{"$in": grams}, "merged_into": bson.M{"$exists": false}}
+	if search.Gender != "" {
+		filter["gender"] = search.Gender
+	}
+	if len(search.Units) > 0 {
+		filter["units"] = bson.M{"$in": search.Units}
+	}
+	if search.BirthYear > 0 {
+		// 出生年範圍包含 BirthYear，兩端都未知的選手不列入
+		filter["$and"] = bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"birth_year_min": 0}, bson.M{"birth_year_min": bson.M{"$lte": search.BirthYear}},
+			}},
+			bson.M{"$or": bson.A{
+				bson.M{"birth_year_max": 0}, bson.M{"birth_year_max": bson.M{"$gte": search.BirthYear}},
+			}},
+			bson.M{"$or": bson.A{
+				bson.M{"birth_year_min": bson.M{"$gt": 0}}, bson.M{"birth_year_max": bson.M{"$gt": 0}},
+			}},
+		}
+	}
+	aggr := models.NewAggrAthleteSearch(query, grams, athlete.MinFuzzyGrams(grams), search.Limit)
+	athletes, err := mgo.PipeFind(ctx, aggr, filter)
+	if err != nil {
+		return nil, fmt.Errorf("search athletes error: %w", err)
+	}
+	return athletes, nil
+}
+
+// UpdateSearchKeys 重新計算所有選手的搜尋字串、n-gram 與出賽次數，回傳更新的選手數
+func (*athleteStore) UpdateSearchKeys(ctx context.Context) (int64, error) {
+	athletes, err := mgo.Find(ctx, models.NewAthlete(), bson.M{})
+	if err != nil {
+		return 0, fmt.Errorf("find athletes error: %w", err)
+	}
+	if len(athletes) == 0 {
+		return 0, nil
+	}
+	counts, err := mgo.PipeFind(ctx, models.NewAggrAthleteRaceCount(), bson.M{})
+	if err != nil {
+		return 0, fmt.Errorf("count athlete races error: %w", err)
+	}
+	raceCounts := make(map[bson.ObjectID]int, len(counts))
+	for _, c := range counts {
+		raceCounts[c.AthleteID] = c.RaceCount
+	}
+	bulk, err := mgo.NewBulkOperation(models.NewAthlete().C())
+	if err != nil {
+		return 0, fmt.Errorf("create bulk operation error: %w", err)
+	}
+	for _, a := range athletes {
+		setSearchKeys(a)
+		bulk = bulk.UpdateOne(bson.M{"_id": a.ID}, bson.M{"$set": bson.M{
+			"search_names": a.SearchNames, "search_grams": a.SearchGrams, "race_count": raceCounts[a.ID],
+		}})
+	}
+	if _, err := bulk.Execute(ctx); err != nil {
+		return 0, fmt.Errorf("update search keys error: %w", err)
+	}
+	return int64(len(athletes)), nil
+}
+
+// nonNil 讓空的 slice 編碼成空陣列而不是 null，$each 只接受陣列
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// setSearchKeys 由選手的姓名寫法計算搜尋字串與 n-gram
+func setSearchKeys(a *models.Athlete) {
+	a.SearchNames = athlete.SearchNames(a.Names)
+	a.SearchGrams = athlete.SearchGrams(a.SearchNames)
+}
+
+// AthleteSearch 是搜尋選手的條件，Query 以外的欄位零值代表不篩選
+type AthleteSearch struct {
+	Query     string
+	Gender    string   // 正規化後的性別 (M/F)
+	Units     []string // 曾代表的單位，任一個符合即可
+	BirthYear int      // 出生年 (民國)
+	Limit     int      // 筆數上限，<= 0 代表不限制
+}
+
+// updateRaceCounts 重新計算選手連結到的成績數，沒有成績的選手設為 0
+func updateRaceCounts(ctx context.Context, athleteIDs []bson.ObjectID) error {
+	ids := slices.DeleteFunc(slices.Clone(athleteIDs), bson.ObjectID.IsZero)
+	slices.SortFunc(ids, func(a, b bson.ObjectID) int { return bytes.Compare(a[:], b[:]) })
+	ids = slices.Compact(ids)
+	if len(ids) == 0 {
+		return nil
+	}
+	counts, err := mgo.PipeFind(ctx, models.NewAggrAthleteRaceCount(), bson.M{"athlete_ids": bson.M{"$in": ids}})
+	if err != nil {
+		return fmt.Errorf("count athlete races error: %w", err)
+	}
+	// 接力成績的其他棒次選手也會被算到，只更新 ids 中的選手
+	raceCounts := make(map[bson.ObjectID]int, len(counts))
+	for _, c := range counts {
+		raceCounts[c.AthleteID] = c.RaceCount
+	}
+	for _, id := range ids {
+		_, err := mgo.UpdateOne(ctx, models.NewAthlete(), bson.M{"_id": id},
+			bson.M{"$set": bson.M{"race_count": raceCounts[id]}})
+		if err != nil {
+			return fmt.Errorf("update athlete race count error: %w", err)
+		}
+	}
+	return nil
+}
+
+// relinkResults 將符合 filter 的成績中第一個符合的選手 ID 換成 athleteID
+func relinkResults(ctx context.Context, filter bson.M, athleteID bson.ObjectID) (int64, error) {
+	n, err := mgo.UpdateMany(ctx, models.NewRaceResult(), filter,
+		bson.M{"$set": bson.M{"athlete_ids.$": athleteID}})
+	if err != nil {
+		return 0, fmt.Errorf("relink results error: %w", err)
+	}
+	return n, nil
+}
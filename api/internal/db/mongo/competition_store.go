@@ -0,0 +1,155 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type CompetitionStore interface {
+	UpsertCompetition(ctx context.Context, competition *models.Competition) (bson.ObjectID, error)
+	FindCompetition(ctx context.Context, id bson.ObjectID) (*models.Competition, error)
+	FindCompetitions(ctx context.Context, year string) ([]*models.Competition, error)
+	GetCompetitionEvents(ctx context.Context, id bson.ObjectID) ([]*models.AggrCompetitionEvent, error)
+	GetCompetitionParticipants(ctx context.Context, id bson.ObjectID) (*models.AggrCompetitionParticipants, error)
+	GetRaceCompetitions(ctx context.Context, year string) ([]*models.AggrRaceCompetition, error)
+	SetRacesCompetition(ctx context.Context, year, competitionName string, id bson.ObjectID) (int64, error)
+}
+
+func newCompetitionStore() CompetitionStore {
+	return &competitionStore{}
+}
+
+type competitionStore struct{}
+
+// UpsertCompetition 以比賽的自然鍵新增或更新比賽，回傳資料庫中比賽的 ID。
+// 空白的欄位不會覆寫已有的資料，日期範圍只會擴大，讓每個項目寫入時都可以呼叫
+func (*competitionStore) UpsertCompetition(
+	ctx context.Context, competition *models.Competition,
+) (bson.ObjectID, error) {
+	competition.Key = competition.NaturalKey()
+	now := time.Now()
+	set := bson.M{
+		"name":       competition.Name,
+		"year":       competition.Year,
+		"ad_year":    competition.ADYear,
+		"updated_at": now,
+	}
+	optional := map[string]string{
+		"source":    competition.Source,
+		"source_id": competition.SourceID,
+		"organizer": competition.Organizer,
+		"venue":     competition.Venue,
+		"pool_type": competition.PoolType,
+	}
+	for field, value := range optional {
+		if value != "" {
+			set[field] = value
+		}
+	}
+	update := bson.M{
+		"$set":         set,
+		"$setOnInsert": bson.M{"_id": competition.ID, "created_at": now},
+	}
+	if !competition.StartDate.IsZero() {
+		update["$min"] = bson.M{"start_date": competition.StartDate}
+	}
+	if !competition.EndDate.IsZero() {
+		update["$max"] = bson.M{"end_date": competition.EndDate}
+	}
+	_, err := mgo.UpdateOne(ctx, competition, bson.M{"key": competition.Key}, update,
+		options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return bson.NilObjectID, fmt.Errorf("upsert competition error: %w", err)
+	}
+	stored := models.NewCompetition()
+	err = mgo.FindOne(ctx, stored, bson.M{"key": competition.Key})
+	if err != nil {
+		return bson.NilObjectID, fmt.Errorf("find upserted competition error: %w", err)
+	}
+	return stored.ID, nil
+}
+
+// FindCompetition 以 ID 找出比賽，找不到時回傳 nil
+func (*competitionStore) FindCompetition(ctx context.Context, id bson.ObjectID) (*models.Competition, error) {
+	competition := models.NewCompetition()
+	err := mgo.FindOne(ctx, competition, bson.M{"_id": id})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find competition error: %w", err)
+	}
+	return competition, nil
+}
+
+// FindCompetitions 依開始日期回傳一個年度的所有比賽
+func (*competitionStore) FindCompetitions(ctx context.Context, year string) ([]*models.Competition, error) {
+	competitions, err := mgo.Find(ctx, models.NewCompetition(), bson.M{"year": year},
+		options.Find().SetSort(bson.D{{Key: "start_date", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("find competitions error: %w", err)
+	}
+	return competitions, nil
+}
+
+// GetCompetitionEvents 依比賽時間回傳比賽的所有項目
+func (*competitionStore) GetCompetitionEvents(
+	ctx context.Context, id bson.ObjectID,
+) ([]*models.AggrCompetitionEvent, error) {
+	events, err := mgo.PipeFind(ctx, models.NewAggrCompetitionEvent(), bson.M{"competition_id": id})
+	if err != nil {
+		return nil, fmt.Errorf("get competition events error: %w", err)
+	}
+	return events, nil
+}
+
+// GetCompetitionParticipants 回傳比賽的成績筆數、選手數與隊伍數
+func (*competitionStore) GetCompetitionParticipants(
+	ctx context.Context, id bson.ObjectID,
+) (*models.AggrCompetitionParticipants, error) {
+	participants := models.NewAggrCompetitionParticipants()
+	err := mgo.PipeFindOne(ctx, participants, bson.M{"competition_id": id})
+	if err != nil {
+		// 還沒有任何成績
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return participants, nil
+		}
+		return nil, fmt.Errorf("get competition participants error: %w", err)
+	}
+	return participants, nil
+}
+
+// GetRaceCompetitions 依 race 的競賽名稱歸納出比賽，year 為空代表所有年份
+func (*competitionStore) GetRaceCompetitions(ctx context.Context, year string) ([]*models.AggrRaceCompetition, error) {
+	q := bson.M{}
+	if year != "" {
+		q["year"] = year
+	}
+	competitions, err := mgo.PipeFind(ctx, models.NewAggrRaceCompetition(), q)
+	if err != nil {
+		return nil, fmt.Errorf("get race competitions error: %w", err)
+	}
+	return competitions, nil
+}
+
+// SetRacesCompetition 將一場比賽所有的 race 連結到 competition
+func (*competitionStore) SetRacesCompetition(
+	ctx context.Context, year, competitionName string, id bson.ObjectID,
+) (int64, error) {
+	updated, err := mgo.UpdateMany(ctx, models.NewRace(),
+		bson.M{"year": year, "competition_name": competitionName},
+		bson.M{"$set": bson.M{"competition_id": id}})
+	if err != nil {
+		return 0, fmt.Errorf("set races competition error: %w", err)
+	}
+	return updated, nil
+}
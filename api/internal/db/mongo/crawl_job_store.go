@@ -0,0 +1,61 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type CrawlJobStore interface {
+	SaveCrawlJob(ctx context.Context, job *models.CrawlJob) error
+	FindCrawlJob(ctx context.Context, id string) (*models.CrawlJob, error)
+	FindRecentCrawlJobs(ctx context.Context, limit int64) ([]*models.CrawlJob, error)
+}
+
+func newCrawlJobStore() CrawlJobStore {
+	return &crawlJobStore{}
+}
+
+type crawlJobStore struct{}
+
+// SaveCrawlJob 以 _id 覆寫整份工作進度，不存在時新增
+func (*crawlJobStore) SaveCrawlJob(ctx context.Context, job *models.CrawlJob) error {
+	_, err := mgo.UpdateOne(ctx, job, bson.M{"_id": job.ID}, bson.M{"$set": job},
+		options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("save crawl job error: %w", err)
+	}
+	return nil
+}
+
+func (*crawlJobStore) FindCrawlJob(ctx context.Context, id string) (*models.CrawlJob, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job id: %w", err)
+	}
+	job := models.NewCrawlJob()
+	err = mgo.FindOne(ctx, job, bson.M{"_id": oid})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find crawl job error: %w", err)
+	}
+	return job, nil
+}
+
+func (*crawlJobStore) FindRecentCrawlJobs(ctx context.Context, limit int64) ([]*models.CrawlJob, error) {
+	jobs, err := mgo.Find(ctx, models.NewCrawlJob(), bson.M{},
+		options.Find().SetSort(bson.D{{Key: "started_at", Value: -1}}).SetLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("find crawl jobs error: %w", err)
+	}
+	return jobs, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/94peter/vulpes/db/mgo"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type Query interface {
@@ -27,8 +28,15 @@ func newCrawlLogStore() CrawlLogStore {
 
 type crawlLogStore struct{}
 
+// SaveCrawlLog 以 URL 新增爬取紀錄，已經存在時保留第一次的紀錄
 func (*crawlLogStore) SaveCrawlLog(ctx context.Context, crawlLog *models.CrawlLog) error {
-	_, err := mgo.Save(ctx, crawlLog)
+	update := bson.M{"$setOnInsert": bson.M{
+		"_id":       crawlLog.ID,
+		"url":       crawlLog.URL,
+		"createdAt": crawlLog.CreatedAt,
+	}}
+	_, err := mgo.UpdateOne(ctx, crawlLog, bson.M{"url": crawlLog.URL}, update,
+		options.UpdateOne().SetUpsert(true))
 	if err != nil {
 		return fmt.Errorf("save crawl log error: %w", err)
 	}
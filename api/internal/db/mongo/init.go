@@ -10,8 +10,17 @@ import (
 )
 
 type Stores struct {
-	CrawlLogStore CrawlLogStore
-	RaceStore     RaceStore
+	AthleteStore     AthleteStore
+	AthleteBestStore AthleteBestStore
+	CompetitionStore CompetitionStore
+	CrawlLogStore    CrawlLogStore
+	CrawlJobStore    CrawlJobStore
+	LeaseStore       LeaseStore
+	RaceStore        RaceStore
+	RaceChangeStore  RaceChangeStore
+	RawPageStore     RawPageStore
+	RecordStore      RecordStore
+	TeamStore        TeamStore
 }
 
 var store *Stores
@@ -35,8 +44,17 @@ func IniMongodb(ctx context.Context, uri string, dbName string) (db.CloseDbFunc,
 
 	raceStoreTracer := otel.Tracer("RaceStore")
 	store = &Stores{
-		CrawlLogStore: newCrawlLogStore(),
-		RaceStore:     newRaceStore(raceStoreTracer),
+		AthleteStore:     newAthleteStore(),
+		AthleteBestStore: newAthleteBestStore(),
+		CompetitionStore: newCompetitionStore(),
+		CrawlLogStore:    newCrawlLogStore(),
+		CrawlJobStore:    newCrawlJobStore(),
+		LeaseStore:       newLeaseStore(),
+		RaceStore:        newRaceStore(raceStoreTracer),
+		RaceChangeStore:  newRaceChangeStore(),
+		RawPageStore:     newRawPageStore(),
+		RecordStore:      newRecordStore(),
+		TeamStore:        newTeamStore(),
 	}
 
 	return mgo.Close, nil
@@ -0,0 +1,55 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type LeaseStore interface {
+	AcquireLease(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, name, owner string) error
+}
+
+func newLeaseStore() LeaseStore {
+	return &leaseStore{}
+}
+
+type leaseStore struct{}
+
+// AcquireLease 取得或延長租約，租約由其他持有者持有且尚未到期時回傳 false
+func (*leaseStore) AcquireLease(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": name,
+		"$or": bson.A{
+			bson.M{"owner": owner},
+			bson.M{"expires_at": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"owner": owner, "expires_at": now.Add(ttl)}}
+	_, err := mgo.UpdateOne(ctx, models.NewLease(name), filter, update, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		// 租約存在但不符合條件時 upsert 會以相同 _id 新增而失敗，代表由其他持有者持有
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("acquire lease error: %w", err)
+	}
+	return true, nil
+}
+
+func (*leaseStore) ReleaseLease(ctx context.Context, name, owner string) error {
+	_, err := mgo.DeleteMany(ctx, models.NewLease(name), bson.M{"_id": name, "owner": owner})
+	if err != nil {
+		return fmt.Errorf("release lease error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func NewAggrAthleteRaceCount() *AggrAthleteRaceCount {
+	return &AggrAthleteRaceCount{
+		Index: raceResultCollection,
+	}
+}
+
+// AggrAthleteRaceCount 是一位選手連結到的成績數，接力成績每一棒的選手各算一筆
+type AggrAthleteRaceCount struct {
+	mgo.Index `bson:"-"`
+	AthleteID bson.ObjectID `bson:"_id"`
+	RaceCount int           `bson:"race_count"`
+}
+
+func (a *AggrAthleteRaceCount) GetPipeline(q bson.M) mongo.Pipeline {
+	return mongo.Pipeline{
+		{
+			{Key: "$match", Value: q},
+		},
+		{
+			{Key: "$unwind", Value: "$athlete_ids"},
+		},
+		{
+			{Key: "$group", Value: bson.M{
+				"_id":        "$athlete_ids",
+				"race_count": bson.M{"$sum": 1},
+			}},
+		},
+	}
+}
@@ -16,16 +16,21 @@ func NewAggrAthleteJoinRacesFilterByAthlete() *AggrAthleteJoinRacesFilterByAthle
 
 type AggrAthleteJoinRacesFilterByAthlete struct {
 	mgo.Index       `bson:"-"`
-	RaceID          string    `bson:"race_id"`
-	CompetitionName string    `bson:"competition_name"`
-	PoolType        string    `bson:"pool_type"`
-	EventName       string    `bson:"event_name"`
-	EventType       string    `bson:"event_type"`
-	EventDate       time.Time `bson:"event_date"`
-	Record          float64   `bson:"record"`
-	Rank            int       `bson:"rank"`
-	Score           int       `bson:"score"`
-	Note            string    `bson:"note"`
+	RaceID          string        `bson:"race_id"`
+	CompetitionName string        `bson:"competition_name"`
+	PoolType        string        `bson:"pool_type"`
+	EventName       string        `bson:"event_name"`
+	EventType       string        `bson:"event_type"`
+	EventDate       time.Time     `bson:"event_date"`
+	Record          float64       `bson:"record"`
+	Rank            int           `bson:"rank"`
+	Score           int           `bson:"score"`
+	Note            string        `bson:"note"`
+	Status          string        `bson:"status"`
+	StatusReason    string        `bson:"status_reason"`
+	Splits          []Split       `bson:"splits"`
+	ReactionTime    time.Duration `bson:"reaction_time"`
+	RaceEvent       `bson:",inline"`
 }
 
 func (*AggrAthleteJoinRacesFilterByAthlete) GetPipeline(q bson.M) mongo.Pipeline {
@@ -53,12 +58,21 @@ func (*AggrAthleteJoinRacesFilterByAthlete) GetPipeline(q bson.M) mongo.Pipeline
 				"competition_name": "$results.competition_name",
 				"event_name":       "$results.event_name",
 				"event_type":       "$results.event_type",
+				"distance":         "$results.distance",
+				"stroke":           "$results.stroke",
+				"relay":            "$results.relay",
+				"relay_count":      "$results.relay_count",
+				"round":            "$results.round",
 				"event_date":       "$results.time",
 				"pool_type":        "$results.pool_type",
 				"record":           "$record",
 				"rank":             "$rank",
 				"score":            "$score",
 				"note":             "$note",
+				"status":           "$status",
+				"status_reason":    "$status_reason",
+				"splits":           "$splits",
+				"reaction_time":    "$reaction_time",
 			}},
 		},
 	}
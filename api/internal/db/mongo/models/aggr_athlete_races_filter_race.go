@@ -8,25 +8,35 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
-func NewAggrAthleteJoinRacesFilterByRace(athleteName string) *AggrAthleteJoinRacesFilterByRace {
+// NewAggrAthleteJoinRacesFilterByRace 建立選手在項目中的成績查詢，resultFilter 以 "results." 為前綴比對成績
+func NewAggrAthleteJoinRacesFilterByRace(resultFilter bson.M) *AggrAthleteJoinRacesFilterByRace {
 	return &AggrAthleteJoinRacesFilterByRace{
-		Index:       raceCollection,
-		athleteName: athleteName,
+		Index:        raceCollection,
+		resultFilter: resultFilter,
 	}
 }
 
 type AggrAthleteJoinRacesFilterByRace struct {
 	mgo.Index       `bson:"-"`
-	RaceID          string    `bson:"race_id"`
-	CompetitionName string    `bson:"competition_name"`
-	EventName       string    `bson:"event_name"`
-	EventType       string    `bson:"event_type"`
-	EventDate       time.Time `bson:"event_date"`
-	Record          float64   `bson:"record"`
-	Rank            int       `bson:"rank"`
-	Score           int       `bson:"score"`
-	Note            string    `bson:"note"`
-	athleteName     string
+	RaceID          string        `bson:"race_id"`
+	CompetitionName string        `bson:"competition_name"`
+	EventName       string        `bson:"event_name"`
+	EventType       string        `bson:"event_type"`
+	EventDate       time.Time     `bson:"event_date"`
+	Record          float64       `bson:"record"`
+	Rank            int           `bson:"rank"`
+	Score           int           `bson:"score"`
+	Note            string        `bson:"note"`
+	Status          string        `bson:"status"`
+	StatusReason    string        `bson:"status_reason"`
+	Unit            string        `bson:"unit"`
+	TeamID          bson.ObjectID `bson:"team_id,omitempty"`
+	Team            string        `bson:"team"`
+	Legs            []RelayLeg    `bson:"legs"`
+	Splits          []Split       `bson:"splits"`
+	ReactionTime    time.Duration `bson:"reaction_time"`
+	RaceEvent       `bson:",inline"`
+	resultFilter    bson.M
 }
 
 func (a *AggrAthleteJoinRacesFilterByRace) GetPipeline(q bson.M) mongo.Pipeline {
@@ -48,11 +58,9 @@ func (a *AggrAthleteJoinRacesFilterByRace) GetPipeline(q bson.M) mongo.Pipeline
 		{
 			{Key: "$unwind", Value: "$results"},
 		},
-		// Stage 4: $match - Filter the unwound results by athlete name
+		// Stage 4: $match - Filter the unwound results by athlete
 		{
-			{Key: "$match", Value: bson.M{
-				"results.name": bson.M{"$in": []string{a.athleteName}},
-			}},
+			{Key: "$match", Value: a.resultFilter},
 		},
 		// Stage 5: $project - Reshape the output document
 		{
@@ -61,11 +69,24 @@ func (a *AggrAthleteJoinRacesFilterByRace) GetPipeline(q bson.M) mongo.Pipeline
 				"competition_name": "$competition_name",
 				"event_name":       "$event_name",
 				"event_type":       "$event_type",
+				"distance":         "$distance",
+				"stroke":           "$stroke",
+				"relay":            "$relay",
+				"relay_count":      "$relay_count",
+				"round":            "$round",
 				"event_date":       "$time",
 				"record":           "$results.record",
 				"rank":             "$results.rank",
 				"score":            "$results.score",
 				"note":             "$results.note",
+				"status":           "$results.status",
+				"status_reason":    "$results.status_reason",
+				"unit":             "$results.unit",
+				"team_id":          "$results.team_id",
+				"team":             "$results.team",
+				"legs":             "$results.legs",
+				"splits":           "$results.splits",
+				"reaction_time":    "$results.reaction_time",
 			}},
 		},
 	}
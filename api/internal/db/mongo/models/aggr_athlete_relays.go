@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func NewAggrAthleteRelay() *AggrAthleteRelay {
+	return &AggrAthleteRelay{
+		Index: raceResultCollection,
+	}
+}
+
+// AggrAthleteRelay 是選手參加過的一筆接力成績
+type AggrAthleteRelay struct {
+	mgo.Index       `bson:"-"`
+	RaceID          string        `bson:"race_id"`
+	Year            string        `bson:"year"`
+	CompetitionName string        `bson:"competition_name"`
+	PoolType        string        `bson:"pool_type"`
+	EventName       string        `bson:"event_name"`
+	EventDate       time.Time     `bson:"event_date"`
+	Team            string        `bson:"team"`
+	Record          time.Duration `bson:"record"`
+	Rank            int           `bson:"rank"`
+	Score           int           `bson:"score"`
+	Note            string        `bson:"note"`
+	Legs            []RelayLeg    `bson:"legs"`
+	// AthleteIDs 與 Legs 順序相同的選手 ID
+	AthleteIDs []bson.ObjectID `bson:"athlete_ids"`
+	RaceEvent  `bson:",inline"`
+}
+
+func (*AggrAthleteRelay) GetPipeline(q bson.M) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		{
+			{Key: "$match", Value: q},
+		},
+		{
+			{Key: "$lookup", Value: bson.M{
+				"from":         raceCollectionName,
+				"localField":   "race_id",
+				"foreignField": "_id",
+				"as":           "race",
+			}},
+		},
+		{
+			{Key: "$unwind", Value: "$race"},
+		},
+		{
+			{Key: "$project", Value: bson.M{
+				"race_id":          bson.M{"$toString": "$race._id"},
+				"year":             "$race.year",
+				"competition_name": "$race.competition_name",
+				"pool_type":        "$race.pool_type",
+				"event_name":       "$race.event_name",
+				"event_date":       "$race.time",
+				"distance":         "$race.distance",
+				"stroke":           "$race.stroke",
+				"relay":            "$race.relay",
+				"relay_count":      "$race.relay_count",
+				"round":            "$race.round",
+				"team":             "$team",
+				"record":           "$record",
+				"rank":             "$rank",
+				"score":            "$score",
+				"note":             "$note",
+				"legs":             "$legs",
+				"athlete_ids":      "$athlete_ids",
+			}},
+		},
+		{
+			{Key: "$sort", Value: bson.D{{Key: "event_date", Value: -1}}},
+		},
+	}
+	return pipeline
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// 選手搜尋的比對等級，數字越小越優先
+const (
+	searchTierPrefix    = 0 // 姓名或拼音以搜尋字串開頭
+	searchTierSubstring = 1 // 姓名或拼音包含搜尋字串
+	searchTierFuzzy     = 2 // 相同的 n-gram 數達到 MinFuzzyGrams
+)
+
+// NewAggrAthleteSearch 建立選手搜尋的查詢，query 與 grams 為正規化後的搜尋字串與其 n-gram，
+// minGrams 為模糊比對至少要相同的 n-gram 數，limit <= 0 代表不限制筆數
+func NewAggrAthleteSearch(query string, grams []string, minGrams, limit int) *AggrAthleteSearch {
+	return &AggrAthleteSearch{
+		Index:    athleteCollection,
+		query:    query,
+		grams:    grams,
+		minGrams: minGrams,
+		limit:    limit,
+	}
+}
+
+// AggrAthleteSearch 是符合搜尋的一位選手與其出賽次數
+type AggrAthleteSearch struct {
+	mgo.Index    `bson:"-"`
+	ID           bson.ObjectID `bson:"_id"`
+	Name         string        `bson:"name"`
+	Names        []string      `bson:"names"`
+	Gender       string        `bson:"gender"`
+	Units        []string      `bson:"units"`
+	BirthYearMin int           `bson:"birth_year_min"`
+	BirthYearMax int           `bson:"birth_year_max"`
+	RaceCount    int           `bson:"race_count"`
+	Tier         int           `bson:"tier"`
+	query        string
+	grams        []string
+	minGrams     int
+	limit        int
+}
+
+func (a *AggrAthleteSearch) GetPipeline(q bson.M) mongo.Pipeline {
+	// indexOfCP 找不到時為 -1，最大值大於 0 代表在姓名或拼音中間找到
+	isSubstring := bson.M{"$gt": bson.A{bson.M{"$max": "$positions"}, 0}}
+	pipeline := mongo.Pipeline{
+		{
+			{Key: "$match", Value: q},
+		},
+		{
+			{Key: "$addFields", Value: bson.M{
+				"matched_grams": bson.M{"$size": bson.M{"$setIntersection": bson.A{"$search_grams", a.grams}}},
+				"positions": bson.M{"$map": bson.M{
+					"input": bson.M{"$ifNull": bson.A{"$search_names", bson.A{}}},
+					"as":    "name",
+					"in":    bson.M{"$indexOfCP": bson.A{"$$name", a.query}},
+				}},
+			}},
+		},
+		{
+			{Key: "$addFields", Value: bson.M{
+				"tier": bson.M{"$switch": bson.M{
+					"branches": bson.A{
+						bson.M{"case": bson.M{"$in": bson.A{0, "$positions"}}, "then": searchTierPrefix},
+						bson.M{"case": isSubstring, "then": searchTierSubstring},
+					},
+					"default": searchTierFuzzy,
+				}},
+			}},
+		},
+		{
+			{Key: "$match", Value: bson.M{"$or": bson.A{
+				bson.M{"tier": bson.M{"$lt": searchTierFuzzy}},
+				bson.M{"matched_grams": bson.M{"$gte": a.minGrams}},
+			}}},
+		},
+		{
+			// 比對等級相同時出賽次數多的選手優先，出賽次數存在選手中，見 Athlete.RaceCount
+			{Key: "$sort", Value: bson.D{
+				{Key: "tier", Value: 1}, {Key: "matched_grams", Value: -1},
+				{Key: "race_count", Value: -1}, {Key: "name", Value: 1},
+			}},
+		},
+	}
+	if a.limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: a.limit}})
+	}
+	return append(pipeline, bson.D{{Key: "$project", Value: bson.M{
+		"name":           1,
+		"names":          1,
+		"gender":         1,
+		"units":          1,
+		"birth_year_min": 1,
+		"birth_year_max": 1,
+		"race_count":     1,
+		"tier":           1,
+	}}})
+}
+
+// Match 回傳比對等級的名稱：prefix、substring 或 fuzzy
+func (a *AggrAthleteSearch) Match() string {
+	switch a.Tier {
+	case searchTierPrefix:
+		return "prefix"
+	case searchTierSubstring:
+		return "substring"
+	}
+	return "fuzzy"
+}
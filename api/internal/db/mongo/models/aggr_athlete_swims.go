@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func NewAggrAthleteSwim() *AggrAthleteSwim {
+	return &AggrAthleteSwim{
+		Index: raceResultCollection,
+	}
+}
+
+// NewAggrAthleteSwimHistory 回傳選手最近 limit 筆的個人項目成績，依比賽時間由新到舊排序
+func NewAggrAthleteSwimHistory(limit int) *AggrAthleteSwim {
+	return &AggrAthleteSwim{
+		Index: raceResultCollection,
+		limit: limit,
+	}
+}
+
+// AggrAthleteSwim 是連結到選手的一筆個人項目成績，用於計算個人最佳成績與成績趨勢
+type AggrAthleteSwim struct {
+	mgo.Index       `bson:"-"`
+	RaceID          bson.ObjectID   `bson:"race_id"`
+	AthleteIDs      []bson.ObjectID `bson:"athlete_ids"`
+	Name            []string        `bson:"name"`
+	Year            string          `bson:"year"`
+	CompetitionName string          `bson:"competition_name"`
+	EventName       string          `bson:"event_name"`
+	EventDate       time.Time       `bson:"event_date"`
+	Gender          string          `bson:"gender"`
+	AgeGroup        string          `bson:"age_group"`
+	PoolType        string          `bson:"pool_type"`
+	Record          time.Duration   `bson:"record"`
+	Note            string          `bson:"note"`
+	Status          string          `bson:"status"`
+	RaceEvent       `bson:",inline"`
+	limit           int
+}
+
+func (a *AggrAthleteSwim) GetPipeline(q bson.M) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		{
+			{Key: "$match", Value: q},
+		},
+		{
+			{Key: "$lookup", Value: bson.M{
+				"from":         raceCollectionName,
+				"localField":   "race_id",
+				"foreignField": "_id",
+				// 只取需要的欄位，不讀取 race 其餘的資料
+				"pipeline": bson.A{bson.M{"$project": bson.M{
+					"year": 1, "competition_name": 1, "event_name": 1, "time": 1, "gender": 1, "age_group": 1,
+					"pool_type": 1, "distance": 1, "stroke": 1, "relay": 1, "relay_count": 1, "round": 1,
+				}}},
+				"as": "race",
+			}},
+		},
+		{
+			{Key: "$unwind", Value: "$race"},
+		},
+		{
+			// 接力與尚未解析項目的成績不列入個人最佳成績
+			{Key: "$match", Value: bson.M{"race.relay": false, "race.distance": bson.M{"$gt": 0}}},
+		},
+		{
+			{Key: "$project", Value: bson.M{
+				"race_id":          "$race._id",
+				"athlete_ids":      "$athlete_ids",
+				"name":             "$name",
+				"year":             "$race.year",
+				"competition_name": "$race.competition_name",
+				"event_name":       "$race.event_name",
+				"event_date":       "$race.time",
+				"gender":           "$race.gender",
+				"age_group":        "$race.age_group",
+				"pool_type":        "$race.pool_type",
+				"distance":         "$race.distance",
+				"stroke":           "$race.stroke",
+				"relay":            "$race.relay",
+				"relay_count":      "$race.relay_count",
+				"round":            "$race.round",
+				"record":           "$record",
+				"note":             "$note",
+				"status":           "$status",
+			}},
+		},
+	}
+	if a.limit > 0 {
+		pipeline = append(pipeline,
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "event_date", Value: -1}}}},
+			bson.D{{Key: "$limit", Value: a.limit}},
+		)
+	}
+	return pipeline
+}
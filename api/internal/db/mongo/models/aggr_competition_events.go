@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func NewAggrCompetitionEvent() *AggrCompetitionEvent {
+	return &AggrCompetitionEvent{
+		Index: raceCollection,
+	}
+}
+
+// AggrCompetitionEvent 是比賽中的一個項目與其成績筆數
+type AggrCompetitionEvent struct {
+	mgo.Index `bson:"-"`
+	RaceID    string    `bson:"race_id"`
+	EventName string    `bson:"event_name"`
+	EventType string    `bson:"event_type"`
+	Gender    string    `bson:"gender"`
+	AgeGroup  string    `bson:"age_group"`
+	EventDate time.Time `bson:"event_date"`
+	Results   int       `bson:"results"` // 成績筆數
+	RaceEvent `bson:",inline"`
+}
+
+func (*AggrCompetitionEvent) GetPipeline(q bson.M) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		{
+			{Key: "$match", Value: q},
+		},
+		{
+			{Key: "$lookup", Value: bson.M{
+				"from":         raceResultCollectionName,
+				"localField":   "_id",
+				"foreignField": "race_id",
+				"as":           "results",
+			}},
+		},
+		{
+			{Key: "$project", Value: bson.M{
+				"race_id":     bson.M{"$toString": "$_id"},
+				"event_name":  "$event_name",
+				"event_type":  "$event_type",
+				"gender":      "$gender",
+				"age_group":   "$age_group",
+				"distance":    "$distance",
+				"stroke":      "$stroke",
+				"relay":       "$relay",
+				"relay_count": "$relay_count",
+				"round":       "$round",
+				"event_date":  "$time",
+				"results":     bson.M{"$size": "$results"},
+			}},
+		},
+		{
+			{Key: "$sort", Value: bson.D{{Key: "event_date", Value: 1}, {Key: "event_name", Value: 1}}},
+		},
+	}
+	return pipeline
+}
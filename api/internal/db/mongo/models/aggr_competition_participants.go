@@ -0,0 +1,80 @@
+package models
+
+import (
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func NewAggrCompetitionParticipants() *AggrCompetitionParticipants {
+	return &AggrCompetitionParticipants{
+		Index: raceCollection,
+	}
+}
+
+// AggrCompetitionParticipants 是比賽的參賽人數統計，尚未比對 athlete 或隊伍的成績以姓名或單位區分
+type AggrCompetitionParticipants struct {
+	mgo.Index `bson:"-"`
+	Results   int `bson:"results"`  // 成績筆數
+	Athletes  int `bson:"athletes"` // 不重複的選手數，接力的每一棒各算一位選手
+	Teams     int `bson:"teams"`    // 不重複的隊伍數
+}
+
+func (*AggrCompetitionParticipants) GetPipeline(q bson.M) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		{
+			{Key: "$match", Value: q},
+		},
+		{
+			{Key: "$lookup", Value: bson.M{
+				"from":         raceResultCollectionName,
+				"localField":   "_id",
+				"foreignField": "race_id",
+				"as":           "results",
+			}},
+		},
+		{
+			{Key: "$unwind", Value: "$results"},
+		},
+		// 有 athlete_id 或 team_id 時以 ID 區分，否則以姓名或單位區分
+		{
+			{Key: "$project", Value: bson.M{
+				"athletes": bson.M{"$map": bson.M{
+					"input": bson.M{"$zip": bson.M{
+						"inputs": bson.A{
+							"$results.name", bson.M{"$ifNull": bson.A{"$results.athlete_ids", bson.A{}}},
+						},
+						"useLongestLength": true,
+					}},
+					"as": "athlete",
+					"in": bson.M{"$ifNull": bson.A{
+						bson.M{"$arrayElemAt": bson.A{"$$athlete", 1}},
+						bson.M{"$arrayElemAt": bson.A{"$$athlete", 0}},
+					}},
+				}},
+				"team": bson.M{"$ifNull": bson.A{"$results.team_id", "$results.unit"}},
+			}},
+		},
+		{
+			{Key: "$group", Value: bson.M{
+				"_id":      nil,
+				"results":  bson.M{"$sum": 1},
+				"athletes": bson.M{"$push": "$athletes"},
+				"teams":    bson.M{"$addToSet": "$team"},
+			}},
+		},
+		{
+			{Key: "$project", Value: bson.M{
+				"_id":     0,
+				"results": "$results",
+				"athletes": bson.M{"$size": bson.M{"$reduce": bson.M{
+					"input":        "$athletes",
+					"initialValue": bson.A{},
+					"in":           bson.M{"$setUnion": bson.A{"$$value", "$$this"}},
+				}}},
+				"teams": bson.M{"$size": bson.M{"$setDifference": bson.A{"$teams", bson.A{"", nil}}}},
+			}},
+		},
+	}
+	return pipeline
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func NewAggrRaceCompetition() *AggrRaceCompetition {
+	return &AggrRaceCompetition{
+		Index: raceCollection,
+	}
+}
+
+// AggrRaceCompetition 是由 race 的競賽名稱歸納出的一場比賽，用來為舊資料建立 competition
+type AggrRaceCompetition struct {
+	mgo.Index       `bson:"-"`
+	Year            string    `bson:"year"`
+	CompetitionName string    `bson:"competition_name"`
+	Source          string    `bson:"source"`
+	Organizer       string    `bson:"organizer"`
+	PoolType        string    `bson:"pool_type"`
+	StartDate       time.Time `bson:"start_date"`
+	EndDate         time.Time `bson:"end_date"`
+	Races           int       `bson:"races"`
+}
+
+func (*AggrRaceCompetition) GetPipeline(q bson.M) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		{
+			{Key: "$match", Value: q},
+		},
+		{
+			{Key: "$group", Value: bson.M{
+				"_id":        bson.M{"year": "$year", "competition_name": "$competition_name"},
+				"source":     bson.M{"$max": "$source"},
+				"organizer":  bson.M{"$max": "$organizer"},
+				"pool_type":  bson.M{"$max": "$pool_type"},
+				"start_date": bson.M{"$min": "$time"},
+				"end_date":   bson.M{"$max": "$time"},
+				"races":      bson.M{"$sum": 1},
+			}},
+		},
+		{
+			{Key: "$project", Value: bson.M{
+				"_id":              0,
+				"year":             "$_id.year",
+				"competition_name": "$_id.competition_name",
+				"source":           "$source",
+				"organizer":        "$organizer",
+				"pool_type":        "$pool_type",
+				"start_date":       "$start_date",
+				"end_date":         "$end_date",
+				"races":            "$races",
+			}},
+		},
+		{
+			{Key: "$sort", Value: bson.D{{Key: "year", Value: 1}, {Key: "start_date", Value: 1}}},
+		},
+	}
+	return pipeline
+}
@@ -17,12 +17,15 @@ func NewAggrRaceWithResult() *AggrRaceWithResult {
 type AggrRaceWithResult struct {
 	mgo.Index `bson:"-"`
 	ID        bson.ObjectID `bson:"_id,omitempty"`
+	Source    string        // 成績來源
 	// 預賽 / 決賽
 	Type            string        // 賽事類型 (預賽/決賽)
 	Organizer       string        // 主辦單位
 	Year            string        // 年份
-	CompetitionName string        `bson:"competition_name"` // 競賽名稱
+	CompetitionName string        `bson:"competition_name"`         // 競賽名稱
+	CompetitionID   bson.ObjectID `bson:"competition_id,omitempty"` // 連結的比賽
 	Gender          string        // 性別組別
+	PoolType        string        `bson:"pool_type"`       // 水道
 	AgeGroup        string        `bson:"age_group"`       // 年齡組別
 	EventType       string        `bson:"event_type"`      // 項目類型
 	EventName       string        `bson:"event_name"`      // 項目名稱
@@ -30,13 +33,23 @@ type AggrRaceWithResult struct {
 	NationalRecord  time.Duration `bson:"national_record"` // 全國紀錄
 	Time            time.Time     // 賽事時間
 	CreatedAt       time.Time     `bson:"created_at"` // 創建時間
+	RaceEvent       `bson:",inline"`
 	Results         []*struct {
+		ID     bson.ObjectID `bson:"_id"`
 		Unit   string        `bson:"unit"`   // 單位
 		Name   []string      `bson:"name"`   // 選手姓名
 		Record time.Duration `bson:"record"` // 成績
 		Rank   int32         `bson:"rank"`   // 名次
 		Score  int32         `bson:"score"`  // 分數
 		Note   string        `bson:"note"`   // 備註
+		Team   string        `bson:"team"`   // 接力隊伍名稱
+		Legs   []RelayLeg    `bson:"legs"`   // 接力各棒
+		// AthleteIDs 與 Name 順序相同的選手 ID
+		AthleteIDs   []bson.ObjectID `bson:"athlete_ids"`
+		Splits       []Split         `bson:"splits"`        // 累計分段
+		ReactionTime time.Duration   `bson:"reaction_time"` // 出發反應時間
+		Status       string          `bson:"status"`        // 成績狀態
+		StatusReason string          `bson:"status_reason"` // 狀態說明
 	} `bson:"results"` // 結果
 }
 
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"time"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// rankingExcludedStatuses 是不列入排行的成績狀態，與 crawler.ResultStatus.HasValidTime 一致
+var rankingExcludedStatuses = bson.A{"dq", "dns", "dnf", "scratch"}
+
+// NewAggrRanking 建立項目排行的查詢，teamID 不為零值時只回傳最佳成績代表該隊伍游出的選手，
+// 名次仍是所有選手中的名次。offset 與 limit 用於分頁，limit <= 0 代表不限制筆數
+func NewAggrRanking(teamID bson.ObjectID, offset, limit int) *AggrRanking {
+	return &AggrRanking{
+		Index:  raceCollection,
+		teamID: teamID,
+		offset: offset,
+		limit:  limit,
+	}
+}
+
+// AggrRanking 是排行中的一位選手與其最佳成績，尚未比對選手的成績以姓名區分
+type AggrRanking struct {
+	mgo.Index       `bson:"-"`
+	Rank            int             `bson:"rank"`
+	AthleteIDs      []bson.ObjectID `bson:"athlete_ids"`
+	Name            []string        `bson:"name"`
+	Unit            string          `bson:"unit"`
+	TeamID          bson.ObjectID   `bson:"team_id,omitempty"`
+	Team            string          `bson:"team"`
+	Record          time.Duration   `bson:"record"`
+	RaceID          bson.ObjectID   `bson:"race_id"`
+	Year            string          `bson:"year"`
+	CompetitionName string          `bson:"competition_name"`
+	EventName       string          `bson:"event_name"`
+	EventDate       time.Time       `bson:"event_date"`
+	AgeGroup        string          `bson:"age_group"`
+	teamID          bson.ObjectID
+	offset          int
+	limit           int
+}
+
+func (a *AggrRanking) GetPipeline(q bson.M) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		{
+			{Key: "$match", Value: q},
+		},
+		{
+			{Key: "$lookup", Value: bson.M{
+				"from":         raceResultCollectionName,
+				"localField":   "_id",
+				"foreignField": "race_id",
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{
+						"record": bson.M{"$gt": 0},
+						"status": bson.M{"$nin": rankingExcludedStatuses},
+					}},
+				},
+				"as": "results",
+			}},
+		},
+		{
+			{Key: "$unwind", Value: "$results"},
+		},
+		{
+			// 同一位選手取最快的成績，成績相同時以較早的為準
+			{Key: "$sort", Value: bson.D{{Key: "results.record", Value: 1}, {Key: "time", Value: 1}}},
+		},
+		{
+			{Key: "$group", Value: bson.M{
+				"_id": bson.M{"$ifNull": bson.A{
+					bson.M{"$first": "$results.athlete_ids"}, bson.M{"$first": "$results.name"},
+				}},
+				"athlete_ids":      bson.M{"$first": "$results.athlete_ids"},
+				"name":             bson.M{"$first": "$results.name"},
+				"unit":             bson.M{"$first": "$results.unit"},
+				"team_id":          bson.M{"$first": "$results.team_id"},
+				"record":           bson.M{"$first": "$results.record"},
+				"race_id":          bson.M{"$first": "$_id"},
+				"year":             bson.M{"$first": "$year"},
+				"competition_name": bson.M{"$first": "$competition_name"},
+				"event_name":       bson.M{"$first": "$event_name"},
+				"event_date":       bson.M{"$first": "$time"},
+				"age_group":        bson.M{"$first": "$age_group"},
+			}},
+		},
+		{
+			// 成績相同的選手名次並列
+			{Key: "$setWindowFields", Value: bson.M{
+				"sortBy": bson.M{"record": 1},
+				"output": bson.M{"rank": bson.M{"$rank": bson.M{}}},
+			}},
+		},
+	}
+	if !a.teamID.IsZero() {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"team_id": a.teamID}}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$sort", Value: bson.D{
+			{Key: "rank", Value: 1}, {Key: "event_date", Value: 1}, {Key: "name", Value: 1},
+		}}},
+		bson.D{{Key: "$skip", Value: a.offset}},
+	)
+	if a.limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: a.limit}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$lookup", Value: bson.M{
+			"from":         teamCollectionName,
+			"localField":   "team_id",
+			"foreignField": "_id",
+			"as":           "team",
+		}}},
+		bson.D{{Key: "$project", Value: bson.M{
+			"_id":              0,
+			"rank":             1,
+			"athlete_ids":      1,
+			"name":             1,
+			"unit":             1,
+			"team_id":          1,
+			"team":             bson.M{"$ifNull": bson.A{bson.M{"$first": "$team.name"}, "$unit"}},
+			"record":           1,
+			"race_id":          1,
+			"year":             1,
+			"competition_name": 1,
+			"event_name":       1,
+			"event_date":       1,
+			"age_group":        1,
+		}}},
+	)
+	return pipeline
+}
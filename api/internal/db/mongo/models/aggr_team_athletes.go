@@ -0,0 +1,62 @@
+package models
+
+import (
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func NewAggrTeamAthlete() *AggrTeamAthlete {
+	return &AggrTeamAthlete{
+		Index: raceResultCollection,
+	}
+}
+
+// AggrTeamAthlete 是代表隊伍出賽過的一位選手，尚未比對 athlete 的成績以姓名區分
+type AggrTeamAthlete struct {
+	mgo.Index `bson:"-"`
+	AthleteID bson.ObjectID `bson:"athlete_id,omitempty"`
+	Name      string        `bson:"name"`
+	Results   int           `bson:"results"` // 代表隊伍的成績筆數
+}
+
+func (*AggrTeamAthlete) GetPipeline(q bson.M) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		{
+			{Key: "$match", Value: q},
+		},
+		// 將姓名與 athlete_ids 依順序配對，接力的每一棒各算一位選手
+		{
+			{Key: "$project", Value: bson.M{
+				"athletes": bson.M{"$zip": bson.M{
+					"inputs":           bson.A{"$name", bson.M{"$ifNull": bson.A{"$athlete_ids", bson.A{}}}},
+					"useLongestLength": true,
+				}},
+			}},
+		},
+		{
+			{Key: "$unwind", Value: "$athletes"},
+		},
+		{
+			{Key: "$group", Value: bson.M{
+				"_id": bson.M{
+					"name":       bson.M{"$arrayElemAt": bson.A{"$athletes", 0}},
+					"athlete_id": bson.M{"$arrayElemAt": bson.A{"$athletes", 1}},
+				},
+				"results": bson.M{"$sum": 1},
+			}},
+		},
+		{
+			{Key: "$project", Value: bson.M{
+				"_id":        0,
+				"name":       "$_id.name",
+				"athlete_id": "$_id.athlete_id",
+				"results":    "$results",
+			}},
+		},
+		{
+			{Key: "$sort", Value: bson.D{{Key: "name", Value: 1}}},
+		},
+	}
+	return pipeline
+}
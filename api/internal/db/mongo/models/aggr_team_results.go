@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// NewAggrTeamResult 建立隊伍成績的查詢，year 為空代表所有年份
+func NewAggrTeamResult(year string) *AggrTeamResult {
+	return &AggrTeamResult{
+		Index: raceResultCollection,
+		year:  year,
+	}
+}
+
+// AggrTeamResult 是代表隊伍出賽的一筆成績
+type AggrTeamResult struct {
+	mgo.Index       `bson:"-"`
+	RaceID          string        `bson:"race_id"`
+	Year            string        `bson:"year"`
+	CompetitionName string        `bson:"competition_name"`
+	EventName       string        `bson:"event_name"`
+	EventDate       time.Time     `bson:"event_date"`
+	Name            []string      `bson:"name"`
+	Record          time.Duration `bson:"record"`
+	Rank            int           `bson:"rank"`
+	Score           int           `bson:"score"`
+	Note            string        `bson:"note"`
+	Status          string        `bson:"status"`
+	StatusReason    string        `bson:"status_reason"`
+	RaceEvent       `bson:",inline"`
+	year            string
+}
+
+func (a *AggrTeamResult) GetPipeline(q bson.M) mongo.Pipeline {
+	raceMatch := bson.M{}
+	if a.year != "" {
+		raceMatch["race.year"] = a.year
+	}
+	pipeline := mongo.Pipeline{
+		{
+			{Key: "$match", Value: q},
+		},
+		{
+			{Key: "$lookup", Value: bson.M{
+				"from":         raceCollectionName,
+				"localField":   "race_id",
+				"foreignField": "_id",
+				"as":           "race",
+			}},
+		},
+		{
+			{Key: "$unwind", Value: "$race"},
+		},
+		{
+			{Key: "$match", Value: raceMatch},
+		},
+		{
+			{Key: "$project", Value: bson.M{
+				"race_id":          bson.M{"$toString": "$race._id"},
+				"year":             "$race.year",
+				"competition_name": "$race.competition_name",
+				"event_name":       "$race.event_name",
+				"event_date":       "$race.time",
+				"distance":         "$race.distance",
+				"stroke":           "$race.stroke",
+				"relay":            "$race.relay",
+				"relay_count":      "$race.relay_count",
+				"round":            "$race.round",
+				"name":             "$name",
+				"record":           "$record",
+				"rank":             "$rank",
+				"score":            "$score",
+				"note":             "$note",
+				"status":           "$status",
+				"status_reason":    "$status_reason",
+			}},
+		},
+		{
+			{Key: "$sort", Value: bson.D{{Key: "event_date", Value: -1}}},
+		},
+	}
+	return pipeline
+}
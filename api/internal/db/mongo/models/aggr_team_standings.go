@@ -0,0 +1,104 @@
+package models
+
+import (
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// 獎牌對應的名次
+const (
+	rankGold   = 1
+	rankSilver = 2
+	rankBronze = 3
+)
+
+// qualifierRounds 是名次不是最終結果的賽次 (預賽、準決賽與快組計時決賽)，不列入獎牌與積分，
+// 否則預賽第一名又在決賽得牌的選手會被重複計算
+var qualifierRounds = bson.A{"heat", "semifinal", "fast_heat_timed_final"}
+
+func NewAggrTeamStanding() *AggrTeamStanding {
+	return &AggrTeamStanding{
+		Index: raceCollection,
+	}
+}
+
+// AggrTeamStanding 是一場比賽中一個隊伍的獎牌數與積分，只計算決賽賽次中有名次的成績
+type AggrTeamStanding struct {
+	mgo.Index `bson:"-"`
+	TeamID    bson.ObjectID `bson:"team_id,omitempty"`
+	Team      string        `bson:"team"`
+	Gold      int           `bson:"gold"`
+	Silver    int           `bson:"silver"`
+	Bronze    int           `bson:"bronze"`
+	Points    int           `bson:"points"`
+}
+
+func (*AggrTeamStanding) GetPipeline(q bson.M) mongo.Pipeline {
+	medal := func(rank int) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$results.rank", rank}}, 1, 0}}}
+	}
+	isTeamID := bson.M{"$eq": bson.A{bson.M{"$type": "$_id"}, "objectId"}}
+	pipeline := mongo.Pipeline{
+		{
+			{Key: "$match", Value: q},
+		},
+		{
+			{Key: "$match", Value: bson.M{"round": bson.M{"$nin": qualifierRounds}}},
+		},
+		{
+			{Key: "$lookup", Value: bson.M{
+				"from":         raceResultCollectionName,
+				"localField":   "_id",
+				"foreignField": "race_id",
+				"as":           "results",
+			}},
+		},
+		{
+			{Key: "$unwind", Value: "$results"},
+		},
+		{
+			{Key: "$match", Value: bson.M{"results.rank": bson.M{"$gt": 0}}},
+		},
+		// 尚未對應隊伍的成績以單位名稱分組
+		{
+			{Key: "$group", Value: bson.M{
+				"_id":    bson.M{"$ifNull": bson.A{"$results.team_id", "$results.unit"}},
+				"unit":   bson.M{"$first": "$results.unit"},
+				"gold":   medal(rankGold),
+				"silver": medal(rankSilver),
+				"bronze": medal(rankBronze),
+				"points": bson.M{"$sum": "$results.score"},
+			}},
+		},
+		{
+			{Key: "$lookup", Value: bson.M{
+				"from":         teamCollectionName,
+				"localField":   "_id",
+				"foreignField": "_id",
+				"as":           "team",
+			}},
+		},
+		{
+			{Key: "$project", Value: bson.M{
+				"_id":     0,
+				"team_id": bson.M{"$cond": bson.A{isTeamID, "$_id", "$$REMOVE"}},
+				"team":    bson.M{"$ifNull": bson.A{bson.M{"$first": "$team.name"}, "$unit"}},
+				"gold":    1,
+				"silver":  1,
+				"bronze":  1,
+				"points":  1,
+			}},
+		},
+		{
+			{Key: "$sort", Value: bson.D{
+				{Key: "points", Value: -1},
+				{Key: "gold", Value: -1},
+				{Key: "silver", Value: -1},
+				{Key: "bronze", Value: -1},
+				{Key: "team", Value: 1},
+			}},
+		},
+	}
+	return pipeline
+}
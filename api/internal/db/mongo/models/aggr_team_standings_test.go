@@ -0,0 +1,35 @@
+package models
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// 預賽與決賽都有名次時，只有決賽的名次列入獎牌與積分
+func TestAggrTeamStanding_GetPipeline_skipsQualifierRounds(t *testing.T) {
+	races := []*Race{
+		{EventName: "100公尺自由式 預賽", RaceEvent: RaceEvent{Round: "heat"}},
+		{EventName: "100公尺自由式 準決賽", RaceEvent: RaceEvent{Round: "semifinal"}},
+		{EventName: "100公尺自由式 決賽", RaceEvent: RaceEvent{Round: "final"}},
+		{EventName: "50公尺蝶式 計時決賽", RaceEvent: RaceEvent{Round: "timed_final"}},
+		{EventName: "200公尺蛙式 快組計時決賽", RaceEvent: RaceEvent{Round: "fast_heat_timed_final"}},
+		{EventName: "200公尺仰式", RaceEvent: RaceEvent{Round: ""}},
+	}
+
+	pipeline := NewAggrTeamStanding().GetPipeline(bson.M{"year": "113"})
+	require.Greater(t, len(pipeline), 2)
+	assert.Equal(t, "$match", pipeline[1][0].Key)
+	// 依第二個 $match 的 $nin 判斷 race 是否列入計算
+	excluded := pipeline[1][0].Value.(bson.M)["round"].(bson.M)["$nin"].(bson.A)
+	var counted []string
+	for _, race := range races {
+		if !slices.Contains(excluded, any(race.Round)) {
+			counted = append(counted, race.EventName)
+		}
+	}
+	assert.Equal(t, []string{"100公尺自由式 決賽", "50公尺蝶式 計時決賽", "200公尺仰式"}, counted)
+}
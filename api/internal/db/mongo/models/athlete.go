@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const athleteCollectionName = "athlete"
+
+var athleteCollection = mgo.NewCollectDef(athleteCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "names", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "merged_into", Value: 1}},
+		},
+		{
+			// 選手搜尋的 n-gram 索引，見 athlete.SearchGrams
+			Keys: bson.D{{Key: "search_grams", Value: 1}},
+		},
+	}
+})
+
+func init() {
+	mgo.RegisterIndex(athleteCollection)
+}
+
+func NewAthlete() *Athlete {
+	return &Athlete{
+		Index: athleteCollection,
+		ID:    bson.NewObjectID(),
+	}
+}
+
+// Athlete 是一位選手，raceResult 以 athlete_ids 連結到選手
+type Athlete struct {
+	mgo.Index    `bson:"-"`
+	ID           bson.ObjectID `bson:"_id,omitempty"`
+	Name         string        `bson:"name"`                  // 顯示名稱
+	Names        []string      `bson:"names"`                 // 比對成績時使用的所有姓名寫法 (含顯示名稱)
+	Gender       string        `bson:"gender"`                // 性別 (M/F)，空白代表未知
+	Units        []string      `bson:"units"`                 // 曾代表的單位
+	BirthYearMin int           `bson:"birth_year_min"`        // 由年齡組推估的出生年 (民國) 下限，0 代表未知
+	BirthYearMax int           `bson:"birth_year_max"`        // 由年齡組推估的出生年 (民國) 上限，0 代表未知
+	MergedInto   bson.ObjectID `bson:"merged_into,omitempty"` // 已合併到的選手，合併後不再用來比對成績
+	SearchNames  []string      `bson:"search_names"`          // 搜尋用的姓名與拼音，見 athlete.SearchNames
+	SearchGrams  []string      `bson:"search_grams"`          // search_names 的 n-gram，見 athlete.SearchGrams
+	RaceCount    int           `bson:"race_count"`            // 連結到選手的成績數，搜尋時排序用
+	CreatedAt    time.Time     `bson:"created_at"`            // 創建時間
+	UpdatedAt    time.Time     `bson:"updated_at"`            // 更新時間
+}
+
+func (s *Athlete) GetId() any {
+	if s.ID.IsZero() {
+		return nil
+	}
+	return s.ID
+}
+
+func (s *Athlete) SetId(id any) {
+	oid, ok := id.(bson.ObjectID)
+	if !ok {
+		return
+	}
+	s.ID = oid
+}
+
+func (*Athlete) Validate() error {
+	return nil
+}
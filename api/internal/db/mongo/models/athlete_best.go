@@ -0,0 +1,96 @@
+package models
+
+import (
+	"time"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const athleteBestCollectionName = "athlete_best"
+
+var athleteBestCollection = mgo.NewCollectDef(athleteBestCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			// 每位選手每個項目與水道一份
+			Keys: bson.D{
+				{Key: "athlete_id", Value: 1}, {Key: "pool_type", Value: 1},
+				{Key: "stroke", Value: 1}, {Key: "distance", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "name", Value: 1}},
+		},
+		{
+			Keys: bson.D{
+				{Key: "stroke", Value: 1}, {Key: "distance", Value: 1}, {Key: "pool_type", Value: 1},
+				{Key: "gender", Value: 1}, {Key: "record", Value: 1},
+			},
+		},
+	}
+})
+
+func init() {
+	mgo.RegisterIndex(athleteBestCollection)
+}
+
+func NewAthleteBest() *AthleteBest {
+	return &AthleteBest{
+		Index: athleteBestCollection,
+		ID:    bson.NewObjectID(),
+	}
+}
+
+// AthleteBest 是選手在一個項目與水道的個人最佳成績與每年的季最佳，由成績計算而來
+type AthleteBest struct {
+	mgo.Index `bson:"-"`
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	AthleteID bson.ObjectID `bson:"athlete_id"` // 選手
+	Name      string        `bson:"name"`       // 最近一次成績上的姓名
+	Gender    string        `bson:"gender"`     // 最近一次成績的性別組別
+	EventType string        `bson:"event_type"` // 項目類型，例如 "100公尺仰式"
+	Distance  int           `bson:"distance"`   // 距離 (公尺)
+	Stroke    string        `bson:"stroke"`     // 泳式
+	PoolType  string        `bson:"pool_type"`  // 水道
+	BestSwim  `bson:",inline"`
+	Seasons   []SeasonBest `bson:"seasons"`    // 每年的季最佳，由新到舊
+	UpdatedAt time.Time    `bson:"updated_at"` // 更新時間
+}
+
+// BestSwim 是最佳成績與其出處
+type BestSwim struct {
+	Record          time.Duration `bson:"record"`           // 成績
+	Date            time.Time     `bson:"date"`             // 比賽日期
+	RaceID          bson.ObjectID `bson:"race_id"`          // 項目
+	CompetitionName string        `bson:"competition_name"` // 競賽名稱
+	EventName       string        `bson:"event_name"`       // 項目名稱
+}
+
+// SeasonBest 是一年 (民國年) 中的最佳成績
+type SeasonBest struct {
+	Year     string `bson:"year"`      // 年份
+	AgeGroup string `bson:"age_group"` // 當年的年齡組別
+	BestSwim `bson:",inline"`
+}
+
+func (s *AthleteBest) GetId() any {
+	if s.ID.IsZero() {
+		return nil
+	}
+	return s.ID
+}
+
+func (s *AthleteBest) SetId(id any) {
+	oid, ok := id.(bson.ObjectID)
+	if !ok {
+		return
+	}
+	s.ID = oid
+}
+
+func (*AthleteBest) Validate() error {
+	return nil
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const competitionCollectionName = "competition"
+
+var competitionCollection = mgo.NewCollectDef(competitionCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "year", Value: 1}, {Key: "start_date", Value: 1}},
+		},
+	}
+})
+
+func init() {
+	mgo.RegisterIndex(competitionCollection)
+}
+
+func NewCompetition() *Competition {
+	return &Competition{
+		Index: competitionCollection,
+		ID:    bson.NewObjectID(),
+	}
+}
+
+// Competition 是一場比賽 (大會)，race 以 competition_id 連結到比賽
+type Competition struct {
+	mgo.Index `bson:"-"`
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	Key       string        `bson:"key"`        // 自然鍵，見 NaturalKey
+	Source    string        `bson:"source"`     // 成績來源
+	SourceID  string        `bson:"source_id"`  // 來源網站上的比賽 ID，例如 CTSA 的活動 ID
+	Name      string        `bson:"name"`       // 競賽名稱
+	Year      string        `bson:"year"`       // 民國年
+	ADYear    int           `bson:"ad_year"`    // 西元年
+	StartDate time.Time     `bson:"start_date"` // 第一個項目的日期
+	EndDate   time.Time     `bson:"end_date"`   // 最後一個項目的日期
+	Organizer string        `bson:"organizer"`  // 主辦單位
+	Venue     string        `bson:"venue"`      // 比賽場地，來源沒有提供時為空
+	PoolType  string        `bson:"pool_type"`  // 水道
+	CreatedAt time.Time     `bson:"created_at"` // 創建時間
+	UpdatedAt time.Time     `bson:"updated_at"` // 更新時間
+}
+
+// NaturalKey 以年份與競賽名稱組成比賽的唯一識別；舊資料沒有成績來源與來源上的比賽 ID，
+// 因此不以 source 或 source_id 判斷是否為同一場比賽
+func (s *Competition) NaturalKey() string {
+	parts := []string{s.Year, s.Name}
+	for i, part := range parts {
+		parts[i] = strings.Join(strings.Fields(part), " ")
+	}
+	return strings.Join(parts, "|")
+}
+
+func (s *Competition) GetId() any {
+	if s.ID.IsZero() {
+		return nil
+	}
+	return s.ID
+}
+
+func (s *Competition) SetId(id any) {
+	oid, ok := id.(bson.ObjectID)
+	if !ok {
+		return
+	}
+	s.ID = oid
+}
+
+func (*Competition) Validate() error {
+	return nil
+}
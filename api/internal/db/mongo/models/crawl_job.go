@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const crawlJobCollectionName = "crawlJob"
+
+var crawlJobCollection = mgo.NewCollectDef(crawlJobCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "started_at", Value: -1}},
+		},
+	}
+})
+
+func init() {
+	mgo.RegisterIndex(crawlJobCollection)
+}
+
+func NewCrawlJob() *CrawlJob {
+	return &CrawlJob{
+		Index: crawlJobCollection,
+		ID:    bson.NewObjectID(),
+	}
+}
+
+// CrawlJob 是一次爬取工作的進度
+type CrawlJob struct {
+	mgo.Index    `bson:"-"`
+	ID           bson.ObjectID          `bson:"_id,omitempty"`
+	Source       string                 // 成績來源
+	Year         string                 // 年份
+	Status       string                 // 狀態
+	Error        string                 // 錯誤訊息
+	Attempts     int                    // 執行次數
+	StartedAt    time.Time              `bson:"started_at"`  // 開始時間
+	FinishedAt   time.Time              `bson:"finished_at"` // 結束時間
+	UpdatedAt    time.Time              `bson:"updated_at"`  // 最後更新時間
+	Competitions []*CrawlJobCompetition // 各比賽進度
+}
+
+type CrawlJobCompetition struct {
+	ID       string          `bson:"id"`   // 來源上的比賽 ID
+	Name     string          `bson:"name"` // 比賽名稱
+	Status   string          `bson:"status"`
+	Error    string          `bson:"error"`
+	Attempts int             `bson:"attempts"`
+	Races    []*CrawlJobRace `bson:"races"`
+}
+
+type CrawlJobRace struct {
+	CompetitionID   string `bson:"competition_id,omitempty"`
+	CompetitionName string `bson:"competition_name"`
+	RaceName        string `bson:"race_name"`
+	URL             string `bson:"url"`
+	Status          string `bson:"status"`
+	Error           string `bson:"error"`
+	Attempts        int    `bson:"attempts"`
+	// Unparsed 是最後一次解析時無法解析而略過的列
+	Unparsed []string `bson:"unparsed,omitempty"`
+}
+
+func (s *CrawlJob) GetId() any {
+	if s.ID.IsZero() {
+		return nil
+	}
+	return s.ID
+}
+
+func (s *CrawlJob) SetId(id any) {
+	oid, ok := id.(bson.ObjectID)
+	if !ok {
+		return
+	}
+	s.ID = oid
+}
+
+func (*CrawlJob) Validate() error {
+	return nil
+}
@@ -35,7 +35,8 @@ type CrawlLog struct {
 	mgo.Index `bson:"-"`
 	ID        bson.ObjectID `bson:"_id"`
 	URL       string
-	CreatedAt time.Time `bson:"createdAt"`
+	RaceID    bson.ObjectID `bson:"race_id,omitempty"` // 寫入的 race，舊的爬取紀錄沒有
+	CreatedAt time.Time     `bson:"createdAt"`
 }
 
 func (s *CrawlLog) GetId() any {
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const leaseCollectionName = "lease"
+
+var leaseCollection = mgo.NewCollectDef(leaseCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "expires_at", Value: 1}},
+		},
+	}
+})
+
+func init() {
+	mgo.RegisterIndex(leaseCollection)
+}
+
+func NewLease(name string) *Lease {
+	return &Lease{
+		Index: leaseCollection,
+		ID:    name,
+	}
+}
+
+// Lease 是排程工作的租約，同一時間只有一個執行個體可以持有
+type Lease struct {
+	mgo.Index `bson:"-"`
+	ID        string    `bson:"_id"`        // 租約名稱
+	Owner     string    `bson:"owner"`      // 持有者
+	ExpiresAt time.Time `bson:"expires_at"` // 到期時間
+}
+
+func (s *Lease) GetId() any {
+	if s.ID == "" {
+		return nil
+	}
+	return s.ID
+}
+
+func (s *Lease) SetId(id any) {
+	name, ok := id.(string)
+	if !ok {
+		return
+	}
+	s.ID = name
+}
+
+func (*Lease) Validate() error {
+	return nil
+}
@@ -1,11 +1,13 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/94peter/vulpes/db/mgo"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 const raceCollectionName = "race"
@@ -18,6 +20,23 @@ var raceCollection = mgo.NewCollectDef(raceCollectionName, func() []mongo.IndexM
 		{
 			Keys: bson.D{{Key: "year", Value: 1}},
 		},
+		{
+			Keys: bson.D{{Key: "competition_id", Value: 1}},
+		},
+		{
+			// 項目排行依項目、組別、水道與年份篩選，前綴也用於只依項目查詢
+			Keys: bson.D{
+				{Key: "stroke", Value: 1}, {Key: "distance", Value: 1}, {Key: "relay", Value: 1},
+				{Key: "gender", Value: 1}, {Key: "age_group", Value: 1}, {Key: "pool_type", Value: 1},
+				{Key: "year", Value: 1},
+			},
+		},
+		{
+			// 舊資料沒有 key，只對有 key 的文件要求唯一
+			Keys: bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"key": bson.M{"$type": "string"}}),
+		},
 	}
 })
 
@@ -35,13 +54,16 @@ func NewRace() *Race {
 type Race struct {
 	mgo.Index `bson:"-"`
 	ID        bson.ObjectID `bson:"_id,omitempty"`
+	Key       string        `bson:"key,omitempty"` // 自然鍵，見 NaturalKey
+	Source    string        // 成績來源
 	// 預賽 / 決賽
 	Type            string        // 賽事類型 (預賽/決賽)
 	Organizer       string        // 主辦單位
 	Year            string        // 年份
-	CompetitionName string        `bson:"competition_name"` // 競賽名稱
+	CompetitionName string        `bson:"competition_name"`         // 競賽名稱
+	CompetitionID   bson.ObjectID `bson:"competition_id,omitempty"` // 連結的比賽
 	Gender          string        // 性別組別
-	PoolType        string        `bson:"pool_type"`
+	PoolType        string        `bson:"pool_type"`       // 水道
 	AgeGroup        string        `bson:"age_group"`       // 年齡組別
 	EventType       string        `bson:"event_type"`      // 項目類型
 	EventName       string        `bson:"event_name"`      // 項目名稱
@@ -49,6 +71,28 @@ type Race struct {
 	NationalRecord  time.Duration `bson:"national_record"` // 全國紀錄
 	Time            time.Time     // 賽事時間
 	CreatedAt       time.Time     `bson:"created_at"` // 創建時間
+
+	RaceEvent `bson:",inline"` // 結構化項目
+}
+
+// NaturalKey 以來源、年份、競賽名稱、項目名稱與賽次組成項目的唯一識別，
+// 同一個項目重複寫入時用來覆寫而不是新增。賽次使用解析後的 Round，
+// 讓來源上不同的寫法 (例如 "決賽" 與 "決 賽") 對應到同一個項目；無法解析時使用去掉空白的 Type
+func (s *Race) NaturalKey() string {
+	return RaceKey(s.Source, s.Year, s.CompetitionName, s.EventName, s.Round, s.Type)
+}
+
+// RaceKey 組成項目的自然鍵 (見 Race.NaturalKey)，讓尚未轉成 Race 的項目 (例如匯入時分組) 使用同一個寫法；
+// 每一段的連續空白合併成一個，round 為空時使用去掉空白的 raceType
+func RaceKey(source, year, competitionName, eventName, round, raceType string) string {
+	if round == "" {
+		round = strings.Join(strings.Fields(raceType), "")
+	}
+	parts := []string{source, year, competitionName, eventName, round}
+	for i, part := range parts {
+		parts[i] = strings.Join(strings.Fields(part), " ")
+	}
+	return strings.Join(parts, "|")
 }
 
 func (s *Race) GetId() any {
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const raceChangeCollectionName = "raceChange"
+
+var raceChangeCollection = mgo.NewCollectDef(raceChangeCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "race_id", Value: 1}, {Key: "detected_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "year", Value: 1}, {Key: "competition_name", Value: 1}, {Key: "detected_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "detected_at", Value: -1}},
+		},
+	}
+})
+
+func init() {
+	mgo.RegisterIndex(raceChangeCollection)
+}
+
+func NewRaceChange() *RaceChange {
+	return &RaceChange{
+		Index: raceChangeCollection,
+		ID:    bson.NewObjectID(),
+	}
+}
+
+// RaceChange 是重新檢查時發現的成績更正
+type RaceChange struct {
+	mgo.Index       `bson:"-"`
+	ID              bson.ObjectID `bson:"_id,omitempty"`
+	RaceId          bson.ObjectID `bson:"race_id"`          // 賽事ID
+	Year            string        `bson:"year"`             // 年份
+	CompetitionName string        `bson:"competition_name"` // 競賽名稱
+	EventName       string        `bson:"event_name"`       // 項目名稱
+	URL             string        `bson:"url"`              // 成績報告連結
+	Athlete         string        `bson:"athlete"`          // 選手姓名，項目本身的欄位為空
+	Field           string        `bson:"field"`            // 變更的欄位
+	OldValue        string        `bson:"old_value"`        // 原本的值
+	NewValue        string        `bson:"new_value"`        // 更正後的值
+	DetectedAt      time.Time     `bson:"detected_at"`      // 發現時間
+}
+
+func (s *RaceChange) GetId() any {
+	if s.ID.IsZero() {
+		return nil
+	}
+	return s.ID
+}
+
+func (s *RaceChange) SetId(id any) {
+	oid, ok := id.(bson.ObjectID)
+	if !ok {
+		return
+	}
+	s.ID = oid
+}
+
+func (*RaceChange) Validate() error {
+	return nil
+}
@@ -0,0 +1,36 @@
+package models
+
+import "fmt"
+
+// RaceEvent 是由項目名稱解析出的結構化項目，內嵌在 race 中
+type RaceEvent struct {
+	Distance   int    `bson:"distance"`    // 距離 (公尺)，接力為每一棒的距離
+	Stroke     string `bson:"stroke"`      // 泳式 (freestyle/backstroke/breaststroke/butterfly/medley)
+	Relay      bool   `bson:"relay"`       // 是否為接力
+	RelayCount int    `bson:"relay_count"` // 接力棒數
+	Round      string `bson:"round"`       // 賽次 (heat/semifinal/final/timed_final/fast_heat_timed_final)
+}
+
+var strokeLabels = map[string]string{
+	"freestyle":    "自由式",
+	"backstroke":   "仰式",
+	"breaststroke": "蛙式",
+	"butterfly":    "蝶式",
+	"medley":       "混合式",
+}
+
+// Label 回傳不含組別與賽次的項目名稱，例如 "200公尺自由式"、"4×50公尺混合式接力"，
+// 尚未解析 (舊資料) 時回傳空字串
+func (e RaceEvent) Label() string {
+	stroke, ok := strokeLabels[e.Stroke]
+	if e.Distance == 0 || !ok {
+		return ""
+	}
+	if !e.Relay {
+		return fmt.Sprintf("%d公尺%s", e.Distance, stroke)
+	}
+	if e.RelayCount == 0 {
+		return fmt.Sprintf("%d公尺%s接力", e.Distance, stroke)
+	}
+	return fmt.Sprintf("%d×%d公尺%s接力", e.RelayCount, e.Distance, stroke)
+}
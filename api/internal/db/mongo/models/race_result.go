@@ -15,6 +15,19 @@ var raceResultCollection = mgo.NewCollectDef(raceResultCollectionName, func() []
 		{
 			Keys: bson.D{{Key: "name", Value: 1}},
 		},
+		{
+			Keys: bson.D{{Key: "legs.swimmer", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "athlete_ids", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "team_id", Value: 1}},
+		},
+		{
+			// 由 race 查詢成績時依成績排序
+			Keys: bson.D{{Key: "race_id", Value: 1}, {Key: "record", Value: 1}},
+		},
 	}
 })
 
@@ -39,6 +52,30 @@ type RaceResult struct {
 	Score     int32         // 分數
 	Note      string        // 備註
 	RaceId    bson.ObjectID `bson:"race_id,omitempty"` // 賽事ID
+	Team      string        `bson:"team,omitempty"`    // 接力隊伍名稱
+	Legs      []RelayLeg    `bson:"legs,omitempty"`    // 接力各棒 (依棒次排列)
+	// AthleteIDs 與 Name 順序相同的選手 ID，尚未比對選手時為空
+	AthleteIDs []bson.ObjectID `bson:"athlete_ids,omitempty"`
+	TeamID     bson.ObjectID   `bson:"team_id,omitempty"` // 單位對應的隊伍
+	// Splits 是依距離排列的累計分段，來源沒有提供時為空
+	Splits       []Split       `bson:"splits,omitempty"`
+	ReactionTime time.Duration `bson:"reaction_time,omitempty"` // 出發反應時間
+	// Status 是成績狀態 (ok/dq/dns/dnf/scratch/exhibition)，舊資料可能為空
+	Status       string `bson:"status,omitempty"`
+	StatusReason string `bson:"status_reason,omitempty"` // 狀態說明 (例如犯規原因)
+}
+
+// Split 是成績中的一個分段，Time 為出發到 Distance 公尺的累計時間
+type Split struct {
+	Distance int           `bson:"distance"` // 距離 (公尺)
+	Time     time.Duration `bson:"time"`     // 累計時間
+}
+
+// RelayLeg 是接力隊伍中的一棒
+type RelayLeg struct {
+	Swimmer string        `bson:"swimmer"`         // 選手姓名
+	Stroke  string        `bson:"stroke"`          // 泳式
+	Split   time.Duration `bson:"split,omitempty"` // 分段成績
 }
 
 func (s *RaceResult) GetId() any {
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const rawPageCollectionName = "rawPage"
+
+var rawPageCollection = mgo.NewCollectDef(rawPageCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "url", Value: 1}, {Key: "hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "year", Value: 1}, {Key: "competition_name", Value: 1}},
+		},
+		{
+			// reparse 依抓取時間由新到舊分批讀取
+			Keys: bson.D{{Key: "fetched_at", Value: -1}, {Key: "_id", Value: -1}},
+		},
+	}
+})
+
+func init() {
+	mgo.RegisterIndex(rawPageCollection)
+}
+
+func NewRawPage() *RawPage {
+	return &RawPage{
+		Index: rawPageCollection,
+		ID:    bson.NewObjectID(),
+	}
+}
+
+// RawPage 是爬蟲抓到的原始成績報告，相同 URL 與內容只會保存一份
+type RawPage struct {
+	mgo.Index       `bson:"-"`
+	ID              bson.ObjectID `bson:"_id,omitempty"`
+	Source          string        // 成績來源
+	URL             string        `bson:"url"` // 成績報告 URL
+	Year            string        // 年份
+	CompetitionID   string        `bson:"competition_id,omitempty"` // 來源網站上的比賽 ID
+	CompetitionName string        `bson:"competition_name"`         // 競賽名稱 (含年份)
+	RaceName        string        `bson:"race_name"`                // 項目名稱
+	Hash            string        // 內容 sha256
+	Body            []byte        // 原始內容
+	FetchedAt       time.Time     `bson:"fetched_at"` // 抓取時間
+}
+
+func (s *RawPage) GetId() any {
+	if s.ID.IsZero() {
+		return nil
+	}
+	return s.ID
+}
+
+func (s *RawPage) SetId(id any) {
+	oid, ok := id.(bson.ObjectID)
+	if !ok {
+		return
+	}
+	s.ID = oid
+}
+
+func (*RawPage) Validate() error {
+	return nil
+}
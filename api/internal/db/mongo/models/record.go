@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const recordCollectionName = "record"
+
+var recordCollection = mgo.NewCollectDef(recordCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "type", Value: 1}, {Key: "gender", Value: 1}, {Key: "age_group", Value: 1},
+				{Key: "stroke", Value: 1}, {Key: "distance", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{{Key: "competition_name", Value: 1}},
+		},
+	}
+})
+
+func init() {
+	mgo.RegisterIndex(recordCollection)
+}
+
+func NewRecord() *Record {
+	return &Record{
+		Index: recordCollection,
+		ID:    bson.NewObjectID(),
+	}
+}
+
+// Record 是由項目推算出的全國紀錄或大會紀錄，History 依時間由舊到新
+type Record struct {
+	mgo.Index       `bson:"-"`
+	ID              bson.ObjectID `bson:"_id,omitempty"`
+	Key             string        `bson:"key"`                        // 唯一識別，見 record.Key
+	Type            string        `bson:"type"`                       // 紀錄種類 (national/games)
+	CompetitionName string        `bson:"competition_name,omitempty"` // 大會紀錄所屬的競賽
+	Gender          string        `bson:"gender"`                     // 性別組別
+	AgeGroup        string        `bson:"age_group"`                  // 年齡組別
+	PoolType        string        `bson:"pool_type"`                  // 水道
+	EventType       string        `bson:"event_type"`                 // 項目類型，例如 "200公尺自由式"
+	Distance        int           `bson:"distance"`                   // 距離 (公尺)，接力為每一棒的距離
+	Stroke          string        `bson:"stroke"`                     // 泳式
+	Relay           bool          `bson:"relay"`                      // 是否為接力
+	RelayCount      int           `bson:"relay_count"`                // 接力棒數
+	Record          time.Duration `bson:"record"`                     // 目前的紀錄
+	Date            time.Time     `bson:"date"`                       // 目前紀錄的日期
+	History         []RecordEntry `bson:"history"`                    // 紀錄的變化
+	UpdatedAt       time.Time     `bson:"updated_at"`                 // 更新時間
+}
+
+// RecordEntry 是紀錄歷史中的一筆
+type RecordEntry struct {
+	Record          time.Duration `bson:"record"`             // 紀錄
+	Date            time.Time     `bson:"date"`               // 日期
+	Source          string        `bson:"source"`             // 來源 (report 成績報告/result 成績)
+	RaceID          bson.ObjectID `bson:"race_id"`            // 出現這個紀錄的項目
+	Year            string        `bson:"year"`               // 年份
+	CompetitionName string        `bson:"competition_name"`   // 競賽名稱
+	EventName       string        `bson:"event_name"`         // 項目名稱
+	Athletes        []string      `bson:"athletes,omitempty"` // 創下紀錄的選手，只有來源為成績時才有
+	Unit            string        `bson:"unit,omitempty"`     // 選手的單位
+}
+
+func (s *Record) GetId() any {
+	if s.ID.IsZero() {
+		return nil
+	}
+	return s.ID
+}
+
+func (s *Record) SetId(id any) {
+	oid, ok := id.(bson.ObjectID)
+	if !ok {
+		return
+	}
+	s.ID = oid
+}
+
+func (*Record) Validate() error {
+	return nil
+}
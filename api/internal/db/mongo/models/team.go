@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const teamCollectionName = "team"
+
+var teamCollection = mgo.NewCollectDef(teamCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			// 同一種寫法只能屬於一個隊伍
+			Keys:    bson.D{{Key: "keys", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "name", Value: 1}},
+		},
+	}
+})
+
+func init() {
+	mgo.RegisterIndex(teamCollection)
+}
+
+func NewTeam() *Team {
+	return &Team{
+		Index: teamCollection,
+		ID:    bson.NewObjectID(),
+	}
+}
+
+// Team 是學校或俱樂部，raceResult 以 team_id 連結到隊伍
+type Team struct {
+	mgo.Index `bson:"-"`
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	Name      string        `bson:"name"`       // 正式名稱
+	Aliases   []string      `bson:"aliases"`    // 成績上出現過的寫法
+	Keys      []string      `bson:"keys"`       // 正規化後用來比對的名稱
+	CreatedAt time.Time     `bson:"created_at"` // 創建時間
+	UpdatedAt time.Time     `bson:"updated_at"` // 更新時間
+}
+
+func (s *Team) GetId() any {
+	if s.ID.IsZero() {
+		return nil
+	}
+	return s.ID
+}
+
+func (s *Team) SetId(id any) {
+	oid, ok := id.(bson.ObjectID)
+	if !ok {
+		return
+	}
+	s.ID = oid
+}
+
+func (*Team) Validate() error {
+	return nil
+}
@@ -0,0 +1,78 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type RaceChangeStore interface {
+	FindRaceChanges(ctx context.Context, q Query, limit int64) ([]*models.RaceChange, error)
+}
+
+func newRaceChangeStore() RaceChangeStore {
+	return &raceChangeStore{}
+}
+
+type raceChangeStore struct{}
+
+// FindRaceChanges 依發現時間由新到舊回傳成績更正，limit <= 0 代表不限制筆數
+func (*raceChangeStore) FindRaceChanges(ctx context.Context, q Query, limit int64) ([]*models.RaceChange, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "detected_at", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+	changes, err := mgo.Find(ctx, models.NewRaceChange(), q.Query(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("find race changes error: %w", err)
+	}
+	return changes, nil
+}
+
+// NewRaceChangeQueryByRace 查詢單一項目的成績更正
+func NewRaceChangeQueryByRace(raceID bson.ObjectID) Query {
+	return &queryRaceChangeByRace{raceID: raceID}
+}
+
+type queryRaceChangeByRace struct {
+	raceID bson.ObjectID
+}
+
+func (q *queryRaceChangeByRace) Query() bson.M {
+	return bson.M{"race_id": q.raceID}
+}
+
+// NewRaceChangeQuery 依年份、競賽名稱、選手與發現時間篩選成績更正，零值代表不篩選
+func NewRaceChangeQuery(year, competitionName, athlete string, since time.Time) Query {
+	return &queryRaceChange{year: year, competitionName: competitionName, athlete: athlete, since: since}
+}
+
+type queryRaceChange struct {
+	year            string
+	competitionName string
+	athlete         string
+	since           time.Time
+}
+
+func (q *queryRaceChange) Query() bson.M {
+	query := bson.M{}
+	if q.year != "" {
+		query["year"] = q.year
+	}
+	if q.competitionName != "" {
+		query["competition_name"] = q.competitionName
+	}
+	if q.athlete != "" {
+		query["athlete"] = q.athlete
+	}
+	if !q.since.IsZero() {
+		query["detected_at"] = bson.M{"$gte": q.since}
+	}
+	return query
+}
@@ -2,27 +2,44 @@ package mongo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
 	"aquascore/api/internal/db/mongo/models"
 
 	"github.com/94peter/vulpes/db/mgo"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 	"go.opentelemetry.io/otel/trace/noop"
 )
 
 type RaceStore interface {
-	SaveRace(ctx context.Context, race *models.Race) (bson.ObjectID, error)
-	SaveRaceResults(ctx context.Context, results []*models.RaceResult) error
+	UpsertRace(ctx context.Context, race *models.Race) (bson.ObjectID, error)
+	ReplaceRaceResults(ctx context.Context, raceID bson.ObjectID, results []*models.RaceResult) error
+	SetRacesPoolType(ctx context.Context, q Query, poolType string, overwrite bool) (int64, error)
+	GetEventNames(ctx context.Context, year string) ([]string, error)
+	SetRacesEvent(ctx context.Context, q Query, event models.RaceEvent) (int64, error)
+	UpdateRaceKeys(ctx context.Context, dryRun bool) (int64, [][]bson.ObjectID, error)
+	GetUnsetStatusNotes(ctx context.Context) ([]string, error)
+	SetResultsStatus(ctx context.Context, note string, hasTime bool, status, reason string) (int64, error)
+	FindRaceWithResults(ctx context.Context, q Query) (*models.AggrRaceWithResult, error)
+	FindRacesWithResults(ctx context.Context, q Query) ([]*models.AggrRaceWithResult, error)
+	ApplyRaceCorrection(
+		ctx context.Context, race *models.Race, results []*models.RaceResult, changes []*models.RaceChange) error
 	GetAthleteNames(ctx context.Context) ([]string, error)
 	GetYears(ctx context.Context) ([]string, error)
 	GetCompetitions(ctx context.Context, year string, athlete string) ([]string, error)
 	GetAthleteRaces(
-		ctx context.Context, athleteName, competitionName, year string) ([]*models.AggrAthleteJoinRacesFilterByRace, error)
-	GetAllAthleteRaces(ctx context.Context, athleteName string) ([]*models.AggrAthleteJoinRacesFilterByAthlete, error)
+		ctx context.Context, athlete AthleteFilter, competitionName, year string,
+	) ([]*models.AggrAthleteJoinRacesFilterByRace, error)
+	GetAllAthleteRaces(ctx context.Context, athlete AthleteFilter) ([]*models.AggrAthleteJoinRacesFilterByAthlete, error)
+	GetAthleteRelays(ctx context.Context, athlete AthleteFilter) ([]*models.AggrAthleteRelay, error)
 	GetRaceWithResultsByID(ctx context.Context, raceID string) (*models.AggrRaceWithResult, error)
+	GetRankings(ctx context.Context, filter RankingFilter, offset, limit int) ([]*models.AggrRanking, error)
 }
 
 func newRaceStore(tracer trace.Tracer) RaceStore {
@@ -101,11 +118,11 @@ func (rs *raceStore) GetCompetitions(ctx context.Context, year string, athlete s
 }
 
 func (rs *raceStore) GetAthleteRaces(
-	ctx context.Context, athleteName, competitionName, year string,
+	ctx context.Context, athlete AthleteFilter, competitionName, year string,
 ) ([]*models.AggrAthleteJoinRacesFilterByRace, error) {
 	ctx, span := rs.startTracer(ctx, "RaceStore.GetAthleteRaces")
 	defer span.End()
-	aggr := models.NewAggrAthleteJoinRacesFilterByRace(athleteName)
+	aggr := models.NewAggrAthleteJoinRacesFilterByRace(athlete.resultQuery("results."))
 	result, err := mgo.PipeFind(ctx, aggr, bson.M{"competition_name": competitionName, "year": year})
 	if err := spanErrorHandler(err, span); err != nil {
 		return nil, err
@@ -114,13 +131,11 @@ func (rs *raceStore) GetAthleteRaces(
 }
 
 func (rs *raceStore) GetAllAthleteRaces(
-	ctx context.Context, athleteName string,
+	ctx context.Context, athlete AthleteFilter,
 ) ([]*models.AggrAthleteJoinRacesFilterByAthlete, error) {
 	ctx, span := rs.startTracer(ctx, "RaceStore.GetAllAthleteRaces")
 	defer span.End()
-	query := bson.M{
-		"name": bson.M{"$in": []string{athleteName}},
-	}
+	query := athlete.resultQuery("")
 	aggr := models.NewAggrAthleteJoinRacesFilterByAthlete()
 	result, err := mgo.PipeFind(ctx, aggr, query)
 	if err := spanErrorHandler(err, span); err != nil {
@@ -129,6 +144,36 @@ func (rs *raceStore) GetAllAthleteRaces(
 	return result, spanErrorHandler(nil, span)
 }
 
+// GetAthleteRelays 回傳選手游過其中一棒的所有接力成績，依比賽時間由新到舊排序
+func (rs *raceStore) GetAthleteRelays(ctx context.Context, athlete AthleteFilter) ([]*models.AggrAthleteRelay, error) {
+	ctx, span := rs.startTracer(ctx, "RaceStore.GetAthleteRelays")
+	defer span.End()
+	query := bson.M{"legs.swimmer": athlete.Name}
+	if !athlete.ID.IsZero() {
+		query = bson.M{"athlete_ids": athlete.ID, "legs.0": bson.M{"$exists": true}}
+	}
+	aggr := models.NewAggrAthleteRelay()
+	result, err := mgo.PipeFind(ctx, aggr, query)
+	if err := spanErrorHandler(err, span); err != nil {
+		return nil, err
+	}
+	return result, spanErrorHandler(nil, span)
+}
+
+// GetRankings 回傳項目中每位選手的最佳成績排行，犯規、未出賽、未完賽、退賽與沒有成績的不列入
+func (rs *raceStore) GetRankings(
+	ctx context.Context, filter RankingFilter, offset, limit int,
+) ([]*models.AggrRanking, error) {
+	ctx, span := rs.startTracer(ctx, "RaceStore.GetRankings")
+	defer span.End()
+	aggr := models.NewAggrRanking(filter.TeamID, offset, limit)
+	result, err := mgo.PipeFind(ctx, aggr, filter.raceQuery())
+	if err := spanErrorHandler(err, span); err != nil {
+		return nil, err
+	}
+	return result, spanErrorHandler(nil, span)
+}
+
 func (rs *raceStore) GetRaceWithResultsByID(ctx context.Context, raceID string) (*models.AggrRaceWithResult, error) {
 	ctx, span := rs.startTracer(ctx, "RaceStore.GetRaceWithResultsByID")
 	defer span.End()
@@ -144,33 +189,441 @@ func (rs *raceStore) GetRaceWithResultsByID(ctx context.Context, raceID string)
 	return raceResult, spanErrorHandler(nil, span)
 }
 
-func (rs *raceStore) SaveRace(ctx context.Context, race *models.Race) (bson.ObjectID, error) {
-	ctx, span := rs.startTracer(ctx, "SaveRace to mongo")
+// UpsertRace 以 race 的自然鍵新增或覆寫項目，回傳資料庫中項目的 ID
+func (rs *raceStore) UpsertRace(ctx context.Context, race *models.Race) (bson.ObjectID, error) {
+	ctx, span := rs.startTracer(ctx, "UpsertRace to mongo")
 	defer span.End()
-	r, err := mgo.Save(ctx, race)
+	race.Key = race.NaturalKey()
+	fields, err := toSetFields(race, "_id", "created_at")
+	if err != nil {
+		return bson.NilObjectID, spanErrorHandler(err, span)
+	}
+	update := bson.M{
+		"$set":         fields,
+		"$setOnInsert": bson.M{"_id": race.ID, "created_at": race.CreatedAt},
+	}
+	_, err = mgo.UpdateOne(ctx, race, bson.M{"key": race.Key}, update, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return bson.NilObjectID, spanErrorHandler(fmt.Errorf("failed to upsert race: %w", err), span)
+	}
+	stored := models.NewRace()
+	err = mgo.FindOne(ctx, stored, bson.M{"key": race.Key})
 	if err != nil {
-		return bson.NilObjectID, spanErrorHandler(fmt.Errorf("failed to save race: %w", err), span)
+		return bson.NilObjectID, spanErrorHandler(fmt.Errorf("failed to find upserted race: %w", err), span)
 	}
-	return r.ID, spanErrorHandler(nil, span)
+	return stored.ID, spanErrorHandler(nil, span)
 }
 
-func (rs *raceStore) SaveRaceResults(ctx context.Context, results []*models.RaceResult) error {
-	ctx, span := rs.startTracer(ctx, "SaveRaceResults to mongo")
+// ReplaceRaceResults 以 results 取代項目原本所有的 raceResult，並更新原本與新成績選手的出賽次數
+func (rs *raceStore) ReplaceRaceResults(
+	ctx context.Context, raceID bson.ObjectID, results []*models.RaceResult,
+) error {
+	ctx, span := rs.startTracer(ctx, "ReplaceRaceResults to mongo")
 	defer span.End()
+	return spanErrorHandler(replaceRaceResults(ctx, raceID, results), span)
+}
+
+func replaceRaceResults(ctx context.Context, raceID bson.ObjectID, results []*models.RaceResult) error {
+	athleteIDs, err := mgo.Distinct[bson.ObjectID](ctx, models.NewRaceResult().C(), "athlete_ids",
+		bson.M{"race_id": raceID})
+	if err != nil {
+		return fmt.Errorf("failed to find race athletes: %w", err)
+	}
+	_, err = mgo.DeleteMany(ctx, models.NewRaceResult(), bson.M{"race_id": raceID})
+	if err != nil {
+		return fmt.Errorf("failed to delete race results: %w", err)
+	}
+	if err := insertRaceResults(ctx, results); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if r != nil {
+			athleteIDs = append(athleteIDs, r.AthleteIDs...)
+		}
+	}
+	return updateRaceCounts(ctx, athleteIDs)
+}
+
+// toSetFields 將文件轉成 $set 用的欄位，並排除 omit 中的欄位
+func toSetFields(doc any, omit ...string) (bson.M, error) {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal document: %w", err)
+	}
+	var fields bson.M
+	if err := bson.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal document: %w", err)
+	}
+	for _, field := range omit {
+		delete(fields, field)
+	}
+	return fields, nil
+}
+
+func insertRaceResults(ctx context.Context, results []*models.RaceResult) error {
 	raceResult := models.NewRaceResult()
 	bulk, err := mgo.NewBulkOperation(raceResult.C())
 	if err != nil {
-		return spanErrorHandler(fmt.Errorf("failed to create bulk operation: %w", err), span)
+		return fmt.Errorf("failed to create bulk operation: %w", err)
 	}
+	inserted := 0
 	for _, r := range results {
 		if r == nil {
 			continue
 		}
 		bulk = bulk.InsertOne(r)
+		inserted++
+	}
+	if inserted == 0 {
+		return nil
 	}
 	_, err = bulk.Execute(ctx)
 	if err != nil {
-		return spanErrorHandler(fmt.Errorf("failed to execute bulk operation: %w", err), span)
+		return fmt.Errorf("failed to execute bulk operation: %w", err)
 	}
-	return spanErrorHandler(nil, span)
+	return nil
+}
+
+// FindRaceWithResults 回傳第一個符合條件的 race 與其成績，找不到時回傳 nil
+func (rs *raceStore) FindRaceWithResults(ctx context.Context, q Query) (*models.AggrRaceWithResult, error) {
+	ctx, span := rs.startTracer(ctx, "RaceStore.FindRaceWithResults")
+	defer span.End()
+	race := models.NewAggrRaceWithResult()
+	err := mgo.PipeFindOne(ctx, race, q.Query())
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, spanErrorHandler(nil, span)
+		}
+		return nil, spanErrorHandler(fmt.Errorf("failed to find race: %w", err), span)
+	}
+	return race, spanErrorHandler(nil, span)
+}
+
+// FindRacesWithResults 回傳符合條件的所有 race 以及其成績
+func (rs *raceStore) FindRacesWithResults(ctx context.Context, q Query) ([]*models.AggrRaceWithResult, error) {
+	ctx, span := rs.startTracer(ctx, "RaceStore.FindRacesWithResults")
+	defer span.End()
+	races, err := mgo.PipeFind(ctx, models.NewAggrRaceWithResult(), q.Query())
+	if err != nil {
+		return nil, spanErrorHandler(fmt.Errorf("failed to find races: %w", err), span)
+	}
+	return races, spanErrorHandler(nil, span)
+}
+
+// ApplyRaceCorrection 在同一個交易中更新 race、以新的成績取代原本的 raceResult 並寫入變更歷史
+func (rs *raceStore) ApplyRaceCorrection(
+	ctx context.Context, race *models.Race, results []*models.RaceResult, changes []*models.RaceChange,
+) error {
+	ctx, span := rs.startTracer(ctx, "ApplyRaceCorrection to mongo")
+	defer span.End()
+	race.Key = race.NaturalKey()
+	err := RunInTransaction(ctx, func(ctx context.Context) error {
+		_, err := mgo.UpdateOne(ctx, race, bson.M{"_id": race.ID}, bson.M{"$set": race})
+		if err != nil {
+			return fmt.Errorf("failed to update race: %w", err)
+		}
+		if err := replaceRaceResults(ctx, race.ID, results); err != nil {
+			return err
+		}
+		for _, change := range changes {
+			if _, err := mgo.Save(ctx, change); err != nil {
+				return fmt.Errorf("failed to save race change: %w", err)
+			}
+		}
+		return nil
+	})
+	return spanErrorHandler(err, span)
+}
+
+// SetRacesPoolType 設定符合條件的 race 的水道，overwrite 為 false 時只更新尚未設定的 race
+func (rs *raceStore) SetRacesPoolType(ctx context.Context, q Query, poolType string, overwrite bool) (int64, error) {
+	ctx, span := rs.startTracer(ctx, "SetRacesPoolType to mongo")
+	defer span.End()
+	filter := q.Query()
+	if !overwrite {
+		filter["pool_type"] = bson.M{"$in": bson.A{"", nil}}
+	}
+	updated, err := mgo.UpdateMany(ctx, models.NewRace(), filter, bson.M{"$set": bson.M{"pool_type": poolType}})
+	if err != nil {
+		return 0, spanErrorHandler(fmt.Errorf("failed to update pool type: %w", err), span)
+	}
+	return updated, spanErrorHandler(nil, span)
+}
+
+// GetEventNames 回傳所有不重複的項目名稱，year 為空代表所有年份
+func (rs *raceStore) GetEventNames(ctx context.Context, year string) ([]string, error) {
+	ctx, span := rs.startTracer(ctx, "RaceStore.GetEventNames")
+	defer span.End()
+	filter := bson.M{}
+	if year != "" {
+		filter["year"] = year
+	}
+	result, err := mgo.Distinct[string](ctx, models.NewRace().C(), "event_name", filter)
+	if err := spanErrorHandler(err, span); err != nil {
+		return nil, err
+	}
+	return result, spanErrorHandler(nil, span)
+}
+
+// SetRacesEvent 設定符合條件的 race 的結構化項目
+func (rs *raceStore) SetRacesEvent(ctx context.Context, q Query, event models.RaceEvent) (int64, error) {
+	ctx, span := rs.startTracer(ctx, "SetRacesEvent to mongo")
+	defer span.End()
+	fields, err := toSetFields(event)
+	if err != nil {
+		return 0, spanErrorHandler(err, span)
+	}
+	updated, err := mgo.UpdateMany(ctx, models.NewRace(), q.Query(), bson.M{"$set": fields})
+	if err != nil {
+		return 0, spanErrorHandler(fmt.Errorf("failed to update race event: %w", err), span)
+	}
+	return updated, spanErrorHandler(nil, span)
+}
+
+// UpdateRaceKeys 以目前的 NaturalKey 重新計算所有 race 的 key，回傳更新的數量，
+// 以及重新計算後 key 相同的 race (同一個項目重複寫入)。重複的 race 中由原本持有該 key 的 race
+// (或最新寫入的 race) 取得 key，其他 race 保留原本的 key，需要人工確認後刪除
+func (rs *raceStore) UpdateRaceKeys(ctx context.Context, dryRun bool) (int64, [][]bson.ObjectID, error) {
+	ctx, span := rs.startTracer(ctx, "UpdateRaceKeys to mongo")
+	defer span.End()
+	races, err := mgo.Find(ctx, models.NewRace(), bson.M{},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return 0, nil, spanErrorHandler(fmt.Errorf("failed to find races: %w", err), span)
+	}
+	byKey := make(map[string][]*models.Race)
+	var keys []string
+	for _, race := range races {
+		key := race.NaturalKey()
+		if _, ok := byKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		byKey[key] = append(byKey[key], race)
+	}
+	var duplicates [][]bson.ObjectID
+	changed := make(map[bson.ObjectID]string)
+	for _, key := range keys {
+		group := byKey[key]
+		owner := group[0]
+		for _, race := range group {
+			if race.Key == key {
+				owner = race
+			}
+		}
+		if len(group) > 1 {
+			ids := []bson.ObjectID{owner.ID}
+			for _, race := range group {
+				if race != owner {
+					ids = append(ids, race.ID)
+				}
+			}
+			duplicates = append(duplicates, ids)
+		}
+		if owner.Key != key {
+			changed[owner.ID] = key
+		}
+	}
+	if dryRun || len(changed) == 0 {
+		return int64(len(changed)), duplicates, spanErrorHandler(nil, span)
+	}
+	// 先移除要變更的 key 再寫入，避免新的 key 與尚未更新的 race 衝突
+	ids := make([]bson.ObjectID, 0, len(changed))
+	for id := range changed {
+		ids = append(ids, id)
+	}
+	_, err = mgo.UpdateMany(ctx, models.NewRace(),
+		bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$unset": bson.M{"key": ""}})
+	if err != nil {
+		return 0, nil, spanErrorHandler(fmt.Errorf("failed to unset race keys: %w", err), span)
+	}
+	var updated int64
+	for id, key := range changed {
+		n, err := mgo.UpdateOne(ctx, models.NewRace(), bson.M{"_id": id}, bson.M{"$set": bson.M{"key": key}})
+		if err != nil {
+			return updated, nil, spanErrorHandler(fmt.Errorf("failed to set race key: %w", err), span)
+		}
+		updated += n
+	}
+	return updated, duplicates, spanErrorHandler(nil, span)
+}
+
+// GetUnsetStatusNotes 回傳尚未記錄成績狀態的成績上出現過的所有備註
+func (rs *raceStore) GetUnsetStatusNotes(ctx context.Context) ([]string, error) {
+	ctx, span := rs.startTracer(ctx, "RaceStore.GetUnsetStatusNotes")
+	defer span.End()
+	notes, err := mgo.Distinct[string](ctx, models.NewRaceResult().C(), "note",
+		bson.M{"status": bson.M{"$exists": false}})
+	if err != nil {
+		return nil, spanErrorHandler(fmt.Errorf("failed to get result notes: %w", err), span)
+	}
+	return notes, spanErrorHandler(nil, span)
+}
+
+// SetResultsStatus 設定備註為 note 且尚未記錄狀態的成績的狀態，hasTime 區分有成績與沒有成績的紀錄
+func (rs *raceStore) SetResultsStatus(
+	ctx context.Context, note string, hasTime bool, status, reason string,
+) (int64, error) {
+	ctx, span := rs.startTracer(ctx, "SetResultsStatus to mongo")
+	defer span.End()
+	filter := bson.M{
+		"status": bson.M{"$exists": false},
+		"note":   note,
+		"record": bson.M{"$lte": 0},
+	}
+	if hasTime {
+		filter["record"] = bson.M{"$gt": 0}
+	}
+	fields := bson.M{"status": status}
+	if reason != "" {
+		fields["status_reason"] = reason
+	}
+	updated, err := mgo.UpdateMany(ctx, models.NewRaceResult(), filter, bson.M{"$set": fields})
+	if err != nil {
+		return 0, spanErrorHandler(fmt.Errorf("failed to update result status: %w", err), span)
+	}
+	return updated, spanErrorHandler(nil, span)
+}
+
+// AthleteFilter 指定要查詢成績的選手，ID 不為零值時以 athlete ID 比對，否則以姓名比對
+type AthleteFilter struct {
+	ID   bson.ObjectID
+	Name string
+}
+
+// resultQuery 回傳比對 raceResult 的條件，prefix 為 raceResult 欄位在 pipeline 中的前綴
+func (f AthleteFilter) resultQuery(prefix string) bson.M {
+	if !f.ID.IsZero() {
+		return bson.M{prefix + "athlete_ids": f.ID}
+	}
+	return bson.M{prefix + "name": f.Name}
+}
+
+// MatchResult 表示選手姓名為 names、選手 ID 為 athleteIDs 的成績是否屬於此選手
+func (f AthleteFilter) MatchResult(names []string, athleteIDs []bson.ObjectID) bool {
+	if !f.ID.IsZero() {
+		return slices.Contains(athleteIDs, f.ID)
+	}
+	return slices.Contains(names, f.Name)
+}
+
+// RankingFilter 是項目排行的條件，Event 只使用距離與泳式，其他欄位零值代表不篩選
+type RankingFilter struct {
+	Event    models.RaceEvent
+	Gender   string
+	AgeGroup string
+	Year     string
+	PoolType string
+	TeamID   bson.ObjectID
+}
+
+func (f RankingFilter) raceQuery() bson.M {
+	query := bson.M{"stroke": f.Event.Stroke, "distance": f.Event.Distance, "relay": false}
+	fields := map[string]string{
+		"gender":    f.Gender,
+		"age_group": f.AgeGroup,
+		"pool_type": f.PoolType,
+		"year":      f.Year,
+	}
+	for field, value := range fields {
+		if value != "" {
+			query[field] = value
+		}
+	}
+	return query
+}
+
+// NewRaceQueryByKey 以自然鍵找出 race，見 models.Race.NaturalKey
+func NewRaceQueryByKey(key string) Query {
+	return &queryRaceByKey{key: key}
+}
+
+type queryRaceByKey struct {
+	key string
+}
+
+func (q *queryRaceByKey) Query() bson.M {
+	return bson.M{"key": q.key}
+}
+
+// NewRaceQueryByEventName 以項目名稱找出 race，year 為空代表所有年份
+func NewRaceQueryByEventName(year, eventName string) Query {
+	return &queryRaceByEventName{year: year, eventName: eventName}
+}
+
+type queryRaceByEventName struct {
+	year      string
+	eventName string
+}
+
+func (q *queryRaceByEventName) Query() bson.M {
+	query := bson.M{"event_name": q.eventName}
+	if q.year != "" {
+		query["year"] = q.year
+	}
+	return query
+}
+
+// NewRaceQueryByYear 找出同一年份的所有 race
+func NewRaceQueryByYear(year string) Query {
+	return &queryRaceByYear{year: year}
+}
+
+type queryRaceByYear struct {
+	year string
+}
+
+func (q *queryRaceByYear) Query() bson.M {
+	return bson.M{"year": q.year}
+}
+
+// NewRaceQueryByCompetition 以年份與競賽名稱找出同一場比賽的所有 race
+func NewRaceQueryByCompetition(year, competitionName string) Query {
+	return &queryRaceByCompetition{year: year, competitionName: competitionName}
+}
+
+type queryRaceByCompetition struct {
+	year            string
+	competitionName string
+}
+
+func (q *queryRaceByCompetition) Query() bson.M {
+	return bson.M{
+		"year":             q.year,
+		"competition_name": q.competitionName,
+	}
+}
+
+// NewRaceQueryBySwimEvent 找出同一性別、年齡組別、水道與項目 (不分賽次) 的所有 race
+func NewRaceQueryBySwimEvent(gender, ageGroup, poolType string, event models.RaceEvent) Query {
+	return &queryRaceBySwimEvent{gender: gender, ageGroup: ageGroup, poolType: poolType, event: event}
+}
+
+type queryRaceBySwimEvent struct {
+	gender   string
+	ageGroup string
+	poolType string
+	event    models.RaceEvent
+}
+
+func (q *queryRaceBySwimEvent) Query() bson.M {
+	return bson.M{
+		"gender":      q.gender,
+		"age_group":   q.ageGroup,
+		"pool_type":   q.poolType,
+		"distance":    q.event.Distance,
+		"stroke":      q.event.Stroke,
+		"relay":       q.event.Relay,
+		"relay_count": q.event.RelayCount,
+	}
+}
+
+// NewRaceQueryWithEvent 找出項目名稱已解析成結構化項目的所有 race
+func NewRaceQueryWithEvent() Query {
+	return &queryRaceWithEvent{}
+}
+
+type queryRaceWithEvent struct{}
+
+func (*queryRaceWithEvent) Query() bson.M {
+	return bson.M{"distance": bson.M{"$gt": 0}}
 }
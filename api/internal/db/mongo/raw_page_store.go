@@ -0,0 +1,80 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type RawPageStore interface {
+	SaveRawPage(ctx context.Context, page *models.RawPage) error
+	FindRawPages(ctx context.Context, q Query, after *models.RawPage, limit int) ([]*models.RawPage, error)
+}
+
+func newRawPageStore() RawPageStore {
+	return &rawPageStore{}
+}
+
+type rawPageStore struct{}
+
+func (*rawPageStore) SaveRawPage(ctx context.Context, page *models.RawPage) error {
+	_, err := mgo.Save(ctx, page)
+	if err != nil {
+		// 相同 URL 與內容已經封存過
+		if mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return fmt.Errorf("save raw page error: %w", err)
+	}
+	return nil
+}
+
+// FindRawPages 依抓取時間由新到舊回傳符合條件的原始成績報告，最多 limit 份。
+// after 為上一批的最後一份，接著回傳比它舊的報告；nil 代表從最新的開始
+func (*rawPageStore) FindRawPages(
+	ctx context.Context, q Query, after *models.RawPage, limit int,
+) ([]*models.RawPage, error) {
+	filter := q.Query()
+	if after != nil {
+		filter["$or"] = bson.A{
+			bson.M{"fetched_at": bson.M{"$lt": after.FetchedAt}},
+			bson.M{"fetched_at": after.FetchedAt, "_id": bson.M{"$lt": after.ID}},
+		}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "fetched_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+	pages, err := mgo.Find(ctx, models.NewRawPage(), filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find raw pages error: %w", err)
+	}
+	return pages, nil
+}
+
+// NewRawPageQuery 依年份與競賽名稱 (部分比對) 篩選原始成績報告，空字串代表不篩選
+func NewRawPageQuery(year, competitionName string) Query {
+	return &queryRawPage{year: year, competitionName: competitionName}
+}
+
+type queryRawPage struct {
+	year            string
+	competitionName string
+}
+
+func (q *queryRawPage) Query() bson.M {
+	query := bson.M{}
+	if q.year != "" {
+		query["year"] = q.year
+	}
+	if q.competitionName != "" {
+		query["competition_name"] = bson.M{"$regex": regexp.QuoteMeta(q.competitionName)}
+	}
+	return query
+}
@@ -0,0 +1,119 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type RecordStore interface {
+	SaveRecords(ctx context.Context, records []*models.Record) error
+	DeleteRecordsExcept(ctx context.Context, keys []string) (int64, error)
+	FindRecords(ctx context.Context, q Query) ([]*models.Record, error)
+	FindRecord(ctx context.Context, id bson.ObjectID) (*models.Record, error)
+}
+
+func newRecordStore() RecordStore {
+	return &recordStore{}
+}
+
+type recordStore struct{}
+
+// SaveRecords 以 key 新增或覆寫紀錄，已存在的紀錄保留原本的 ID
+func (*recordStore) SaveRecords(ctx context.Context, records []*models.Record) error {
+	for _, record := range records {
+		fields, err := toSetFields(record, "_id")
+		if err != nil {
+			return err
+		}
+		update := bson.M{"$set": fields, "$setOnInsert": bson.M{"_id": record.ID}}
+		_, err = mgo.UpdateOne(ctx, record, bson.M{"key": record.Key}, update, options.UpdateOne().SetUpsert(true))
+		if err != nil {
+			return fmt.Errorf("save record error: %w", err)
+		}
+	}
+	return nil
+}
+
+// DeleteRecordsExcept 刪除 key 不在 keys 中的紀錄，用於重新推算所有紀錄後移除已不存在的紀錄
+func (*recordStore) DeleteRecordsExcept(ctx context.Context, keys []string) (int64, error) {
+	deleted, err := mgo.DeleteMany(ctx, models.NewRecord(), bson.M{"key": bson.M{"$nin": keys}})
+	if err != nil {
+		return 0, fmt.Errorf("delete records error: %w", err)
+	}
+	return deleted, nil
+}
+
+// FindRecords 依紀錄種類、競賽、組別與項目排序回傳符合條件的紀錄
+func (*recordStore) FindRecords(ctx context.Context, q Query) ([]*models.Record, error) {
+	opts := options.Find().SetSort(bson.D{
+		{Key: "type", Value: 1}, {Key: "competition_name", Value: 1}, {Key: "gender", Value: 1},
+		{Key: "age_group", Value: 1}, {Key: "relay", Value: 1}, {Key: "stroke", Value: 1}, {Key: "distance", Value: 1},
+	})
+	records, err := mgo.Find(ctx, models.NewRecord(), q.Query(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("find records error: %w", err)
+	}
+	return records, nil
+}
+
+// FindRecord 以 ID 找出紀錄，找不到時回傳 nil
+func (*recordStore) FindRecord(ctx context.Context, id bson.ObjectID) (*models.Record, error) {
+	record := models.NewRecord()
+	err := mgo.FindOne(ctx, record, bson.M{"_id": id})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find record error: %w", err)
+	}
+	return record, nil
+}
+
+// RecordFilter 是查詢紀錄的條件，零值代表不篩選
+type RecordFilter struct {
+	Type            string
+	CompetitionName string
+	Gender          string
+	AgeGroup        string
+	PoolType        string
+	Stroke          string
+	Distance        int
+}
+
+// NewRecordQuery 依 filter 篩選紀錄
+func NewRecordQuery(filter RecordFilter) Query {
+	return &queryRecord{filter: filter}
+}
+
+type queryRecord struct {
+	filter RecordFilter
+}
+
+func (q *queryRecord) Query() bson.M {
+	query := bson.M{}
+	fields := map[string]string{
+		"type":             q.filter.Type,
+		"competition_name": q.filter.CompetitionName,
+		"gender":           q.filter.Gender,
+		"age_group":        q.filter.AgeGroup,
+		"pool_type":        q.filter.PoolType,
+		"stroke":           q.filter.Stroke,
+	}
+	for field, value := range fields {
+		if value != "" {
+			query[field] = value
+		}
+	}
+	if q.filter.Distance > 0 {
+		query["distance"] = q.filter.Distance
+	}
+	return query
+}
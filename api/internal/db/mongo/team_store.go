@@ -0,0 +1,125 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type TeamStore interface {
+	FindTeams(ctx context.Context) ([]*models.Team, error)
+	FindTeam(ctx context.Context, id bson.ObjectID) (*models.Team, error)
+	FindTeamByKey(ctx context.Context, key string) (*models.Team, error)
+	SaveTeam(ctx context.Context, team *models.Team) error
+	GetUnits(ctx context.Context) ([]string, error)
+	SetResultsTeam(ctx context.Context, unit string, teamID bson.ObjectID) (int64, error)
+	GetTeamAthletes(ctx context.Context, teamID bson.ObjectID) ([]*models.AggrTeamAthlete, error)
+	GetTeamResults(ctx context.Context, teamID bson.ObjectID, year string) ([]*models.AggrTeamResult, error)
+	GetTeamStandings(ctx context.Context, year, competitionName string) ([]*models.AggrTeamStanding, error)
+}
+
+func newTeamStore() TeamStore {
+	return &teamStore{}
+}
+
+type teamStore struct{}
+
+// FindTeams 依名稱排序回傳所有隊伍
+func (*teamStore) FindTeams(ctx context.Context) ([]*models.Team, error) {
+	teams, err := mgo.Find(ctx, models.NewTeam(), bson.M{},
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("find teams error: %w", err)
+	}
+	return teams, nil
+}
+
+// FindTeam 以 ID 找出隊伍，找不到時回傳 nil
+func (*teamStore) FindTeam(ctx context.Context, id bson.ObjectID) (*models.Team, error) {
+	return findTeam(ctx, bson.M{"_id": id})
+}
+
+// FindTeamByKey 以正規化後的名稱找出隊伍，找不到時回傳 nil
+func (*teamStore) FindTeamByKey(ctx context.Context, key string) (*models.Team, error) {
+	return findTeam(ctx, bson.M{"keys": key})
+}
+
+func findTeam(ctx context.Context, filter bson.M) (*models.Team, error) {
+	team := models.NewTeam()
+	err := mgo.FindOne(ctx, team, filter)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find team error: %w", err)
+	}
+	return team, nil
+}
+
+// SaveTeam 以 _id 覆寫整份隊伍資料，不存在時新增
+func (*teamStore) SaveTeam(ctx context.Context, team *models.Team) error {
+	_, err := mgo.UpdateOne(ctx, team, bson.M{"_id": team.ID}, bson.M{"$set": team},
+		options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("save team error: %w", err)
+	}
+	return nil
+}
+
+// GetUnits 回傳成績上出現過的所有單位名稱
+func (*teamStore) GetUnits(ctx context.Context) ([]string, error) {
+	units, err := mgo.Distinct[string](ctx, models.NewRaceResult().C(), "unit", bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("get units error: %w", err)
+	}
+	return units, nil
+}
+
+// SetResultsTeam 將單位為 unit 的成績連結到隊伍
+func (*teamStore) SetResultsTeam(ctx context.Context, unit string, teamID bson.ObjectID) (int64, error) {
+	updated, err := mgo.UpdateMany(ctx, models.NewRaceResult(), bson.M{"unit": unit},
+		bson.M{"$set": bson.M{"team_id": teamID}})
+	if err != nil {
+		return 0, fmt.Errorf("set results team error: %w", err)
+	}
+	return updated, nil
+}
+
+// GetTeamAthletes 回傳代表隊伍出賽過的選手
+func (*teamStore) GetTeamAthletes(ctx context.Context, teamID bson.ObjectID) ([]*models.AggrTeamAthlete, error) {
+	athletes, err := mgo.PipeFind(ctx, models.NewAggrTeamAthlete(), bson.M{"team_id": teamID})
+	if err != nil {
+		return nil, fmt.Errorf("get team athletes error: %w", err)
+	}
+	return athletes, nil
+}
+
+// GetTeamResults 依比賽時間由新到舊回傳隊伍的成績，year 為空代表所有年份
+func (*teamStore) GetTeamResults(
+	ctx context.Context, teamID bson.ObjectID, year string,
+) ([]*models.AggrTeamResult, error) {
+	results, err := mgo.PipeFind(ctx, models.NewAggrTeamResult(year), bson.M{"team_id": teamID})
+	if err != nil {
+		return nil, fmt.Errorf("get team results error: %w", err)
+	}
+	return results, nil
+}
+
+// GetTeamStandings 回傳一場比賽各隊伍的獎牌數與積分，依積分與金銀銅牌數排序
+func (*teamStore) GetTeamStandings(
+	ctx context.Context, year, competitionName string,
+) ([]*models.AggrTeamStanding, error) {
+	standings, err := mgo.PipeFind(ctx, models.NewAggrTeamStanding(),
+		bson.M{"year": year, "competition_name": competitionName})
+	if err != nil {
+		return nil, fmt.Errorf("get team standings error: %w", err)
+	}
+	return standings, nil
+}
@@ -0,0 +1,44 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync/atomic"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// illegalOperationCode 是單機 MongoDB 不支援交易時回傳的錯誤碼
+const illegalOperationCode = 20
+
+var transactionUnsupported atomic.Bool
+
+// RunInTransaction 在交易中執行 fn；部署不支援交易 (單機 MongoDB) 時改為直接執行，
+// 因此 fn 內的寫入必須可以重複執行。ctx 已在交易中時直接加入外層的交易
+func RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if transactionUnsupported.Load() || mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	err := mgo.WithTransaction(ctx, fn)
+	if isTransactionUnsupported(err) {
+		if transactionUnsupported.CompareAndSwap(false, true) {
+			log.Printf("⚠️ MongoDB 不支援交易 (需要 replica set)，改為依序寫入: %v", err)
+		}
+		return fn(ctx)
+	}
+	return err
+}
+
+func isTransactionUnsupported(err error) bool {
+	if err == nil {
+		return false
+	}
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == illegalOperationCode {
+		return true
+	}
+	return strings.Contains(err.Error(), "Transaction numbers are only allowed")
+}
@@ -0,0 +1,153 @@
+package headtohead
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Winner 是兩位選手在同一場比賽中的勝負
+type Winner string
+
+const (
+	WinnerA    Winner = "a"    // 選手 A 較快，或只有 A 有有效成績
+	WinnerB    Winner = "b"    // 選手 B 較快，或只有 B 有有效成績
+	WinnerTie  Winner = "tie"  // 成績相同
+	WinnerNone Winner = "none" // 兩位都沒有有效成績 (犯規、棄權等)
+)
+
+// Swim 是選手的一次個人項目成績
+type Swim struct {
+	RaceID          string
+	Event           string // 比較用的項目名稱，同一項目與水道的成績必須相同，例如 "100公尺仰式(50m)"
+	Date            time.Time
+	CompetitionName string
+	EventName       string
+	Record          time.Duration
+	Rank            int
+	Valid           bool // 成績是否可以比較，犯規、未出賽等沒有有效時間的成績為 false
+}
+
+// Meeting 是兩位選手都有出賽的一場比賽
+type Meeting struct {
+	A, B   Swim
+	Winner Winner
+	// Gap 是 A 減 B 的時間差，負值代表 A 較快，只有兩位都有有效成績時才有
+	Gap *time.Duration
+}
+
+// EventSummary 是兩位選手在一個都游過的項目中的對戰紀錄與最佳成績比較
+type EventSummary struct {
+	Event    string
+	Meetings int
+	WinsA    int
+	WinsB    int
+	Ties     int
+	// AverageGap 是兩位都有有效成績的比賽中 A 減 B 的平均時間差，沒有這樣的比賽時為 nil
+	AverageGap *time.Duration
+	BestA      time.Duration // A 在項目中的最佳成績，沒有有效成績時為 0
+	BestB      time.Duration // B 在項目中的最佳成績，沒有有效成績時為 0
+}
+
+// Result 是兩位選手的比較，Meetings 依日期由舊到新，Events 依項目名稱排序
+type Result struct {
+	Meetings []Meeting
+	Events   []EventSummary
+}
+
+// Compare 以 race 配對兩位選手的成績，計算每個都游過的項目的勝負、平均時間差與最佳成績
+func Compare(a, b []Swim) Result {
+	bSwims := make(map[string]Swim, len(b))
+	for _, swim := range b {
+		bSwims[swim.RaceID] = swim
+	}
+	result := Result{Meetings: []Meeting{}, Events: []EventSummary{}}
+	for _, swimA := range a {
+		swimB, ok := bSwims[swimA.RaceID]
+		if !ok {
+			continue
+		}
+		result.Meetings = append(result.Meetings, meet(swimA, swimB))
+	}
+	slices.SortStableFunc(result.Meetings, func(x, y Meeting) int { return x.A.Date.Compare(y.A.Date) })
+
+	bestA, bestB := bests(a), bests(b)
+	events := make(map[string]*EventSummary)
+	gaps := make(map[string][]time.Duration)
+	for event := range bestA {
+		if _, ok := bestB[event]; ok {
+			events[event] = &EventSummary{Event: event, BestA: bestA[event], BestB: bestB[event]}
+		}
+	}
+	for _, meeting := range result.Meetings {
+		event := meeting.A.Event
+		summary, ok := events[event]
+		if !ok {
+			// 同場比賽過但其中一位沒有有效成績的項目
+			summary = &EventSummary{Event: event, BestA: bestA[event], BestB: bestB[event]}
+			events[event] = summary
+		}
+		summary.Meetings++
+		switch meeting.Winner {
+		case WinnerA:
+			summary.WinsA++
+		case WinnerB:
+			summary.WinsB++
+		case WinnerTie:
+			summary.Ties++
+		case WinnerNone:
+		}
+		if meeting.Gap != nil {
+			gaps[event] = append(gaps[event], *meeting.Gap)
+		}
+	}
+	for event, summary := range events {
+		if eventGaps := gaps[event]; len(eventGaps) > 0 {
+			var total time.Duration
+			for _, gap := range eventGaps {
+				total += gap
+			}
+			average := total / time.Duration(len(eventGaps))
+			summary.AverageGap = &average
+		}
+		result.Events = append(result.Events, *summary)
+	}
+	slices.SortFunc(result.Events, func(x, y EventSummary) int { return cmp.Compare(x.Event, y.Event) })
+	return result
+}
+
+func meet(a, b Swim) Meeting {
+	meeting := Meeting{A: a, B: b, Winner: WinnerNone}
+	switch {
+	case a.Valid && b.Valid:
+		gap := a.Record - b.Record
+		meeting.Gap = &gap
+		switch {
+		case gap < 0:
+			meeting.Winner = WinnerA
+		case gap > 0:
+			meeting.Winner = WinnerB
+		default:
+			meeting.Winner = WinnerTie
+		}
+	case a.Valid:
+		meeting.Winner = WinnerA
+	case b.Valid:
+		meeting.Winner = WinnerB
+	}
+	return meeting
+}
+
+// bests 回傳每個項目的最佳有效成績
+func bests(swims []Swim) map[string]time.Duration {
+	result := make(map[string]time.Duration)
+	for _, swim := range swims {
+		if !swim.Valid || swim.Record <= 0 {
+			continue
+		}
+		if best, ok := result[swim.Event]; !ok || swim.Record < best {
+			result[swim.Event] = swim.Record
+		}
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+package headtohead
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	const (
+		back   = "100公尺仰式(50m)"
+		free   = "50公尺自由式(50m)"
+		breast = "100公尺蛙式(50m)"
+	)
+	day := func(d int) time.Time { return time.Date(2025, 5, d, 0, 0, 0, 0, time.UTC) }
+	swim := func(raceID, event string, date time.Time, record time.Duration, valid bool) Swim {
+		return Swim{RaceID: raceID, Event: event, Date: date, Record: record, Valid: valid}
+	}
+	a := []Swim{
+		swim("r3", back, day(3), 70*time.Second, true),
+		swim("r1", back, day(1), 72*time.Second, true),
+		swim("r2", back, day(2), 71*time.Second, false),
+		swim("r4", back, day(4), 69*time.Second, true),
+		swim("r5", free, day(5), 30*time.Second, true),
+		swim("r6", breast, day(6), 80*time.Second, true),
+	}
+	b := []Swim{
+		swim("r1", back, day(1), 71*time.Second, true),
+		swim("r2", back, day(2), 70*time.Second, true),
+		swim("r3", back, day(3), 71*time.Second, true),
+		swim("r4", back, day(4), 69*time.Second, true),
+		swim("r7", free, day(7), 29*time.Second, true),
+		swim("r8", back, day(8), 68*time.Second, true),
+	}
+	result := Compare(a, b)
+
+	require.Len(t, result.Meetings, 4)
+	assert.Equal(t, []string{"r1", "r2", "r3", "r4"}, []string{
+		result.Meetings[0].A.RaceID, result.Meetings[1].A.RaceID,
+		result.Meetings[2].A.RaceID, result.Meetings[3].A.RaceID,
+	})
+	assert.Equal(t, WinnerB, result.Meetings[0].Winner)
+	require.NotNil(t, result.Meetings[0].Gap)
+	assert.Equal(t, time.Second, *result.Meetings[0].Gap)
+	// A 沒有有效成績時 B 獲勝，但不計算時間差
+	assert.Equal(t, WinnerB, result.Meetings[1].Winner)
+	assert.Nil(t, result.Meetings[1].Gap)
+	assert.Equal(t, WinnerA, result.Meetings[2].Winner)
+	assert.Equal(t, WinnerTie, result.Meetings[3].Winner)
+
+	// 只有 A 游過的蛙式不列入
+	require.Len(t, result.Events, 2)
+	backSummary := result.Events[0]
+	assert.Equal(t, back, backSummary.Event)
+	assert.Equal(t, 4, backSummary.Meetings)
+	assert.Equal(t, 1, backSummary.WinsA)
+	assert.Equal(t, 2, backSummary.WinsB)
+	assert.Equal(t, 1, backSummary.Ties)
+	require.NotNil(t, backSummary.AverageGap)
+	assert.Equal(t, 0*time.Second, *backSummary.AverageGap)
+	assert.Equal(t, 69*time.Second, backSummary.BestA)
+	assert.Equal(t, 68*time.Second, backSummary.BestB)
+
+	// 沒有同場比賽過的項目仍比較最佳成績
+	freeSummary := result.Events[1]
+	assert.Equal(t, free, freeSummary.Event)
+	assert.Zero(t, freeSummary.Meetings)
+	assert.Nil(t, freeSummary.AverageGap)
+	assert.Equal(t, 30*time.Second, freeSummary.BestA)
+	assert.Equal(t, 29*time.Second, freeSummary.BestB)
+}
+
+func TestCompare_noMeetings(t *testing.T) {
+	result := Compare(nil, []Swim{{RaceID: "r1", Event: "50公尺自由式(50m)", Record: time.Minute, Valid: true}})
+	assert.Empty(t, result.Meetings)
+	assert.Empty(t, result.Events)
+}
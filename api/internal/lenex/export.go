@@ -0,0 +1,318 @@
+package lenex
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"aquascore/api/internal/athlete"
+	"aquascore/api/internal/crawler"
+)
+
+const defaultRelayCount = 4
+
+var ageReg = regexp.MustCompile(`\d+`)
+
+// MeetInfo 是匯出時比賽本身的資料
+type MeetInfo struct {
+	Name      string
+	City      string
+	Nation    string // IOC 國家代碼，例如 TPE
+	Organizer string
+	PoolType  string // 見 crawler.PoolTypeShortCourse/PoolTypeLongCourse
+}
+
+// Build 將同一場比賽的項目轉成 Lenex 文件：依日期分成場次，每個項目 (race) 是一個只有一個年齡組的 EVENT，
+// 選手的出生日期由年齡組推估 (只有年份可信)
+func Build(meet MeetInfo, races []*crawler.Race, constructor Constructor) *Lenex {
+	b := newBuilder()
+	races = slices.Clone(races)
+	slices.SortStableFunc(races, func(x, y *crawler.Race) int {
+		if c := x.Time.Compare(y.Time); c != 0 {
+			return c
+		}
+		return strings.Compare(x.EventName, y.EventName)
+	})
+	for _, race := range races {
+		b.addRace(race)
+	}
+	return &Lenex{
+		Version:     Version,
+		Constructor: &constructor,
+		Meets: []Meet{{
+			Name:      meet.Name,
+			City:      meet.City,
+			Nation:    meet.Nation,
+			Course:    courseCode(meet.PoolType),
+			Organizer: meet.Organizer,
+			Sessions:  b.sessionList(),
+			Clubs:     b.clubList(),
+		}},
+	}
+}
+
+type clubBuilder struct {
+	club     Club
+	athletes []*Athlete
+	relays   []*Relay
+}
+
+type builder struct {
+	sessions  map[string]*Session
+	dates     []string
+	clubs     map[string]*clubBuilder
+	clubOrder []string
+	athletes  map[string]*Athlete
+	births    map[*Athlete]athlete.BirthRange
+	eventID   int
+	resultID  int
+	athleteID int
+}
+
+func newBuilder() *builder {
+	return &builder{
+		sessions: make(map[string]*Session),
+		clubs:    make(map[string]*clubBuilder),
+		athletes: make(map[string]*Athlete),
+		births:   make(map[*Athlete]athlete.BirthRange),
+	}
+}
+
+func (b *builder) addRace(race *crawler.Race) {
+	b.eventID++
+	minAge, maxAge := parseAgeGroup(race.AgeGroup)
+	relayCount := 1
+	if race.Event.Relay {
+		relayCount = race.Event.RelayCount
+		if relayCount <= 1 {
+			relayCount = defaultRelayCount
+		}
+	}
+	event := Event{
+		EventID: b.eventID,
+		Number:  b.eventID,
+		Gender:  genderCode(race.Gender),
+		Round:   roundCode(race.Event.Round),
+		SwimStyle: SwimStyle{
+			Distance:   race.Event.Distance,
+			RelayCount: relayCount,
+			Stroke:     strokeCode(race.Event.Stroke),
+			Name:       race.EventType,
+		},
+	}
+	ageGroup := AgeGroup{AgeGroupID: b.eventID, AgeMin: minAge, AgeMax: maxAge, Name: race.AgeGroup}
+	gender := athlete.NormalizeGender(race.Gender)
+	birth := athlete.ParseBirthRange(race.Year, race.AgeGroup)
+	relays := make(map[string]int)
+	for _, raceResult := range race.Results {
+		b.resultID++
+		result := exportResult(b.resultID, b.eventID, totalDistance(race.Event), raceResult)
+		place := -1
+		if raceResult.Rank > 0 {
+			place = int(raceResult.Rank)
+		}
+		ageGroup.Rankings = append(ageGroup.Rankings,
+			Ranking{Order: len(ageGroup.Rankings) + 1, Place: place, ResultID: b.resultID})
+
+		if !race.Event.Relay {
+			a := b.athlete(raceResult.Unit, strings.Join(raceResult.Name, " "), gender, birth)
+			a.Results = append(a.Results, result)
+			continue
+		}
+		teamName := raceResult.Team
+		if teamName == "" {
+			teamName = raceResult.Unit
+		}
+		for i, name := range raceResult.Name {
+			a := b.athlete(raceResult.Unit, name, gender, birth)
+			result.RelayPositions = append(result.RelayPositions, RelayPosition{Number: i + 1, AthleteID: a.AthleteID})
+		}
+		club := b.club(raceResult.Unit)
+		relays[raceResult.Unit]++
+		club.relays = append(club.relays, &Relay{
+			Number:  relays[raceResult.Unit],
+			Gender:  genderCode(race.Gender),
+			Name:    teamName,
+			Results: []Result{result},
+		})
+	}
+	event.AgeGroups = []AgeGroup{ageGroup}
+	session := b.session(race.Time)
+	session.Events = append(session.Events, event)
+}
+
+// exportResult 轉換一筆成績，終點的分段即 swimtime，不另外輸出
+func exportResult(resultID, eventID, distance int, raceResult *crawler.RaceResult) Result {
+	result := Result{
+		ResultID:     resultID,
+		EventID:      eventID,
+		SwimTime:     FormatSwimTime(raceResult.Record),
+		Status:       statusCode(raceResult.Status),
+		ReactionTime: formatReactionTime(raceResult.ReactionTime),
+		Comment:      raceResult.Note,
+	}
+	for _, split := range raceResult.Splits {
+		if distance > 0 && split.Distance >= distance {
+			continue
+		}
+		result.Splits = append(result.Splits, Split{Distance: split.Distance, SwimTime: FormatSwimTime(split.Time)})
+	}
+	return result
+}
+
+func (b *builder) session(t time.Time) *Session {
+	date := t.Format(time.DateOnly)
+	session, ok := b.sessions[date]
+	if !ok {
+		session = &Session{Date: date}
+		b.sessions[date] = session
+		b.dates = append(b.dates, date)
+	}
+	return session
+}
+
+func (b *builder) club(name string) *clubBuilder {
+	club, ok := b.clubs[name]
+	if !ok {
+		club = &clubBuilder{club: Club{Name: name}}
+		b.clubs[name] = club
+		b.clubOrder = append(b.clubOrder, name)
+	}
+	return club
+}
+
+// athlete 回傳單位中同名的選手，並以這次出賽補充性別與出生年範圍
+func (b *builder) athlete(unit, name, gender string, birth athlete.BirthRange) *Athlete {
+	key := unit + "|" + name
+	a, ok := b.athletes[key]
+	if !ok {
+		b.athleteID++
+		last, first := splitName(name)
+		a = &Athlete{AthleteID: b.athleteID, LastName: last, FirstName: first}
+		b.athletes[key] = a
+		club := b.club(unit)
+		club.athletes = append(club.athletes, a)
+	}
+	if a.Gender == "" {
+		a.Gender = gender
+	}
+	b.births[a] = b.births[a].Intersect(birth)
+	return a
+}
+
+func (b *builder) sessionList() []Session {
+	slices.Sort(b.dates)
+	sessions := make([]Session, len(b.dates))
+	for i, date := range b.dates {
+		sessions[i] = *b.sessions[date]
+		sessions[i].Number = i + 1
+	}
+	return sessions
+}
+
+func (b *builder) clubList() []Club {
+	clubs := make([]Club, len(b.clubOrder))
+	for i, name := range b.clubOrder {
+		club := b.clubs[name]
+		clubs[i] = club.club
+		for _, a := range club.athletes {
+			a.BirthDate = birthDate(b.births[a])
+			clubs[i].Athletes = append(clubs[i].Athletes, *a)
+		}
+		for _, relay := range club.relays {
+			clubs[i].Relays = append(clubs[i].Relays, *relay)
+		}
+	}
+	return clubs
+}
+
+// birthDate 以推估出生年的上限 (民國) 的 1 月 1 日作為出生日期，未知時為空
+func birthDate(birth athlete.BirthRange) string {
+	year := birth.Max
+	if year == 0 {
+		year = birth.Min
+	}
+	if year == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%04d-01-01", year+rocYearOffset)
+}
+
+// splitName 將姓名分成姓與名：中文姓名的第一個字為姓，其他以最後一個空白分開 ("名 姓")
+func splitName(name string) (last, first string) {
+	if i := strings.LastIndex(name, " "); i >= 0 {
+		return name[i+1:], name[:i]
+	}
+	if hanReg.MatchString(name) && utf8.RuneCountInString(name) > 1 {
+		_, size := utf8.DecodeRuneInString(name)
+		return name[:size], name[size:]
+	}
+	return name, ""
+}
+
+// parseAgeGroup 由年齡組 (例如 "11&12歲級"、"18及以上歲級") 找出年齡範圍，-1 代表不限
+func parseAgeGroup(ageGroup string) (minAge, maxAge int) {
+	ages := ageReg.FindAllString(ageGroup, -1)
+	if len(ages) == 0 {
+		return -1, -1
+	}
+	minAge, _ = strconv.Atoi(ages[0])
+	maxAge, _ = strconv.Atoi(ages[len(ages)-1])
+	switch {
+	case strings.Contains(ageGroup, "以上"):
+		return minAge, -1
+	case strings.Contains(ageGroup, "以下"):
+		return -1, maxAge
+	}
+	return minAge, maxAge
+}
+
+// genderCode 回傳組別的 Lenex 性別代碼 (M、F、X)
+func genderCode(gender string) string {
+	if code := athlete.NormalizeGender(gender); code != "" {
+		return code
+	}
+	if strings.Contains(gender, "混合") {
+		return "X"
+	}
+	return ""
+}
+
+func courseCode(poolType string) string {
+	return reverseLookup(courses, poolType)
+}
+
+func roundCode(round crawler.Round) string {
+	return reverseLookup(rounds, round)
+}
+
+func strokeCode(stroke crawler.Stroke) string {
+	if stroke == crawler.StrokeMedley {
+		return "MEDLEY"
+	}
+	if code := reverseLookup(strokes, stroke); code != "" {
+		return code
+	}
+	return "UNKNOWN"
+}
+
+func statusCode(status crawler.ResultStatus) string {
+	if status == crawler.ResultStatusDNS {
+		return "DNS"
+	}
+	return reverseLookup(statuses, status)
+}
+
+// reverseLookup 回傳 codes 中對應 value 的代碼，找不到時回傳空字串
+func reverseLookup[V comparable](codes map[string]V, value V) string {
+	for code, v := range codes {
+		if v == value {
+			return code
+		}
+	}
+	return ""
+}
@@ -0,0 +1,321 @@
+package lenex
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"aquascore/api/internal/athlete"
+	"aquascore/api/internal/crawler"
+)
+
+const (
+	// SourceName 是由 Lenex 匯入的 Race 的來源名稱
+	SourceName = "lenex"
+
+	rocYearOffset = 1911
+)
+
+var (
+	strokes = map[string]crawler.Stroke{
+		"FREE":    crawler.StrokeFreestyle,
+		"BACK":    crawler.StrokeBackstroke,
+		"BREAST":  crawler.StrokeBreaststroke,
+		"FLY":     crawler.StrokeButterfly,
+		"MEDLEY":  crawler.StrokeMedley,
+		"IMRELAY": crawler.StrokeMedley,
+	}
+	rounds = map[string]crawler.Round{
+		"TIM": crawler.RoundTimedFinal,
+		"FHT": crawler.RoundFastHeatTimedFinal,
+		"FIN": crawler.RoundFinal,
+		"SEM": crawler.RoundSemifinal,
+		"PRE": crawler.RoundHeat,
+	}
+	genders = map[string]string{
+		"M": "男子組",
+		"F": "女子組",
+		"X": "混合組",
+	}
+	courses = map[string]string{
+		"LCM": crawler.PoolTypeLongCourse,
+		"SCM": crawler.PoolTypeShortCourse,
+	}
+	statuses = map[string]crawler.ResultStatus{
+		"DSQ":  crawler.ResultStatusDQ,
+		"DNS":  crawler.ResultStatusDNS,
+		"SICK": crawler.ResultStatusDNS,
+		"DNF":  crawler.ResultStatusDNF,
+		"WDR":  crawler.ResultStatusScratch,
+		"EXH":  crawler.ResultStatusExhibition,
+	}
+	hanReg = regexp.MustCompile(`^\p{Han}+$`)
+)
+
+// ImportedRace 是由 Lenex 轉換出的一個項目，Key 在同一份檔案中唯一 ("項目 ID/年齡組 ID")
+type ImportedRace struct {
+	Key  string
+	Race *crawler.Race
+}
+
+// entry 是一筆成績與所屬的單位、選手或接力隊伍
+type entry struct {
+	club    *Club
+	athlete *Athlete // 個人成績
+	relay   *Relay   // 接力成績
+	result  *Result
+}
+
+// Races 將 Lenex 文件中的成績轉成 crawler.Race：每個項目的每個有名次的年齡組為一個 Race，
+// 不在任何年齡組名次中的成績另外成為一個沒有年齡組的 Race
+func Races(doc *Lenex) ([]ImportedRace, error) {
+	var races []ImportedRace
+	for i := range doc.Meets {
+		meetRaces, err := convertMeet(&doc.Meets[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", doc.Meets[i].Name, err)
+		}
+		races = append(races, meetRaces...)
+	}
+	return races, nil
+}
+
+func convertMeet(meet *Meet) ([]ImportedRace, error) {
+	athletes := make(map[int]*Athlete)
+	entries := make(map[int][]entry)
+	for ci := range meet.Clubs {
+		club := &meet.Clubs[ci]
+		for ai := range club.Athletes {
+			a := &club.Athletes[ai]
+			athletes[a.AthleteID] = a
+			for ri := range a.Results {
+				result := &a.Results[ri]
+				entries[result.EventID] = append(entries[result.EventID], entry{club: club, athlete: a, result: result})
+			}
+		}
+		for ri := range club.Relays {
+			relay := &club.Relays[ri]
+			for i := range relay.Results {
+				result := &relay.Results[i]
+				entries[result.EventID] = append(entries[result.EventID], entry{club: club, relay: relay, result: result})
+			}
+		}
+	}
+
+	var races []ImportedRace
+	for _, session := range meet.Sessions {
+		date, err := time.Parse(time.DateOnly, session.Date)
+		if err != nil {
+			return nil, fmt.Errorf("場次 %d 日期格式錯誤: %w", session.Number, err)
+		}
+		for i := range session.Events {
+			event := &session.Events[i]
+			races = append(races, convertEvent(meet, date, event, entries[event.EventID], athletes)...)
+		}
+	}
+	return races, nil
+}
+
+func convertEvent(
+	meet *Meet, date time.Time, event *Event, entries []entry, athletes map[int]*Athlete,
+) []ImportedRace {
+	byResult := make(map[int]entry, len(entries))
+	for _, e := range entries {
+		byResult[e.result.ResultID] = e
+	}
+	assigned := make(map[int]bool, len(entries))
+	var races []ImportedRace
+	for _, ageGroup := range event.AgeGroups {
+		if len(ageGroup.Rankings) == 0 {
+			continue
+		}
+		race := newRace(meet, date, event, ageGroupLabel(&ageGroup))
+		for _, ranking := range ageGroup.Rankings {
+			e, ok := byResult[ranking.ResultID]
+			if !ok {
+				continue
+			}
+			assigned[ranking.ResultID] = true
+			result, err := convertResult(e, race.Event, athletes)
+			if err != nil {
+				race.Unparsed = append(race.Unparsed, unparsedRow(len(race.Results)+len(race.Unparsed)+1, e, err))
+				continue
+			}
+			if ranking.Place > 0 {
+				result.Rank = int32(ranking.Place)
+			}
+			race.Results = append(race.Results, result)
+		}
+		races = append(races, ImportedRace{Key: fmt.Sprintf("%d/%d", event.EventID, ageGroup.AgeGroupID), Race: race})
+	}
+
+	// 沒有名次的成績 (或檔案沒有年齡組) 依原本的順序放在沒有年齡組的 Race
+	var race *crawler.Race
+	for _, e := range entries {
+		if assigned[e.result.ResultID] {
+			continue
+		}
+		if race == nil {
+			race = newRace(meet, date, event, "")
+		}
+		result, err := convertResult(e, race.Event, athletes)
+		if err != nil {
+			race.Unparsed = append(race.Unparsed, unparsedRow(len(race.Results)+len(race.Unparsed)+1, e, err))
+			continue
+		}
+		race.Results = append(race.Results, result)
+	}
+	if race != nil {
+		races = append(races, ImportedRace{Key: fmt.Sprintf("%d/0", event.EventID), Race: race})
+	}
+	return races
+}
+
+func newRace(meet *Meet, date time.Time, event *Event, ageGroup string) *crawler.Race {
+	style := event.SwimStyle
+	e := crawler.Event{
+		Distance: style.Distance,
+		Stroke:   strokes[style.Stroke],
+		Round:    rounds[event.Round],
+	}
+	if style.RelayCount > 1 {
+		e.Relay = true
+		e.RelayCount = style.RelayCount
+	}
+	gender := genders[event.Gender]
+	eventType := eventLabel(e, style.Name)
+	roundLabel := e.Round.Label()
+	return &crawler.Race{
+		Source:          SourceName,
+		Organizer:       meet.Organizer,
+		Venue:           meet.City,
+		Year:            strconv.Itoa(date.Year() - rocYearOffset),
+		Type:            roundLabel,
+		CompetitionName: strings.TrimSpace(meet.Name),
+		Gender:          gender,
+		PoolType:        courses[meet.Course],
+		AgeGroup:        ageGroup,
+		EventType:       eventType,
+		EventName:       joinNonEmpty(ageGroup+gender, eventType, roundLabel),
+		Event:           e,
+		Time:            date,
+	}
+}
+
+func convertResult(e entry, event crawler.Event, athletes map[int]*Athlete) (*crawler.RaceResult, error) {
+	record, err := ParseSwimTime(e.result.SwimTime)
+	if err != nil {
+		return nil, err
+	}
+	result := &crawler.RaceResult{
+		Unit:         strings.TrimSpace(e.club.Name),
+		Record:       record,
+		Note:         e.result.Comment,
+		ReactionTime: parseReactionTime(e.result.ReactionTime),
+	}
+	if status, ok := statuses[e.result.Status]; ok {
+		result.Status = status
+		if status == crawler.ResultStatusDQ {
+			result.StatusReason = e.result.Comment
+		}
+	} else {
+		result.Status, _ = crawler.ParseResultStatus("", record > 0)
+	}
+	splits := make([]crawler.Split, 0, len(e.result.Splits))
+	for _, split := range e.result.Splits {
+		t, err := ParseSwimTime(split.SwimTime)
+		if err != nil || t <= 0 {
+			continue
+		}
+		splits = append(splits, crawler.Split{Distance: split.Distance, Time: t})
+	}
+	// Lenex 的分段不含終點 (即 swimtime)，補上後與其他來源一致
+	if len(splits) > 0 && record > 0 {
+		splits = append(splits, crawler.Split{Distance: totalDistance(event), Time: record})
+	}
+	result.Splits = crawler.NormalizeSplits(splits, record)
+
+	if e.relay == nil {
+		result.Name = []string{fullName(e.athlete)}
+		return result, nil
+	}
+	result.Team = relayTeamName(e.club, e.relay)
+	positions := slices.Clone(e.result.RelayPositions)
+	slices.SortFunc(positions, func(a, b RelayPosition) int { return a.Number - b.Number })
+	for _, position := range positions {
+		a := position.Athlete
+		if a == nil {
+			a = athletes[position.AthleteID]
+		}
+		if a == nil {
+			continue
+		}
+		result.Name = append(result.Name, fullName(a))
+		if position.Number == 1 && result.ReactionTime == 0 {
+			result.ReactionTime = parseReactionTime(position.ReactionTime)
+		}
+	}
+	result.Legs = crawler.RelayLegs(event, result.Name)
+	crawler.ApplyLegSplits(event, result)
+	return result, nil
+}
+
+// totalDistance 回傳項目的總距離，接力為每棒距離乘上棒數
+func totalDistance(event crawler.Event) int {
+	if event.Relay {
+		return event.Distance * event.RelayCount
+	}
+	return event.Distance
+}
+
+func unparsedRow(row int, e entry, err error) crawler.UnparsedRow {
+	name := e.club.Name
+	if e.athlete != nil {
+		name = fullName(e.athlete)
+	}
+	return crawler.UnparsedRow{
+		Row:    row,
+		Text:   fmt.Sprintf("resultid=%d %s %s", e.result.ResultID, name, e.result.SwimTime),
+		Reason: err.Error(),
+	}
+}
+
+// fullName 回傳選手姓名，見 athlete.JoinName
+func fullName(a *Athlete) string {
+	return athlete.JoinName(a.LastName, a.FirstName)
+}
+
+// relayTeamName 回傳接力隊伍名稱，同一單位的第二隊以後加上 B、C...
+func relayTeamName(club *Club, relay *Relay) string {
+	if relay.Name != "" {
+		return relay.Name
+	}
+	name := strings.TrimSpace(club.Name)
+	if relay.Number > 1 {
+		name += string(rune('A' + relay.Number - 1))
+	}
+	return name
+}
+
+// ageGroupLabel 將年齡組轉成與 CTSA 相同的寫法，不限年齡時使用年齡組名稱
+func ageGroupLabel(ageGroup *AgeGroup) string {
+	if label := crawler.AgeGroupLabel(ageGroup.AgeMin, ageGroup.AgeMax); label != "" {
+		return label
+	}
+	return ageGroup.Name
+}
+
+// eventLabel 回傳 CTSA 寫法的項目名稱，泳式不明時使用 name
+func eventLabel(event crawler.Event, name string) string {
+	if label := event.Label(); label != "" {
+		return label
+	}
+	return name
+}
+
+func joinNonEmpty(parts ...string) string {
+	return strings.Join(slices.DeleteFunc(parts, func(s string) bool { return s == "" }), " ")
+}
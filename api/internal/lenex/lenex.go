@@ -0,0 +1,251 @@
+// Package lenex 讀寫 Lenex 3.0 (.lef/.lxf) 成績檔，並與 crawler.Race 互相轉換
+package lenex
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"aquascore/api/internal/crawler"
+)
+
+const (
+	Version = "3.0"
+
+	// NoTime 是沒有成績時的 swimtime
+	NoTime = "NT"
+
+	hundredth = 10 * time.Millisecond
+)
+
+// Lenex 是 Lenex 文件的根元素
+type Lenex struct {
+	XMLName     xml.Name     `xml:"LENEX"`
+	Version     string       `xml:"version,attr"`
+	Constructor *Constructor `xml:"CONSTRUCTOR"`
+	Meets       []Meet       `xml:"MEETS>MEET"`
+}
+
+// Constructor 是產生檔案的軟體
+type Constructor struct {
+	Name    string  `xml:"name,attr"`
+	Version string  `xml:"version,attr"`
+	Contact Contact `xml:"CONTACT"`
+}
+
+type Contact struct {
+	Name  string `xml:"name,attr,omitempty"`
+	Email string `xml:"email,attr"`
+}
+
+// Meet 是一場比賽
+type Meet struct {
+	Name      string    `xml:"name,attr"`
+	City      string    `xml:"city,attr"`
+	Nation    string    `xml:"nation,attr"`
+	Course    string    `xml:"course,attr,omitempty"` // LCM (50m)、SCM (25m)
+	Organizer string    `xml:"organizer,attr,omitempty"`
+	Sessions  []Session `xml:"SESSIONS>SESSION"`
+	Clubs     []Club    `xml:"CLUBS>CLUB"`
+}
+
+// Session 是比賽中的一個場次
+type Session struct {
+	Number int     `xml:"number,attr"`
+	Date   string  `xml:"date,attr"` // YYYY-MM-DD
+	Name   string  `xml:"name,attr,omitempty"`
+	Events []Event `xml:"EVENTS>EVENT"`
+}
+
+// Event 是場次中的一個項目，AgeGroups 中的 Rankings 記錄各年齡組的名次
+type Event struct {
+	EventID   int        `xml:"eventid,attr"`
+	Number    int        `xml:"number,attr"`
+	Gender    string     `xml:"gender,attr,omitempty"` // M、F、X (混合)
+	Round     string     `xml:"round,attr,omitempty"`  // TIM、FHT、FIN、SEM、PRE
+	SwimStyle SwimStyle  `xml:"SWIMSTYLE"`
+	AgeGroups []AgeGroup `xml:"AGEGROUPS>AGEGROUP"`
+}
+
+type SwimStyle struct {
+	Distance   int    `xml:"distance,attr"`
+	RelayCount int    `xml:"relaycount,attr"`
+	Stroke     string `xml:"stroke,attr"` // FREE、BACK、BREAST、FLY、MEDLEY
+	Name       string `xml:"name,attr,omitempty"`
+}
+
+// AgeGroup 是項目中的年齡組，-1 代表不限
+type AgeGroup struct {
+	AgeGroupID int       `xml:"agegroupid,attr"`
+	AgeMin     int       `xml:"agemin,attr"`
+	AgeMax     int       `xml:"agemax,attr"`
+	Name       string    `xml:"name,attr,omitempty"`
+	Rankings   []Ranking `xml:"RANKINGS>RANKING"`
+}
+
+// Ranking 是年齡組中的一個名次，Place 為 -1 代表沒有名次 (例如犯規)
+type Ranking struct {
+	Order    int `xml:"order,attr,omitempty"`
+	Place    int `xml:"place,attr"`
+	ResultID int `xml:"resultid,attr"`
+}
+
+// Club 是代表隊 (學校或俱樂部)
+type Club struct {
+	Name     string    `xml:"name,attr"`
+	Code     string    `xml:"code,attr,omitempty"`
+	Nation   string    `xml:"nation,attr,omitempty"`
+	Athletes []Athlete `xml:"ATHLETES>ATHLETE"`
+	Relays   []Relay   `xml:"RELAYS>RELAY"`
+}
+
+type Athlete struct {
+	AthleteID int      `xml:"athleteid,attr"`
+	LastName  string   `xml:"lastname,attr"`
+	FirstName string   `xml:"firstname,attr"`
+	Gender    string   `xml:"gender,attr,omitempty"`    // M、F
+	BirthDate string   `xml:"birthdate,attr,omitempty"` // YYYY-MM-DD
+	Results   []Result `xml:"RESULTS>RESULT"`
+}
+
+// Relay 是一支接力隊伍，Number 區分同一單位的第幾隊
+type Relay struct {
+	Number  int      `xml:"number,attr"`
+	Gender  string   `xml:"gender,attr,omitempty"`
+	Name    string   `xml:"name,attr,omitempty"`
+	Results []Result `xml:"RESULTS>RESULT"`
+}
+
+// Result 是一筆成績，Status 為空代表正常完賽
+type Result struct {
+	ResultID       int             `xml:"resultid,attr"`
+	EventID        int             `xml:"eventid,attr"`
+	SwimTime       string          `xml:"swimtime,attr,omitempty"` // HH:MM:SS.ss
+	Status         string          `xml:"status,attr,omitempty"`   // DSQ、DNS、DNF、WDR、SICK、EXH
+	ReactionTime   string          `xml:"reactiontime,attr,omitempty"`
+	Comment        string          `xml:"comment,attr,omitempty"`
+	Splits         []Split         `xml:"SPLITS>SPLIT"`
+	RelayPositions []RelayPosition `xml:"RELAYPOSITIONS>RELAYPOSITION"`
+}
+
+// Split 是累計分段
+type Split struct {
+	Distance int    `xml:"distance,attr"`
+	SwimTime string `xml:"swimtime,attr"`
+}
+
+// RelayPosition 是接力的一棒，選手可能引用單位中的 ATHLETE 或直接內嵌
+type RelayPosition struct {
+	Number       int      `xml:"number,attr"`
+	AthleteID    int      `xml:"athleteid,attr,omitempty"`
+	ReactionTime string   `xml:"reactiontime,attr,omitempty"`
+	Athlete      *Athlete `xml:"ATHLETE"`
+}
+
+// Read 讀取 .lef (XML) 或 .lxf (zip 壓縮的 .lef) 檔案
+func Read(r io.Reader) (*Lenex, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read lenex fail: %w", err)
+	}
+	if bytes.HasPrefix(data, []byte("PK")) {
+		data, err = unzipLef(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var doc Lenex
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decode lenex fail: %w", err)
+	}
+	return &doc, nil
+}
+
+// unzipLef 回傳 .lxf 中第一個 .lef 檔的內容
+func unzipLef(data []byte) ([]byte, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open lxf fail: %w", err)
+	}
+	for _, file := range archive.File {
+		if !strings.EqualFold(path.Ext(file.Name), ".lef") {
+			continue
+		}
+		f, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("open %s fail: %w", file.Name, err)
+		}
+		defer f.Close()
+		return io.ReadAll(f)
+	}
+	return nil, errors.New("lxf 中沒有 .lef 檔")
+}
+
+// Write 寫出 .lef (XML) 檔案
+func Write(w io.Writer, doc *Lenex) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("encode lenex fail: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteCompressed 寫出 .lxf 檔案，name 是壓縮檔中的 .lef 檔名
+func WriteCompressed(w io.Writer, doc *Lenex, name string) error {
+	archive := zip.NewWriter(w)
+	f, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("create %s fail: %w", name, err)
+	}
+	if err := Write(f, doc); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// ParseSwimTime 解析 "HH:MM:SS.ss" 格式的時間，"NT" 或空字串回傳 0
+func ParseSwimTime(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == NoTime {
+		return 0, nil
+	}
+	return crawler.ParseSwimTime(s)
+}
+
+// FormatSwimTime 將時間格式化為 "HH:MM:SS.ss"，0 回傳 "NT"
+func FormatSwimTime(d time.Duration) string {
+	if d <= 0 {
+		return NoTime
+	}
+	hundredths := d.Round(hundredth) / hundredth
+	return fmt.Sprintf("%02d:%02d:%02d.%02d",
+		hundredths/360000, hundredths%360000/6000, hundredths%6000/100, hundredths%100)
+}
+
+// parseReactionTime 解析以百分之一秒為單位的反應時間，例如 "+68"
+func parseReactionTime(s string) time.Duration {
+	n, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(s), "+"))
+	if err != nil || n <= 0 {
+		return 0
+	}
+	return time.Duration(n) * hundredth
+}
+
+func formatReactionTime(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("+%d", d.Round(hundredth)/hundredth)
+}
@@ -0,0 +1,222 @@
+package lenex
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"aquascore/api/internal/crawler"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readFixture(t *testing.T) *Lenex {
+	t.Helper()
+	file, err := os.Open("test_file/meet.lef")
+	require.NoError(t, err)
+	defer file.Close()
+	doc, err := Read(file)
+	require.NoError(t, err)
+	return doc
+}
+
+func racesByKey(t *testing.T, doc *Lenex) map[string]*crawler.Race {
+	t.Helper()
+	imported, err := Races(doc)
+	require.NoError(t, err)
+	races := make(map[string]*crawler.Race, len(imported))
+	for _, r := range imported {
+		races[r.Key] = r.Race
+	}
+	return races
+}
+
+func TestSwimTime(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		output   string
+	}{
+		{"00:01:02.35", time.Minute + 2*time.Second + 350*time.Millisecond, "00:01:02.35"},
+		{"00:00:29.80", 29*time.Second + 800*time.Millisecond, "00:00:29.80"},
+		{"01:02:03.45", time.Hour + 2*time.Minute + 3*time.Second + 450*time.Millisecond, "01:02:03.45"},
+		{"NT", 0, "NT"},
+		{"", 0, "NT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			d, err := ParseSwimTime(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, d)
+			assert.Equal(t, tt.output, FormatSwimTime(d))
+		})
+	}
+
+	_, err := ParseSwimTime("1:2x.00")
+	assert.Error(t, err)
+
+	assert.Equal(t, 680*time.Millisecond, parseReactionTime("+68"))
+	assert.Equal(t, time.Duration(0), parseReactionTime("abc"))
+	assert.Equal(t, "+68", formatReactionTime(680*time.Millisecond))
+	assert.Empty(t, formatReactionTime(0))
+}
+
+func TestRaces(t *testing.T) {
+	races := racesByKey(t, readFixture(t))
+	require.Len(t, races, 4)
+
+	race := races["10/1"]
+	require.NotNil(t, race)
+	assert.Equal(t, SourceName, race.Source)
+	assert.Equal(t, "113", race.Year)
+	assert.Equal(t, "113年全國分齡游泳錦標賽", race.CompetitionName)
+	assert.Equal(t, "中華民國游泳協會", race.Organizer)
+	assert.Equal(t, "臺北市", race.Venue)
+	assert.Equal(t, crawler.PoolTypeLongCourse, race.PoolType)
+	assert.Equal(t, "男子組", race.Gender)
+	assert.Equal(t, "11&12歲級", race.AgeGroup)
+	assert.Equal(t, "100公尺自由式", race.EventType)
+	assert.Equal(t, "11&12歲級男子組 100公尺自由式 計時決賽", race.EventName)
+	assert.Equal(t, crawler.Event{Distance: 100, Stroke: crawler.StrokeFreestyle, Round: crawler.RoundTimedFinal},
+		race.Event)
+	assert.Equal(t, time.Date(2024, 7, 20, 0, 0, 0, 0, time.UTC), race.Time)
+	require.Len(t, race.Results, 3)
+
+	first := race.Results[0]
+	assert.Equal(t, []string{"王小明"}, first.Name)
+	assert.Equal(t, "臺北市立大同高中", first.Unit)
+	assert.Equal(t, int32(1), first.Rank)
+	assert.Equal(t, time.Minute+2*time.Second+350*time.Millisecond, first.Record)
+	assert.Equal(t, 680*time.Millisecond, first.ReactionTime)
+	assert.Equal(t, crawler.ResultStatusOK, first.Status)
+	assert.Equal(t, []crawler.Split{
+		{Distance: 50, Time: 29*time.Second + 800*time.Millisecond},
+		{Distance: 100, Time: first.Record},
+	}, first.Splits)
+
+	assert.Equal(t, []string{"Wei Ting Chen"}, race.Results[1].Name)
+	assert.Equal(t, int32(2), race.Results[1].Rank)
+
+	dq := race.Results[2]
+	assert.Equal(t, []string{"李大華"}, dq.Name)
+	assert.Equal(t, int32(0), dq.Rank)
+	assert.Equal(t, time.Duration(0), dq.Record)
+	assert.Equal(t, crawler.ResultStatusDQ, dq.Status)
+	assert.Equal(t, "轉身犯規", dq.StatusReason)
+
+	assert.Equal(t, "18及以上歲級", races["10/2"].AgeGroup)
+	require.Len(t, races["10/2"].Results, 1)
+
+	// 不在名次中且時間格式錯誤的成績
+	unranked := races["10/0"]
+	assert.Empty(t, unranked.AgeGroup)
+	assert.Empty(t, unranked.Results)
+	require.Len(t, unranked.Unparsed, 1)
+	assert.Contains(t, unranked.Unparsed[0].Text, "resultid=105")
+}
+
+func TestRaces_relay(t *testing.T) {
+	race := racesByKey(t, readFixture(t))["20/0"]
+	require.NotNil(t, race)
+	assert.Equal(t, "4×50公尺混合式接力", race.EventType)
+	assert.Equal(t, crawler.Event{
+		Distance: 50, Stroke: crawler.StrokeMedley, Relay: true, RelayCount: 4, Round: crawler.RoundTimedFinal,
+	}, race.Event)
+	require.Len(t, race.Results, 2)
+
+	relay := race.Results[0]
+	assert.Equal(t, "臺北市立大同高中", relay.Team)
+	assert.Equal(t, []string{"王小明", "李大華", "陳志強", "林建宏"}, relay.Name)
+	assert.Equal(t, 700*time.Millisecond, relay.ReactionTime)
+	require.Len(t, relay.Legs, 4)
+	assert.Equal(t, crawler.RelayLeg{Swimmer: "王小明", Stroke: crawler.StrokeBackstroke, Split: 33100 * time.Millisecond},
+		relay.Legs[0])
+	assert.Equal(t, 29400*time.Millisecond, relay.Legs[3].Split)
+
+	dns := race.Results[1]
+	assert.Equal(t, "Kaohsiung Swim ClubB", dns.Team)
+	assert.Equal(t, []string{"Kai Wu"}, dns.Name)
+	assert.Equal(t, crawler.ResultStatusDNS, dns.Status)
+}
+
+func TestRead_lxf(t *testing.T) {
+	data, err := os.ReadFile("test_file/meet.lef")
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	f, err := archive.Create("meet.lef")
+	require.NoError(t, err)
+	_, err = f.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, archive.Close())
+
+	doc, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, readFixture(t), doc)
+}
+
+func TestBuild_roundTrip(t *testing.T) {
+	imported, err := Races(readFixture(t))
+	require.NoError(t, err)
+	var races []*crawler.Race
+	for _, r := range imported {
+		if len(r.Race.Results) > 0 {
+			races = append(races, r.Race)
+		}
+	}
+	meet := MeetInfo{
+		Name: "113年全國分齡游泳錦標賽", City: "臺北市", Nation: "TPE", Organizer: "中華民國游泳協會",
+		PoolType: crawler.PoolTypeLongCourse,
+	}
+	doc := Build(meet, races, Constructor{Name: "aquascore", Version: "1.0", Contact: Contact{Email: "a@b.c"}})
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCompressed(&buf, doc, "meet.lef"))
+	written, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, Version, written.Version)
+	require.Len(t, written.Meets, 1)
+	assert.Equal(t, "LCM", written.Meets[0].Course)
+	require.Len(t, written.Meets[0].Sessions, 2)
+
+	again, err := Races(written)
+	require.NoError(t, err)
+	require.Len(t, again, len(races))
+	for i, r := range again {
+		expected := races[i]
+		assert.Equal(t, expected.EventName, r.Race.EventName)
+		assert.Equal(t, expected.AgeGroup, r.Race.AgeGroup)
+		assert.Equal(t, expected.Event, r.Race.Event)
+		assert.Equal(t, expected.Time, r.Race.Time)
+		assert.Equal(t, expected.Results, r.Race.Results, expected.EventName)
+	}
+
+	// 出生日期由年齡組推估
+	clubs := written.Meets[0].Clubs
+	require.NotEmpty(t, clubs)
+	assert.Equal(t, "臺北市立大同高中", clubs[0].Name)
+	assert.Equal(t, "王", clubs[0].Athletes[0].LastName)
+	assert.Equal(t, "小明", clubs[0].Athletes[0].FirstName)
+	assert.Equal(t, "2013-01-01", clubs[0].Athletes[0].BirthDate)
+}
+
+func TestParseAgeGroup(t *testing.T) {
+	tests := []struct {
+		input    string
+		min, max int
+	}{
+		{"11&12歲級", 11, 12},
+		{"18及以上歲級", 18, -1},
+		{"10及以下歲級", -1, 10},
+		{"公開組", -1, -1},
+	}
+	for _, tt := range tests {
+		minAge, maxAge := parseAgeGroup(tt.input)
+		assert.Equal(t, tt.min, minAge, tt.input)
+		assert.Equal(t, tt.max, maxAge, tt.input)
+		assert.Equal(t, tt.input, ageGroupLabel(&AgeGroup{AgeMin: minAge, AgeMax: maxAge, Name: tt.input}))
+	}
+}
@@ -0,0 +1,95 @@
+package pacing
+
+import "time"
+
+// Split 是成績中的一個分段，Time 為出發到 Distance 公尺的累計時間
+type Split struct {
+	Distance int
+	Time     time.Duration
+}
+
+// Swim 是一次個人項目的成績，Splits 依距離排列
+type Swim struct {
+	Record time.Duration
+	Splits []Split
+}
+
+// Lap 是兩個分段之間的一段
+type Lap struct {
+	Distance   int           // 這一段結束的距離
+	Cumulative time.Duration // 出發到這一段結束的累計時間
+	Time       time.Duration // 這一段的時間
+	Share      float64       // 這一段佔總成績的比例
+	// BestDiff 是與比較成績在同一距離累計時間的差，正值代表較慢；比較成績沒有這個分段時為 nil
+	BestDiff *time.Duration
+}
+
+// Profile 是一次成績的配速
+type Profile struct {
+	Laps       []Lap
+	FirstHalf  time.Duration // 前半段時間，沒有半程分段時為 0
+	SecondHalf time.Duration // 後半段時間，沒有半程分段時為 0
+	// Differential 是後半段減前半段，正值代表後段較慢 (正分段)，沒有半程分段時為 0
+	Differential time.Duration
+}
+
+// HasHalves 回傳是否有半程分段可以比較前後半段
+func (p *Profile) HasHalves() bool {
+	return p.FirstHalf > 0 && p.SecondHalf > 0
+}
+
+// Analyze 分析距離為 distance 的一次成績，best 不為 nil 時逐段與其比較
+func Analyze(swim Swim, distance int, best *Swim) Profile {
+	var profile Profile
+	total := swim.Record
+	if total <= 0 && len(swim.Splits) > 0 {
+		total = swim.Splits[len(swim.Splits)-1].Time
+	}
+	bestTimes := make(map[int]time.Duration)
+	if best != nil {
+		for _, split := range best.Splits {
+			bestTimes[split.Distance] = split.Time
+		}
+	}
+	var previous time.Duration
+	for _, split := range swim.Splits {
+		lap := Lap{
+			Distance:   split.Distance,
+			Cumulative: split.Time,
+			Time:       split.Time - previous,
+		}
+		if total > 0 {
+			lap.Share = float64(lap.Time) / float64(total)
+		}
+		if bestTime, ok := bestTimes[split.Distance]; ok {
+			diff := split.Time - bestTime
+			lap.BestDiff = &diff
+		}
+		profile.Laps = append(profile.Laps, lap)
+		previous = split.Time
+		if distance > 0 && split.Distance*2 == distance {
+			profile.FirstHalf = split.Time
+		}
+	}
+	if profile.FirstHalf > 0 && total > profile.FirstHalf {
+		profile.SecondHalf = total - profile.FirstHalf
+		profile.Differential = profile.SecondHalf - profile.FirstHalf
+	} else {
+		profile.FirstHalf = 0
+	}
+	return profile
+}
+
+// Best 回傳有分段的成績中最快的一筆位置，都沒有分段時回傳 -1
+func Best(swims []Swim) int {
+	best := -1
+	for i, swim := range swims {
+		if swim.Record <= 0 || len(swim.Splits) == 0 {
+			continue
+		}
+		if best < 0 || swim.Record < swims[best].Record {
+			best = i
+		}
+	}
+	return best
+}
@@ -0,0 +1,60 @@
+package pacing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	best := &Swim{
+		Record: 120 * time.Second,
+		Splits: []Split{
+			{Distance: 50, Time: 28 * time.Second},
+			{Distance: 100, Time: 58 * time.Second},
+			{Distance: 200, Time: 120 * time.Second},
+		},
+	}
+	swim := Swim{
+		Record: 125 * time.Second,
+		Splits: []Split{
+			{Distance: 50, Time: 29 * time.Second},
+			{Distance: 100, Time: 60 * time.Second},
+			{Distance: 150, Time: 92 * time.Second},
+			{Distance: 200, Time: 125 * time.Second},
+		},
+	}
+	profile := Analyze(swim, 200, best)
+	require.Len(t, profile.Laps, 4)
+	assert.Equal(t, 29*time.Second, profile.Laps[0].Time)
+	assert.Equal(t, 31*time.Second, profile.Laps[1].Time)
+	assert.Equal(t, 33*time.Second, profile.Laps[3].Time)
+	assert.InDelta(t, 0.232, profile.Laps[0].Share, 0.001)
+	require.NotNil(t, profile.Laps[1].BestDiff)
+	assert.Equal(t, 2*time.Second, *profile.Laps[1].BestDiff)
+	assert.Nil(t, profile.Laps[2].BestDiff)
+	assert.True(t, profile.HasHalves())
+	assert.Equal(t, 60*time.Second, profile.FirstHalf)
+	assert.Equal(t, 65*time.Second, profile.SecondHalf)
+	assert.Equal(t, 5*time.Second, profile.Differential)
+
+	// 沒有半程分段
+	profile = Analyze(Swim{Record: 65 * time.Second, Splits: []Split{{Distance: 25, Time: 14 * time.Second}}}, 100, nil)
+	assert.False(t, profile.HasHalves())
+	assert.Zero(t, profile.Differential)
+	assert.Nil(t, profile.Laps[0].BestDiff)
+}
+
+func TestBest(t *testing.T) {
+	swims := []Swim{
+		{Record: 60 * time.Second, Splits: []Split{{Distance: 50, Time: 29 * time.Second}}},
+		{Record: 58 * time.Second},
+		{Record: 59 * time.Second, Splits: []Split{{Distance: 50, Time: 28 * time.Second}}},
+		{Splits: []Split{{Distance: 50, Time: 27 * time.Second}}},
+	}
+	assert.Equal(t, 2, Best(swims))
+	assert.Equal(t, -1, Best(swims[1:2]))
+	assert.Equal(t, -1, Best(nil))
+}
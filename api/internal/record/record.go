@@ -0,0 +1,201 @@
+// Package record 由已儲存的項目推算全國紀錄與大會紀錄的歷史。
+// 成績報告上列出的紀錄與我們的成績中平或破紀錄的成績，依比賽時間排列成每項紀錄的變化
+package record
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"aquascore/api/internal/crawler"
+)
+
+// Type 是紀錄的種類
+type Type string
+
+const (
+	TypeNational Type = "national" // 全國紀錄
+	TypeGames    Type = "games"    // 大會紀錄，每個競賽各自的紀錄
+)
+
+// Source 是紀錄歷史中一筆資料的來源
+type Source string
+
+const (
+	SourceReport Source = "report" // 成績報告上列出的紀錄，日期為第一次看到這個紀錄的比賽
+	SourceResult Source = "result" // 成績中平或破紀錄的成績
+)
+
+// Key 識別一項紀錄，大會紀錄另以競賽名稱區分
+type Key struct {
+	Type            Type
+	CompetitionName string // 大會紀錄所屬的競賽，全國紀錄為空
+	Gender          string
+	AgeGroup        string
+	PoolType        string
+	Event           crawler.Event // 不含賽次
+}
+
+// String 回傳紀錄的唯一識別，用於儲存時覆寫同一項紀錄
+func (k Key) String() string {
+	relay := ""
+	if k.Event.Relay {
+		relay = strconv.Itoa(k.Event.RelayCount) + "x"
+	}
+	return strings.Join([]string{
+		string(k.Type), k.CompetitionName, k.Gender, k.AgeGroup, k.PoolType,
+		relay + strconv.Itoa(k.Event.Distance), string(k.Event.Stroke),
+	}, "|")
+}
+
+// Entry 是紀錄歷史中的一筆，Athletes 與 Unit 只有由成績推算出的紀錄才有
+type Entry struct {
+	Record          time.Duration
+	Date            time.Time
+	Source          Source
+	RaceID          string
+	Year            string
+	CompetitionName string
+	EventName       string
+	Athletes        []string
+	Unit            string
+}
+
+// Record 是一項紀錄與其歷史，Entries 依時間由舊到新，每一筆都平或快於前一筆
+type Record struct {
+	Key     Key
+	Entries []Entry
+}
+
+// Current 回傳目前的紀錄，沒有任何歷史時回傳 nil
+func (r *Record) Current() *Entry {
+	if len(r.Entries) == 0 {
+		return nil
+	}
+	return &r.Entries[len(r.Entries)-1]
+}
+
+// Race 是已儲存的項目，ID 為 race 的 ID
+type Race struct {
+	ID string
+	*crawler.Race
+}
+
+// Build 依比賽時間依序檢視所有項目，推算每項紀錄的歷史。
+// 成績報告上的紀錄比目前已知的快時記為新的紀錄；成績平或快於比賽前的紀錄時記為選手創下的紀錄。
+// 還不知道紀錄時不會由成績推算，沒有任何歷史的紀錄不會回傳
+func Build(races []Race) []*Record {
+	races = slices.Clone(races)
+	slices.SortStableFunc(races, compareRace)
+
+	var records []*Record
+	byKey := make(map[string]*Record)
+	for _, race := range races {
+		if race.Event.Distance == 0 || race.Event.Stroke == "" {
+			continue
+		}
+		for _, t := range []Type{TypeNational, TypeGames} {
+			key := newKey(t, race)
+			r, ok := byKey[key.String()]
+			if !ok {
+				r = &Record{Key: key}
+				byKey[key.String()] = r
+				records = append(records, r)
+			}
+			r.observe(race, reported(t, race))
+		}
+	}
+	return slices.DeleteFunc(records, func(r *Record) bool { return len(r.Entries) == 0 })
+}
+
+// compareRace 依比賽時間排序，同一天的預賽排在決賽之前
+func compareRace(a, b Race) int {
+	if c := a.Time.Compare(b.Time); c != 0 {
+		return c
+	}
+	return cmp.Compare(roundOrder(a.Event.Round), roundOrder(b.Event.Round))
+}
+
+func roundOrder(round crawler.Round) int {
+	if round.IsQualifier() {
+		return 0
+	}
+	return 1
+}
+
+func newKey(t Type, race Race) Key {
+	event := race.Event
+	event.Round = ""
+	key := Key{
+		Type:     t,
+		Gender:   race.Gender,
+		AgeGroup: race.AgeGroup,
+		PoolType: race.PoolType,
+		Event:    event,
+	}
+	if t == TypeGames {
+		key.CompetitionName = race.CompetitionName
+	}
+	return key
+}
+
+func reported(t Type, race Race) time.Duration {
+	if t == TypeGames {
+		return race.GamesRecord
+	}
+	return race.NationalRecord
+}
+
+// observe 加入一個項目的紀錄：先比較成績報告上的紀錄 (比賽前的紀錄)，再找出平或破紀錄的成績
+func (r *Record) observe(race Race, reported time.Duration) {
+	current := r.Current()
+	if reported > 0 && (current == nil || reported < current.Record) {
+		r.Entries = append(r.Entries, newEntry(race, reported, SourceReport))
+		current = r.Current()
+	}
+	if current == nil {
+		return
+	}
+	var best *crawler.RaceResult
+	for _, result := range race.Results {
+		if !setsRecord(result, current.Record) {
+			continue
+		}
+		if best == nil || result.Record < best.Record {
+			best = result
+		}
+	}
+	if best == nil {
+		return
+	}
+	entry := newEntry(race, best.Record, SourceResult)
+	entry.Athletes = best.Name
+	entry.Unit = best.Unit
+	r.Entries = append(r.Entries, entry)
+}
+
+// setsRecord 回傳成績是否平或破紀錄，只計算正常完賽的成績
+func setsRecord(result *crawler.RaceResult, record time.Duration) bool {
+	if result.Record <= 0 || result.Record > record || len(result.Name) == 0 {
+		return false
+	}
+	status := result.Status
+	if status == "" {
+		status, _ = crawler.ParseResultStatus(result.Note, true)
+	}
+	return status == crawler.ResultStatusOK
+}
+
+func newEntry(race Race, record time.Duration, source Source) Entry {
+	return Entry{
+		Record:          record,
+		Date:            race.Time,
+		Source:          source,
+		RaceID:          race.ID,
+		Year:            race.Year,
+		CompetitionName: race.CompetitionName,
+		EventName:       race.EventName,
+	}
+}
@@ -0,0 +1,105 @@
+package record
+
+import (
+	"testing"
+	"time"
+
+	"aquascore/api/internal/crawler"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+func newRace(id, competition string, date time.Time, round crawler.Round, national, games time.Duration,
+	results ...*crawler.RaceResult,
+) Race {
+	return Race{ID: id, Race: &crawler.Race{
+		Year:            "113",
+		CompetitionName: competition,
+		EventName:       "11&12歲級女子組 200公尺自由式",
+		Gender:          "女子組",
+		AgeGroup:        "11&12歲級",
+		PoolType:        crawler.PoolTypeLongCourse,
+		Event:           crawler.Event{Distance: 200, Stroke: crawler.StrokeFreestyle, Round: round},
+		NationalRecord:  national,
+		GamesRecord:     games,
+		Time:            date,
+		Results:         results,
+	}}
+}
+
+func result(name string, record float64, status crawler.ResultStatus) *crawler.RaceResult {
+	return &crawler.RaceResult{Name: []string{name}, Unit: "大同高中", Record: seconds(record), Status: status}
+}
+
+func TestBuild(t *testing.T) {
+	spring := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	summer := time.Date(2024, 7, 20, 0, 0, 0, 0, time.UTC)
+	winter := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	races := []Race{
+		// 冬季賽的報告列出了在別的比賽創下的新紀錄
+		newRace("4", "全國冬季游泳錦標賽", winter, crawler.RoundTimedFinal, seconds(127.5), 0,
+			result("林小美", 128, crawler.ResultStatusOK)),
+		newRace("3", "全國分齡游泳錦標賽", summer, crawler.RoundFinal, seconds(130), seconds(133),
+			result("王小華", 129.5, crawler.ResultStatusOK),
+			result("陳小玉", 128.8, crawler.ResultStatusDQ)),
+		// 同一天的預賽先於決賽
+		newRace("2", "全國分齡游泳錦標賽", summer, crawler.RoundHeat, seconds(130), seconds(133),
+			result("王小華", 130, crawler.ResultStatusOK),
+			result("李小英", 132, crawler.ResultStatusOK)),
+		newRace("1", "全國春季游泳錦標賽", spring, crawler.RoundTimedFinal, seconds(130.5), 0,
+			result("王小華", 131, crawler.ResultStatusOK)),
+	}
+	records := Build(races)
+	require.Len(t, records, 2)
+
+	national := records[0]
+	assert.Equal(t, TypeNational, national.Key.Type)
+	assert.Empty(t, national.Key.CompetitionName)
+	assert.Equal(t, crawler.Event{Distance: 200, Stroke: crawler.StrokeFreestyle}, national.Key.Event)
+	assert.Equal(t, "national||女子組|11&12歲級|50m|200|freestyle", national.Key.String())
+	require.Len(t, national.Entries, 5)
+	assert.Equal(t, Entry{
+		Record: seconds(130.5), Date: spring, Source: SourceReport, RaceID: "1", Year: "113",
+		CompetitionName: "全國春季游泳錦標賽", EventName: "11&12歲級女子組 200公尺自由式",
+	}, national.Entries[0])
+	assert.Equal(t, seconds(130), national.Entries[1].Record)
+	assert.Equal(t, SourceReport, national.Entries[1].Source)
+	assert.Equal(t, "2", national.Entries[1].RaceID)
+	// 預賽平紀錄
+	assert.Equal(t, SourceResult, national.Entries[2].Source)
+	assert.Equal(t, []string{"王小華"}, national.Entries[2].Athletes)
+	assert.Equal(t, "大同高中", national.Entries[2].Unit)
+	assert.Equal(t, "2", national.Entries[2].RaceID)
+	// 決賽破紀錄，犯規的成績不算
+	assert.Equal(t, seconds(129.5), national.Entries[3].Record)
+	assert.Equal(t, "3", national.Entries[3].RaceID)
+	assert.Equal(t, seconds(127.5), national.Entries[4].Record)
+	assert.Equal(t, SourceReport, national.Entries[4].Source)
+	assert.Equal(t, seconds(127.5), national.Current().Record)
+
+	games := records[1]
+	assert.Equal(t, TypeGames, games.Key.Type)
+	assert.Equal(t, "全國分齡游泳錦標賽", games.Key.CompetitionName)
+	require.Len(t, games.Entries, 3)
+	assert.Equal(t, seconds(133), games.Entries[0].Record)
+	assert.Equal(t, seconds(130), games.Entries[1].Record)
+	assert.Equal(t, seconds(129.5), games.Entries[2].Record)
+}
+
+func TestBuild_unknownRecord(t *testing.T) {
+	date := time.Date(2024, 7, 20, 0, 0, 0, 0, time.UTC)
+	races := []Race{
+		newRace("1", "全國分齡游泳錦標賽", date, crawler.RoundTimedFinal, 0, 0,
+			result("王小華", 129.5, crawler.ResultStatusOK)),
+	}
+	// 沒有任何已知的紀錄時不由成績推算
+	assert.Empty(t, Build(races))
+
+	var empty Record
+	assert.Nil(t, empty.Current())
+}
@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron_invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			_, err := ParseCron(spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	loc := time.UTC
+	// 2025-03-14 是星期五
+	from := time.Date(2025, 3, 14, 10, 30, 15, 0, loc)
+	tests := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2025, 3, 14, 10, 31, 0, 0, loc)},
+		{"*/15 * * * *", time.Date(2025, 3, 14, 10, 45, 0, 0, loc)},
+		{"0 3 * * *", time.Date(2025, 3, 15, 3, 0, 0, 0, loc)},
+		{"@daily", time.Date(2025, 3, 15, 0, 0, 0, 0, loc)},
+		{"@hourly", time.Date(2025, 3, 14, 11, 0, 0, 0, loc)},
+		{"0 9-18/3 * * *", time.Date(2025, 3, 14, 12, 0, 0, 0, loc)},
+		{"30 2 * * 1", time.Date(2025, 3, 17, 2, 30, 0, 0, loc)},
+		{"0 0 * * 7", time.Date(2025, 3, 16, 0, 0, 0, 0, loc)},
+		{"0 0 1,15 * *", time.Date(2025, 3, 15, 0, 0, 0, 0, loc)},
+		{"0 0 1 1 *", time.Date(2026, 1, 1, 0, 0, 0, 0, loc)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, loc)},
+		// 日與星期都有限制時，任一符合即可
+		{"0 0 20 * 6", time.Date(2025, 3, 15, 0, 0, 0, 0, loc)},
+		// 以 * 開頭的間隔視為 *，需要同時符合：單數日的星期一
+		{"0 3 */2 * 1", time.Date(2025, 3, 17, 3, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := ParseCron(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, schedule.Next(from))
+		})
+	}
+}
+
+func TestSchedule_Next_never(t *testing.T) {
+	schedule, err := ParseCron("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
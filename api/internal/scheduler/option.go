@@ -0,0 +1,67 @@
+package scheduler
+
+import "time"
+
+const defaultLeaseTTL = 10 * time.Minute
+
+type options struct {
+	owner    string
+	locker   Locker
+	leaseTTL time.Duration
+	location *time.Location
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		leaseTTL: defaultLeaseTTL,
+		location: time.Local,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+type Option func(*options)
+
+// WithLocker 使用跨執行個體的租約鎖，同一個工作同時間只會有一個執行個體執行
+func WithLocker(locker Locker) Option {
+	return func(o *options) {
+		o.locker = locker
+	}
+}
+
+// WithOwner 設定租約持有者的識別，預設為 hostname 與 pid
+func WithOwner(owner string) Option {
+	return func(o *options) {
+		o.owner = owner
+	}
+}
+
+// WithLeaseTTL 設定租約的有效時間，工作執行期間會定期延長
+func WithLeaseTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		if ttl > 0 {
+			o.leaseTTL = ttl
+		}
+	}
+}
+
+// WithLocation 設定解讀 cron 表示式的時區
+func WithLocation(loc *time.Location) Option {
+	return func(o *options) {
+		if loc != nil {
+			o.location = loc
+		}
+	}
+}
+
+// TaskOption 是註冊工作時的設定
+type TaskOption func(*entry)
+
+// WithLease 設定工作的租約名稱，例如讓爬取同一個來源的多個工作共用一個租約，避免同時執行
+func WithLease(lease string) TaskOption {
+	return func(e *entry) {
+		e.lease = lease
+	}
+}
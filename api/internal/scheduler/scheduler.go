@@ -0,0 +1,228 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	releaseTimeout = 10 * time.Second
+	// renewDivisor 決定延長租約的頻率 (TTL 的幾分之一)
+	renewDivisor = 3
+)
+
+// Task 是排程執行的工作，ctx 會在關機或失去租約時取消
+type Task func(ctx context.Context) error
+
+// Locker 是跨執行個體的租約鎖，避免多個副本同時執行同一個工作
+type Locker interface {
+	// Acquire 取得或延長 name 的租約，被其他 owner 持有且尚未過期時回傳 false
+	Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	// Release 釋放 owner 持有的租約
+	Release(ctx context.Context, name, owner string) error
+}
+
+// Scheduler 依 cron 表示式執行已註冊的工作
+type Scheduler struct {
+	owner    string
+	locker   Locker
+	leaseTTL time.Duration
+	location *time.Location
+	entries  []*entry
+	wg       sync.WaitGroup
+
+	mu      sync.Mutex
+	running map[string]*atomic.Bool // 以租約名稱記錄本執行個體中是否有工作正在執行
+}
+
+type entry struct {
+	name     string
+	lease    string // 租約鎖的名稱，空白代表使用 name
+	schedule *Schedule
+	task     Task
+}
+
+// leaseName 回傳工作使用的租約名稱
+func (e *entry) leaseName() string {
+	if e.lease != "" {
+		return e.lease
+	}
+	return e.name
+}
+
+func New(opts ...Option) *Scheduler {
+	o := newOptions(opts...)
+	owner := o.owner
+	if owner == "" {
+		hostname, _ := os.Hostname()
+		owner = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+	return &Scheduler{
+		owner:    owner,
+		locker:   o.locker,
+		leaseTTL: o.leaseTTL,
+		location: o.location,
+		running:  make(map[string]*atomic.Bool),
+	}
+}
+
+// Add 註冊一個工作，預設以 name 作為租約鎖的名稱；使用同一個租約 (見 WithLease) 的工作不會同時執行
+func (s *Scheduler) Add(name, spec string, task Task, opts ...TaskOption) error {
+	if name == "" {
+		return errors.New("task name is empty")
+	}
+	if task == nil {
+		return fmt.Errorf("task %s is nil", name)
+	}
+	for _, e := range s.entries {
+		if e.name == name {
+			return fmt.Errorf("task %s already registered", name)
+		}
+	}
+	schedule, err := ParseCron(spec)
+	if err != nil {
+		return err
+	}
+	e := &entry{name: name, schedule: schedule, task: task}
+	for _, opt := range opts {
+		opt(e)
+	}
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+// Run 執行排程直到 ctx 取消，並等待執行中的工作結束後才返回
+func (s *Scheduler) Run(ctx context.Context) error {
+	if len(s.entries) == 0 {
+		return errors.New("no task registered")
+	}
+	var loops sync.WaitGroup
+	for _, e := range s.entries {
+		loops.Add(1)
+		go func() {
+			defer loops.Done()
+			s.loop(ctx, e)
+		}()
+	}
+	loops.Wait()
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	for {
+		next := e.schedule.Next(time.Now().In(s.location))
+		if next.IsZero() {
+			log.Printf("⚠️ 排程 %s 找不到下一次執行時間，停止排程", e.name)
+			return
+		}
+		log.Printf("⏰ 排程 %s 下一次執行時間: %s", e.name, next.Format(time.RFC3339))
+		if err := sleepUntil(ctx, next); err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.runEntry(ctx, e)
+		}()
+	}
+}
+
+// runEntry 執行一次工作，同一租約的工作 (包含上一次執行) 尚未結束或租約被其他執行個體持有時略過
+func (s *Scheduler) runEntry(ctx context.Context, e *entry) {
+	lease := e.leaseName()
+	running := s.leaseRunning(lease)
+	if !running.CompareAndSwap(false, true) {
+		log.Printf("⚠️ 排程 %s 上一次執行或同一租約 %s 的工作尚未結束，略過本次", e.name, lease)
+		return
+	}
+	defer running.Store(false)
+
+	taskCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if s.locker != nil {
+		// 租約由同一個 owner 持有時 Acquire 會成功，同一執行個體中的互斥由 leaseRunning 負責
+		ok, err := s.locker.Acquire(ctx, lease, s.owner, s.leaseTTL)
+		if err != nil {
+			log.Printf("❌ 排程 %s 取得租約失敗: %v", e.name, err)
+			return
+		}
+		if !ok {
+			log.Printf("⚠️ 排程 %s 正由其他執行個體執行，略過本次", e.name)
+			return
+		}
+		defer s.release(lease)
+		go s.renewLease(taskCtx, cancel, lease)
+	}
+
+	start := time.Now()
+	log.Printf("🚀 排程 %s 開始執行", e.name)
+	if err := e.task(taskCtx); err != nil {
+		log.Printf("❌ 排程 %s 執行失敗 (%s): %v", e.name, time.Since(start), err)
+		return
+	}
+	log.Printf("✅ 排程 %s 執行完成 (%s)", e.name, time.Since(start))
+}
+
+// leaseRunning 回傳租約在本執行個體中是否有工作正在執行的旗標
+func (s *Scheduler) leaseRunning(lease string) *atomic.Bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	running, ok := s.running[lease]
+	if !ok {
+		running = &atomic.Bool{}
+		s.running[lease] = running
+	}
+	return running
+}
+
+// renewLease 在工作執行期間定期延長租約，失去租約時取消工作
+func (s *Scheduler) renewLease(ctx context.Context, cancel context.CancelFunc, name string) {
+	ticker := time.NewTicker(s.leaseTTL / renewDivisor)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ok, err := s.locker.Acquire(ctx, name, s.owner, s.leaseTTL)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("⚠️ 排程 %s 延長租約失敗: %v", name, err)
+				}
+				continue
+			}
+			if !ok {
+				log.Printf("❌ 排程 %s 已失去租約，取消執行", name)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// release 使用獨立的 context，關機時 ctx 已取消仍要釋放租約
+func (s *Scheduler) release(name string) {
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+	if err := s.locker.Release(ctx, name, s.owner); err != nil {
+		log.Printf("⚠️ 排程 %s 釋放租約失敗: %v", name, err)
+	}
+}
+
+func sleepUntil(ctx context.Context, t time.Time) error {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
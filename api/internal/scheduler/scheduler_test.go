@@ -0,0 +1,184 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLocker struct {
+	mu       sync.Mutex
+	owners   map[string]string
+	err      error
+	released []string
+}
+
+func newMockLocker() *mockLocker {
+	return &mockLocker{owners: make(map[string]string)}
+}
+
+func (m *mockLocker) Acquire(_ context.Context, name, owner string, _ time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return false, m.err
+	}
+	if current, ok := m.owners[name]; ok && current != owner {
+		return false, nil
+	}
+	m.owners[name] = owner
+	return true, nil
+}
+
+func (m *mockLocker) Release(_ context.Context, name, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.owners[name] == owner {
+		delete(m.owners, name)
+	}
+	m.released = append(m.released, name)
+	return nil
+}
+
+func TestScheduler_Add(t *testing.T) {
+	s := New()
+	task := func(context.Context) error { return nil }
+	require.NoError(t, s.Add("crawl", "0 3 * * *", task))
+	assert.Error(t, s.Add("crawl", "0 4 * * *", task))
+	assert.Error(t, s.Add("other", "bad", task))
+	assert.Error(t, s.Add("", "0 3 * * *", task))
+	assert.Error(t, s.Add("nil", "0 3 * * *", nil))
+}
+
+func TestScheduler_runEntry_lease(t *testing.T) {
+	locker := newMockLocker()
+	locker.owners["crawl"] = "replica-b"
+	s := New(WithLocker(locker), WithOwner("replica-a"))
+
+	calls := 0
+	e := &entry{name: "crawl", task: func(context.Context) error {
+		calls++
+		return nil
+	}}
+	s.runEntry(t.Context(), e)
+	assert.Equal(t, 0, calls, "lease held by another replica")
+
+	delete(locker.owners, "crawl")
+	s.runEntry(t.Context(), e)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, []string{"crawl"}, locker.released)
+	assert.Empty(t, locker.owners)
+
+	locker.err = errors.New("mongo down")
+	s.runEntry(t.Context(), e)
+	assert.Equal(t, 1, calls, "skip when lease state is unknown")
+}
+
+func TestScheduler_runEntry_overlap(t *testing.T) {
+	s := New()
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	calls := 0
+	e := &entry{name: "crawl", task: func(context.Context) error {
+		calls++
+		close(started)
+		<-finish
+		return nil
+	}}
+	done := make(chan struct{})
+	go func() {
+		s.runEntry(t.Context(), e)
+		close(done)
+	}()
+	<-started
+	s.runEntry(t.Context(), e)
+	close(finish)
+	<-done
+	assert.Equal(t, 1, calls)
+}
+
+func TestScheduler_runEntry_sharedLease(t *testing.T) {
+	locker := newMockLocker()
+	s := New(WithLocker(locker), WithOwner("replica-a"))
+	task := func(context.Context) error { return nil }
+	require.NoError(t, s.Add("ctsa-current-year", "0 */6 * * *", task, WithLease("crawl:ctsa")))
+	require.NoError(t, s.Add("ctsa-recheck", "30 4 * * *", task, WithLease("crawl:ctsa")))
+	current, recheck := s.entries[0], s.entries[1]
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	calls := 0
+	current.task = func(context.Context) error {
+		calls++
+		close(started)
+		<-finish
+		return nil
+	}
+	recheck.task = func(context.Context) error {
+		calls++
+		return nil
+	}
+	done := make(chan struct{})
+	go func() {
+		s.runEntry(t.Context(), current)
+		close(done)
+	}()
+	<-started
+	// 同一個執行個體中，另一個爬取同一來源的工作不會同時執行
+	s.runEntry(t.Context(), recheck)
+	close(finish)
+	<-done
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, []string{"crawl:ctsa"}, locker.released)
+
+	// 其他執行個體持有同一來源的租約時也略過
+	locker.owners["crawl:ctsa"] = "replica-b"
+	s.runEntry(t.Context(), recheck)
+	assert.Equal(t, 1, calls)
+}
+
+func TestScheduler_Run_cancel(t *testing.T) {
+	s := New()
+	require.NoError(t, s.Add("crawl", "* * * * *", func(context.Context) error { return nil }))
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error)
+	go func() {
+		done <- s.Run(ctx)
+	}()
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop after cancel")
+	}
+}
+
+func TestScheduler_renewLease_lost(t *testing.T) {
+	locker := newMockLocker()
+	s := New(WithLocker(locker), WithOwner("replica-a"), WithLeaseTTL(30*time.Millisecond))
+
+	e := &entry{name: "crawl", task: func(ctx context.Context) error {
+		locker.mu.Lock()
+		locker.owners["crawl"] = "replica-b"
+		locker.mu.Unlock()
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	done := make(chan struct{})
+	go func() {
+		s.runEntry(t.Context(), e)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("task was not cancelled after losing the lease")
+	}
+	assert.Equal(t, "replica-b", locker.owners["crawl"])
+}
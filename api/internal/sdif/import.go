@@ -0,0 +1,373 @@
+package sdif
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"aquascore/api/internal/athlete"
+	"aquascore/api/internal/crawler"
+)
+
+const (
+	// SourceName 是由 SDIF 匯入的 Race 的來源名稱
+	SourceName = "sdif"
+
+	rocYearOffset  = 1911
+	relayLegCount  = 4 // SDIF 的接力固定為 4 棒
+	ageBoundLength = 2 // 年齡代碼前兩碼為下限、後兩碼為上限
+	ageCodeLength  = ageBoundLength * 2
+	maxLineLength  = 1024 * 1024
+)
+
+var (
+	strokes = map[string]crawler.Stroke{
+		"1": crawler.StrokeFreestyle,
+		"2": crawler.StrokeBackstroke,
+		"3": crawler.StrokeBreaststroke,
+		"4": crawler.StrokeButterfly,
+		"5": crawler.StrokeMedley,
+		"6": crawler.StrokeFreestyle,
+		"7": crawler.StrokeMedley,
+	}
+	genders = map[string]string{
+		"M": "男子組",
+		"F": "女子組",
+		"X": "混合組",
+	}
+	// courses 只對應公尺的水道，碼 (Y、2) 的成績沒有水道
+	courses = map[string]string{
+		"S": crawler.PoolTypeShortCourse,
+		"1": crawler.PoolTypeShortCourse,
+		"L": crawler.PoolTypeLongCourse,
+		"3": crawler.PoolTypeLongCourse,
+	}
+	timeStatuses = map[string]crawler.ResultStatus{
+		timeNoSwim:       crawler.ResultStatusDNS,
+		timeDidNotFinish: crawler.ResultStatusDNF,
+		timeDisqualified: crawler.ResultStatusDQ,
+		timeScratch:      crawler.ResultStatusScratch,
+	}
+
+	competitionYearReg = regexp.MustCompile(`^(\d+)年(.*)`)
+)
+
+// ImportedRace 是由 SDIF 轉換出的一個項目，Key 在同一份檔案中唯一 ("項目編號/P" 為預賽，"項目編號/F" 為決賽)
+type ImportedRace struct {
+	Key  string
+	Race *crawler.Race
+}
+
+// file 是讀入的 SDIF 檔案
+type file struct {
+	meet    meet
+	teams   map[string]string // 隊伍代碼 => 名稱
+	entries []*entry
+}
+
+// Races 讀取 SDIF 檔案，每個項目的預賽與決賽 (或計時決賽) 各為一個 Race。
+// 無法解析的成績或分段記錄會略過並記錄在 Race.Unparsed
+func Races(r io.Reader) ([]ImportedRace, error) {
+	f, err := read(r)
+	if err != nil {
+		return nil, err
+	}
+	return f.races(), nil
+}
+
+func read(r io.Reader) (*file, error) {
+	f := &file{teams: make(map[string]string)}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, recordLength*2), maxLineLength)
+	var currentTeam string
+	var last *entry
+	for row := 1; scanner.Scan(); row++ {
+		text := strings.TrimPrefix(scanner.Text(), "\uFEFF")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		l := newLine(text)
+		switch l.kind() {
+		case recordMeet:
+			f.meet = parseMeet(l)
+		case recordTeam:
+			t := parseTeam(l)
+			f.teams[t.code] = t.name
+			currentTeam = t.code
+		case recordIndividual:
+			last = parseIndividual(l, row, currentTeam)
+			f.entries = append(f.entries, last)
+		case recordRelay:
+			last = parseRelay(l, row)
+			f.entries = append(f.entries, last)
+		case recordRelayName:
+			if last != nil && last.relay {
+				last.members = append(last.members, parseRelayName(l))
+			}
+		case recordSplits:
+			if last == nil {
+				continue
+			}
+			split, prelim, err := parseSplits(l)
+			if err != nil {
+				last.invalid = append(last.invalid,
+					crawler.UnparsedRow{Row: row, Text: strings.TrimSpace(text), Reason: err.Error()})
+				continue
+			}
+			if last.splits == nil {
+				last.splits = make(map[bool][]splitRecord)
+			}
+			last.splits[prelim] = append(last.splits[prelim], split)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read sdif fail: %w", err)
+	}
+	return f, nil
+}
+
+// races 依項目編號分組，有預賽成績的項目分成預賽與決賽，否則為計時決賽
+func (f *file) races() []ImportedRace {
+	var numbers []string
+	byNumber := make(map[string][]*entry)
+	for _, e := range f.entries {
+		if _, ok := byNumber[e.event.number]; !ok {
+			numbers = append(numbers, e.event.number)
+		}
+		byNumber[e.event.number] = append(byNumber[e.event.number], e)
+	}
+
+	var races []ImportedRace
+	for _, number := range numbers {
+		entries := byNumber[number]
+		hasPrelims := slices.ContainsFunc(entries, func(e *entry) bool { return e.prelim.time != "" })
+		var prelimRace, finalRace *crawler.Race
+		for _, e := range entries {
+			if e.prelim.time != "" {
+				if prelimRace == nil {
+					prelimRace = f.newRace(e, crawler.RoundHeat)
+					races = append(races, ImportedRace{Key: number + "/P", Race: prelimRace})
+				}
+				f.addResult(prelimRace, e, true)
+			}
+			if e.final.time != "" || e.prelim.time == "" {
+				if finalRace == nil {
+					round := crawler.RoundTimedFinal
+					if hasPrelims {
+						round = crawler.RoundFinal
+					}
+					finalRace = f.newRace(e, round)
+					races = append(races, ImportedRace{Key: number + "/F", Race: finalRace})
+				}
+				f.addResult(finalRace, e, false)
+			}
+		}
+	}
+	return races
+}
+
+func (f *file) newRace(e *entry, round crawler.Round) *crawler.Race {
+	event := crawler.Event{
+		Distance: e.event.distance,
+		Stroke:   strokes[e.event.stroke],
+		Round:    round,
+	}
+	if e.relay {
+		event.Relay = true
+		event.RelayCount = relayLegCount
+		event.Distance = e.event.distance / relayLegCount
+	}
+	date := e.event.date
+	if date.IsZero() {
+		date = f.meet.start
+	}
+	course := e.final.course
+	if round == crawler.RoundHeat {
+		course = e.prelim.course
+	}
+	if course == "" {
+		course = f.meet.course
+	}
+	ageGroup := crawler.AgeGroupLabel(ageRange(e.event.ageCode))
+	gender := genders[e.event.sex]
+	eventType := event.Label()
+	year, competition := splitCompetition(f.meet.name)
+	if year == "" && !date.IsZero() {
+		year = strconv.Itoa(date.Year() - rocYearOffset)
+	}
+	return &crawler.Race{
+		Source:          SourceName,
+		Venue:           f.meet.city,
+		Year:            year,
+		Type:            round.Label(),
+		CompetitionName: competition,
+		Gender:          gender,
+		PoolType:        courses[course],
+		AgeGroup:        ageGroup,
+		EventType:       eventType,
+		EventName:       joinNonEmpty(ageGroup+gender, eventType, round.Label()),
+		Event:           event,
+		Time:            date,
+	}
+}
+
+// addResult 將成績記錄的預賽 (prelim) 或決賽成績加入 race，分段記錄的錯誤一併記在 race
+func (f *file) addResult(race *crawler.Race, e *entry, prelim bool) {
+	result, err := f.convertResult(race.Event, e, prelim)
+	if err != nil {
+		race.Unparsed = append(race.Unparsed, crawler.UnparsedRow{Row: e.row, Text: e.text, Reason: err.Error()})
+	} else {
+		race.Results = append(race.Results, result)
+	}
+	if !prelim || e.final.time == "" {
+		race.Unparsed = append(race.Unparsed, e.invalid...)
+	}
+}
+
+func (f *file) convertResult(event crawler.Event, e *entry, prelim bool) (*crawler.RaceResult, error) {
+	s := e.final
+	if prelim {
+		s = e.prelim
+	}
+	record, status, err := parseTime(s.time)
+	if err != nil {
+		return nil, err
+	}
+	unit := f.teams[e.team]
+	if unit == "" {
+		unit = e.team
+	}
+	result := &crawler.RaceResult{
+		Unit:   unit,
+		Record: record,
+		Rank:   int32(s.place),
+		Status: status,
+	}
+	if !prelim {
+		result.Score = int32(e.points)
+	}
+	splits := e.splitTimes(prelim)
+	if len(splits) > 0 && record > 0 && splits[len(splits)-1].Distance < e.event.distance {
+		splits = append(splits, crawler.Split{Distance: e.event.distance, Time: record})
+	}
+	result.Splits = crawler.NormalizeSplits(splits, record)
+
+	if !e.relay {
+		result.Name = []string{swimmerName(e.name)}
+		return result, nil
+	}
+	result.Team = unit
+	if e.name != "" && e.name != "A" {
+		result.Team += e.name
+	}
+	members := e.legMembers(prelim)
+	for _, m := range members {
+		result.Name = append(result.Name, swimmerName(m.name))
+	}
+	result.Legs = crawler.RelayLegs(event, result.Name)
+	for i, m := range members {
+		if split, err := crawler.ParseSwimTime(m.legTime); err == nil {
+			result.Legs[i].Split = split
+		}
+	}
+	crawler.ApplyLegSplits(event, result)
+	return result, nil
+}
+
+// splitTimes 將預賽或決賽的 G0 記錄轉成依距離排列的分段，無法解析的時間略過
+func (e *entry) splitTimes(prelim bool) []crawler.Split {
+	records := slices.Clone(e.splits[prelim])
+	slices.SortFunc(records, func(a, b splitRecord) int { return a.sequence - b.sequence })
+	var splits []crawler.Split
+	var total time.Duration
+	for _, r := range records {
+		for i, text := range r.times {
+			t, _, err := parseTime(text)
+			if err != nil || t <= 0 {
+				continue
+			}
+			if !r.cumulative {
+				total += t
+				t = total
+			}
+			distance := ((r.sequence-1)*splitsPerG0 + i + 1) * r.distance
+			splits = append(splits, crawler.Split{Distance: distance, Time: t})
+		}
+	}
+	return splits
+}
+
+// legMembers 回傳預賽或決賽下水的接力選手，依棒次排列
+func (e *entry) legMembers(prelim bool) []member {
+	order := func(m member) string {
+		if prelim {
+			return m.prelimOrder
+		}
+		return m.finalOrder
+	}
+	var members []member
+	for _, m := range e.members {
+		if n, err := strconv.Atoi(order(m)); err == nil && n >= 1 && n <= relayLegCount {
+			members = append(members, m)
+		}
+	}
+	slices.SortStableFunc(members, func(a, b member) int { return strings.Compare(order(a), order(b)) })
+	return members
+}
+
+// parseTime 解析時間欄位，NS、DQ 等代碼回傳對應的成績狀態
+func parseTime(text string) (time.Duration, crawler.ResultStatus, error) {
+	if status, ok := timeStatuses[text]; ok {
+		return 0, status, nil
+	}
+	if text == "" || text == timeNoTime {
+		return 0, crawler.ResultStatusDNS, nil
+	}
+	record, err := crawler.ParseSwimTime(text)
+	if err != nil {
+		return 0, "", err
+	}
+	return record, crawler.ResultStatusOK, nil
+}
+
+// ageRange 解析年齡代碼，例如 "1112" 為 11~12，"UN10" 為 10 歲以下，"15OV" 為 15 歲以上，-1 代表不限
+func ageRange(code string) (minAge, maxAge int) {
+	minAge, maxAge = -1, -1
+	if len(code) != ageCodeLength {
+		return minAge, maxAge
+	}
+	if n, err := strconv.Atoi(code[:ageBoundLength]); err == nil {
+		minAge = n
+	}
+	if n, err := strconv.Atoi(code[ageBoundLength:]); err == nil {
+		maxAge = n
+	}
+	return minAge, maxAge
+}
+
+// swimmerName 將 SDIF 的 "姓, 名 中間名縮寫" 轉成選手姓名，見 athlete.JoinName
+func swimmerName(name string) string {
+	last, first, ok := strings.Cut(name, ",")
+	if !ok {
+		return strings.TrimSpace(name)
+	}
+	return athlete.JoinName(last, first)
+}
+
+// splitCompetition 將 "113年全國分齡游泳錦標賽" 分成民國年與競賽名稱，沒有年份時 year 為空
+func splitCompetition(name string) (year, competition string) {
+	name = strings.TrimSpace(name)
+	if matches := competitionYearReg.FindStringSubmatch(name); matches != nil {
+		return matches[1], strings.TrimSpace(matches[2])
+	}
+	return "", name
+}
+
+func joinNonEmpty(parts ...string) string {
+	return strings.Join(slices.DeleteFunc(parts, func(s string) bool { return s == "" }), " ")
+}
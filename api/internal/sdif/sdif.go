@@ -0,0 +1,279 @@
+// Package sdif 讀取 Hy-Tek Meet Manager 匯出的 SDIF v3 (.cl2/.sd3) 固定欄寬成績檔，
+// 並轉成 crawler.Race
+package sdif
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"aquascore/api/internal/crawler"
+)
+
+// 使用到的記錄類型，其他記錄 (A0、B2、C2、D1、D3、Z0...) 略過
+const (
+	recordMeet       = "B1"
+	recordTeam       = "C1"
+	recordIndividual = "D0"
+	recordRelay      = "E0"
+	recordRelayName  = "F0"
+	recordSplits     = "G0"
+
+	recordLength = 160
+	splitsPerG0  = 10
+	dateLayout   = "01022006" // MMDDYYYY
+)
+
+// 時間欄位中代表沒有成績的代碼
+const (
+	timeNoTime       = "NT"
+	timeNoSwim       = "NS"
+	timeDidNotFinish = "DNF"
+	timeDisqualified = "DQ"
+	timeScratch      = "SCR"
+)
+
+// column 是記錄中的一個欄位，start 依 SDIF 規格從 1 起算
+type column struct {
+	start, length int
+}
+
+// 各記錄使用到的欄位
+var (
+	meetName   = column{12, 30}
+	meetCity   = column{86, 20}
+	meetStart  = column{122, 8}
+	meetCourse = column{150, 1}
+
+	teamCode = column{12, 6}
+	teamName = column{18, 30}
+
+	individualName        = column{12, 28}
+	individualEventSex    = column{67, 1}
+	individualDistance    = column{68, 4}
+	individualStroke      = column{72, 1}
+	individualEventNumber = column{73, 4}
+	individualAgeCode     = column{77, 4}
+	individualDate        = column{81, 8}
+	individualPrelimTime  = column{98, 8}
+	individualPrelimCrs   = column{106, 1}
+	individualFinalTime   = column{116, 8}
+	individualFinalCrs    = column{124, 1}
+	individualPrelimPlace = column{133, 3}
+	individualFinalPlace  = column{136, 3}
+	individualPoints      = column{139, 4}
+
+	relayName        = column{12, 1}
+	relayTeamCode    = column{13, 6}
+	relayEventSex    = column{21, 1}
+	relayDistance    = column{22, 4}
+	relayStroke      = column{26, 1}
+	relayEventNumber = column{27, 4}
+	relayAgeCode     = column{31, 4}
+	relayDate        = column{38, 8}
+	relayPrelimTime  = column{55, 8}
+	relayPrelimCrs   = column{63, 1}
+	relayFinalTime   = column{73, 8}
+	relayFinalCrs    = column{81, 1}
+	relayPrelimPlace = column{90, 3}
+	relayFinalPlace  = column{93, 3}
+	relayPoints      = column{96, 4}
+
+	memberName        = column{23, 28}
+	memberPrelimOrder = column{77, 1}
+	memberFinalOrder  = column{79, 1}
+	memberLegTime     = column{80, 8}
+
+	splitSequence = column{56, 1}
+	splitTotal    = column{57, 2}
+	splitDistance = column{59, 4}
+	splitCode     = column{63, 1}
+	splitFirst    = column{64, 8} // 之後每 8 個字元一個時間，共 10 個
+	splitRound    = column{144, 1}
+)
+
+// line 是一筆記錄，欄位以字元計算 (UTF-8 的中文姓名也佔一個字元)
+type line []rune
+
+func newLine(text string) line {
+	l := []rune(strings.TrimRight(text, "\r\n"))
+	for len(l) < recordLength {
+		l = append(l, ' ')
+	}
+	return l
+}
+
+func (l line) kind() string {
+	return string(l[:2])
+}
+
+// field 回傳欄位去掉空白後的文字
+func (l line) field(c column) string {
+	return strings.TrimSpace(string(l[c.start-1 : c.start-1+c.length]))
+}
+
+func (l line) int(c column) int {
+	n, _ := strconv.Atoi(l.field(c))
+	return n
+}
+
+func (l line) date(c column) time.Time {
+	t, _ := time.Parse(dateLayout, l.field(c))
+	return t
+}
+
+// meet 是 B1 比賽記錄
+type meet struct {
+	name   string
+	city   string
+	start  time.Time
+	course string
+}
+
+func parseMeet(l line) meet {
+	return meet{
+		name:   l.field(meetName),
+		city:   l.field(meetCity),
+		start:  l.date(meetStart),
+		course: l.field(meetCourse),
+	}
+}
+
+// team 是 C1 隊伍記錄
+type team struct {
+	code string
+	name string
+}
+
+func parseTeam(l line) team {
+	return team{code: l.field(teamCode), name: l.field(teamName)}
+}
+
+// eventInfo 是個人 (D0) 與接力 (E0) 記錄共同的項目資料
+type eventInfo struct {
+	number   string
+	sex      string // M、F、X
+	distance int    // 總距離，接力為全隊的距離
+	stroke   string // 1 自由式、2 仰式、3 蛙式、4 蝶式、5 混合式、6 自由式接力、7 混合式接力
+	ageCode  string // 例如 "1112"、"UN10"、"15OV"、"UNOV"
+	date     time.Time
+}
+
+// swim 是一個項目中預賽或決賽的成績欄位
+type swim struct {
+	time   string
+	course string
+	place  int
+}
+
+// entry 是一筆個人 (D0) 或接力 (E0) 成績記錄
+type entry struct {
+	row     int    // 在檔案中的行數 (從 1 開始)
+	text    string // 原始記錄
+	event   eventInfo
+	name    string // 個人為選手姓名 ("姓, 名")，接力為隊伍代號 (A、B...)
+	team    string // 隊伍代碼，個人成績為前一筆 C1 記錄的隊伍
+	prelim  swim
+	final   swim
+	points  int
+	relay   bool
+	members []member
+	splits  map[bool][]splitRecord // 預賽 (true) 與決賽 (false) 的分段
+	invalid []crawler.UnparsedRow  // 無法解析的分段記錄
+}
+
+// member 是接力的一位選手 (F0)
+type member struct {
+	name        string
+	prelimOrder string // 棒次，"0" 代表沒有下水，"A" 為候補
+	finalOrder  string
+	legTime     string
+}
+
+// splitRecord 是一筆 G0 分段記錄
+type splitRecord struct {
+	sequence   int
+	distance   int
+	cumulative bool // C 為累計時間，I 為每段時間
+	times      []string
+}
+
+func parseIndividual(l line, row int, teamCode string) *entry {
+	return &entry{
+		row:  row,
+		text: strings.TrimSpace(string(l)),
+		event: eventInfo{
+			sex:      l.field(individualEventSex),
+			distance: l.int(individualDistance),
+			stroke:   l.field(individualStroke),
+			number:   l.field(individualEventNumber),
+			ageCode:  l.field(individualAgeCode),
+			date:     l.date(individualDate),
+		},
+		name: l.field(individualName),
+		team: teamCode,
+		prelim: swim{
+			time:   l.field(individualPrelimTime),
+			course: l.field(individualPrelimCrs),
+			place:  l.int(individualPrelimPlace),
+		},
+		final: swim{
+			time:   l.field(individualFinalTime),
+			course: l.field(individualFinalCrs),
+			place:  l.int(individualFinalPlace),
+		},
+		points: l.int(individualPoints),
+	}
+}
+
+func parseRelay(l line, row int) *entry {
+	return &entry{
+		row:  row,
+		text: strings.TrimSpace(string(l)),
+		event: eventInfo{
+			sex:      l.field(relayEventSex),
+			distance: l.int(relayDistance),
+			stroke:   l.field(relayStroke),
+			number:   l.field(relayEventNumber),
+			ageCode:  l.field(relayAgeCode),
+			date:     l.date(relayDate),
+		},
+		name:   l.field(relayName),
+		team:   l.field(relayTeamCode),
+		prelim: swim{time: l.field(relayPrelimTime), course: l.field(relayPrelimCrs), place: l.int(relayPrelimPlace)},
+		final:  swim{time: l.field(relayFinalTime), course: l.field(relayFinalCrs), place: l.int(relayFinalPlace)},
+		points: l.int(relayPoints),
+		relay:  true,
+	}
+}
+
+func parseRelayName(l line) member {
+	return member{
+		name:        l.field(memberName),
+		prelimOrder: l.field(memberPrelimOrder),
+		finalOrder:  l.field(memberFinalOrder),
+		legTime:     l.field(memberLegTime),
+	}
+}
+
+// parseSplits 解析 G0 記錄，prelim 表示是預賽的分段
+func parseSplits(l line) (split splitRecord, prelim bool, err error) {
+	split = splitRecord{
+		sequence:   l.int(splitSequence),
+		distance:   l.int(splitDistance),
+		cumulative: l.field(splitCode) != "I",
+	}
+	if split.distance <= 0 || split.sequence <= 0 {
+		return split, false, fmt.Errorf("分段記錄格式錯誤: 距離 %q、序號 %q", l.field(splitDistance), l.field(splitSequence))
+	}
+	total := l.int(splitTotal)
+	for i := range splitsPerG0 {
+		if (split.sequence-1)*splitsPerG0+i >= total {
+			break
+		}
+		c := column{splitFirst.start + i*splitFirst.length, splitFirst.length}
+		split.times = append(split.times, l.field(c))
+	}
+	return split, l.field(splitRound) == "P", nil
+}
@@ -0,0 +1,162 @@
+package sdif
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"aquascore/api/internal/crawler"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readFixture(t *testing.T) map[string]*crawler.Race {
+	t.Helper()
+	file, err := os.Open("test_file/meet.cl2")
+	require.NoError(t, err)
+	defer file.Close()
+	imported, err := Races(file)
+	require.NoError(t, err)
+	races := make(map[string]*crawler.Race, len(imported))
+	for _, r := range imported {
+		races[r.Key] = r.Race
+	}
+	return races
+}
+
+func swimTime(t *testing.T, text string) time.Duration {
+	t.Helper()
+	d, err := crawler.ParseSwimTime(text)
+	require.NoError(t, err)
+	return d
+}
+
+func TestRaces(t *testing.T) {
+	races := readFixture(t)
+	require.Len(t, races, 4)
+
+	race := races["1/P"]
+	require.NotNil(t, race)
+	assert.Equal(t, SourceName, race.Source)
+	assert.Equal(t, "113", race.Year)
+	assert.Equal(t, "全國分齡游泳錦標賽", race.CompetitionName)
+	assert.Equal(t, "臺北市", race.Venue)
+	assert.Equal(t, crawler.PoolTypeLongCourse, race.PoolType)
+	assert.Equal(t, "男子組", race.Gender)
+	assert.Equal(t, "11&12歲級", race.AgeGroup)
+	assert.Equal(t, "100公尺自由式", race.EventType)
+	assert.Equal(t, "預賽", race.Type)
+	assert.Equal(t, "11&12歲級男子組 100公尺自由式 預賽", race.EventName)
+	assert.Equal(t, crawler.Event{Distance: 100, Stroke: crawler.StrokeFreestyle, Round: crawler.RoundHeat}, race.Event)
+	assert.Equal(t, time.Date(2024, 7, 20, 0, 0, 0, 0, time.UTC), race.Time)
+	require.Len(t, race.Results, 4)
+
+	first := race.Results[0]
+	assert.Equal(t, []string{"王小明"}, first.Name)
+	assert.Equal(t, "臺北市立大同高中", first.Unit)
+	assert.Equal(t, swimTime(t, "1:03.10"), first.Record)
+	assert.Equal(t, int32(1), first.Rank)
+	assert.Zero(t, first.Score)
+	assert.Equal(t, []crawler.Split{
+		{Distance: 50, Time: swimTime(t, "30.20")},
+		{Distance: 100, Time: swimTime(t, "1:03.10")},
+	}, first.Splits)
+
+	dq := race.Results[2]
+	assert.Equal(t, []string{"陳志強"}, dq.Name)
+	assert.Equal(t, crawler.ResultStatusDQ, dq.Status)
+	assert.Zero(t, dq.Record)
+
+	// 英文姓名的 "姓, 名" 轉成 "名 姓"，隊伍名稱由 C1 記錄而來
+	assert.Equal(t, []string{"Wei Ting Chen"}, race.Results[3].Name)
+	assert.Equal(t, "Kaohsiung Swim Club", race.Results[3].Unit)
+
+	final := races["1/F"]
+	require.NotNil(t, final)
+	assert.Equal(t, crawler.RoundFinal, final.Event.Round)
+	assert.Equal(t, "11&12歲級男子組 100公尺自由式 決賽", final.EventName)
+	// 沒有進入決賽的選手只出現在預賽
+	require.Len(t, final.Results, 2)
+	assert.Equal(t, swimTime(t, "1:02.35"), final.Results[0].Record)
+	assert.Equal(t, int32(9), final.Results[0].Score)
+	assert.Equal(t, []crawler.Split{
+		{Distance: 50, Time: swimTime(t, "29.80")},
+		{Distance: 100, Time: swimTime(t, "1:02.35")},
+	}, final.Results[0].Splits)
+	assert.Equal(t, int32(2), final.Results[1].Rank)
+}
+
+func TestRaces_timedFinal(t *testing.T) {
+	race := readFixture(t)["2/F"]
+	require.NotNil(t, race)
+	assert.Equal(t, crawler.RoundTimedFinal, race.Event.Round)
+	assert.Empty(t, race.AgeGroup)
+	assert.Equal(t, "男子組 200公尺仰式 計時決賽", race.EventName)
+	require.Len(t, race.Results, 1)
+	// I 代表每段時間，轉成累計時間
+	assert.Equal(t, []crawler.Split{
+		{Distance: 50, Time: swimTime(t, "35.00")},
+		{Distance: 100, Time: swimTime(t, "1:13.00")},
+		{Distance: 150, Time: swimTime(t, "1:51.50")},
+		{Distance: 200, Time: swimTime(t, "2:30.55")},
+	}, race.Results[0].Splits)
+
+	require.Len(t, race.Unparsed, 2)
+	assert.Equal(t, 11, race.Unparsed[0].Row)
+	assert.True(t, strings.HasPrefix(race.Unparsed[0].Text, "D01"))
+	assert.Contains(t, race.Unparsed[0].Reason, "2:3x.00")
+	assert.Equal(t, 12, race.Unparsed[1].Row)
+	assert.Contains(t, race.Unparsed[1].Reason, "分段記錄格式錯誤")
+}
+
+func TestRaces_relay(t *testing.T) {
+	race := readFixture(t)["3/F"]
+	require.NotNil(t, race)
+	assert.Equal(t, crawler.Event{
+		Distance: 50, Stroke: crawler.StrokeMedley, Relay: true, RelayCount: 4, Round: crawler.RoundTimedFinal,
+	}, race.Event)
+	assert.Equal(t, "4×50公尺混合式接力", race.EventType)
+	assert.Equal(t, time.Date(2024, 7, 21, 0, 0, 0, 0, time.UTC), race.Time)
+	require.Len(t, race.Results, 2)
+
+	relay := race.Results[0]
+	assert.Equal(t, "Kaohsiung Swim Club", relay.Team)
+	assert.Equal(t, int32(18), relay.Score)
+	// 依棒次排列，候補選手不列入
+	assert.Equal(t, []string{"Kai Wu", "Bo Lin", "Yu Huang", "Ming Tsai"}, relay.Name)
+	assert.Equal(t, []crawler.RelayLeg{
+		{Swimmer: "Kai Wu", Stroke: crawler.StrokeBackstroke, Split: swimTime(t, "33.10")},
+		{Swimmer: "Bo Lin", Stroke: crawler.StrokeBreaststroke, Split: swimTime(t, "37.10")},
+		{Swimmer: "Yu Huang", Stroke: crawler.StrokeButterfly, Split: swimTime(t, "30.80")},
+		// 沒有棒次時間時由分段計算
+		{Swimmer: "Ming Tsai", Stroke: crawler.StrokeFreestyle, Split: swimTime(t, "29.40")},
+	}, relay.Legs)
+	assert.Len(t, relay.Splits, 4)
+
+	ns := race.Results[1]
+	assert.Equal(t, "Kaohsiung Swim ClubB", ns.Team)
+	assert.Equal(t, crawler.ResultStatusDNS, ns.Status)
+	assert.Empty(t, ns.Legs)
+}
+
+func TestAgeRange(t *testing.T) {
+	tests := []struct {
+		code     string
+		min, max int
+	}{
+		{"1112", 11, 12},
+		{"UN10", -1, 10},
+		{"15OV", 15, -1},
+		{"UNOV", -1, -1},
+		{"", -1, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			minAge, maxAge := ageRange(tt.code)
+			assert.Equal(t, tt.min, minAge)
+			assert.Equal(t, tt.max, maxAge)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Competition 是一場比賽的基本資料、項目列表與參賽人數
+type Competition struct {
+	ID           string                  `json:"id"`
+	Name         string                  `json:"name"`
+	Year         string                  `json:"year"`
+	ADYear       int                     `json:"ad_year"`
+	Source       string                  `json:"source"`
+	SourceID     string                  `json:"source_id,omitempty"`
+	StartDate    time.Time               `json:"start_date"`
+	EndDate      time.Time               `json:"end_date"`
+	Organizer    string                  `json:"organizer"`
+	Venue        string                  `json:"venue,omitempty"`
+	PoolType     string                  `json:"pool_type"`
+	Participants CompetitionParticipants `json:"participants"`
+	Events       []CompetitionEvent      `json:"events"`
+}
+
+// CompetitionParticipants 是比賽的參賽人數，接力的每一棒各算一位選手
+type CompetitionParticipants struct {
+	Athletes int `json:"athletes"`
+	Teams    int `json:"teams"`
+	Results  int `json:"results"`
+}
+
+// CompetitionEvent 是比賽中的一個項目
+type CompetitionEvent struct {
+	RaceID    string    `json:"race_id"`
+	EventName string    `json:"event_name"`
+	EventType string    `json:"event_type"`
+	Gender    string    `json:"gender"`
+	AgeGroup  string    `json:"age_group"`
+	Distance  int       `json:"distance"`
+	Stroke    string    `json:"stroke"`
+	Relay     bool      `json:"relay"`
+	Round     string    `json:"round"`
+	EventDate time.Time `json:"event_date"`
+	Results   int       `json:"results"`
+}
+
+// GetCompetition handles the GET /competitions/:competition_id endpoint.
+func (h *apiHandler) GetCompetition(c *gin.Context) {
+	id, err := bson.ObjectIDFromHex(c.Param("competition_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid competition_id"})
+		return
+	}
+	ctx := c.Request.Context()
+	competition, err := h.competitionStore.FindCompetition(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve competition"})
+		return
+	}
+	if competition == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "competition not found"})
+		return
+	}
+	events, err := h.competitionStore.GetCompetitionEvents(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve competition events"})
+		return
+	}
+	participants, err := h.competitionStore.GetCompetitionParticipants(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve competition participants"})
+		return
+	}
+	c.JSON(http.StatusOK, mapCompetition(competition, events, participants))
+}
+
+func mapCompetition(
+	competition *models.Competition,
+	events []*models.AggrCompetitionEvent,
+	participants *models.AggrCompetitionParticipants,
+) Competition {
+	output := Competition{
+		ID:        competition.ID.Hex(),
+		Name:      competition.Name,
+		Year:      competition.Year,
+		ADYear:    competition.ADYear,
+		Source:    competition.Source,
+		SourceID:  competition.SourceID,
+		StartDate: competition.StartDate,
+		EndDate:   competition.EndDate,
+		Organizer: competition.Organizer,
+		Venue:     competition.Venue,
+		PoolType:  competition.PoolType,
+		Participants: CompetitionParticipants{
+			Athletes: participants.Athletes,
+			Teams:    participants.Teams,
+			Results:  participants.Results,
+		},
+		Events: make([]CompetitionEvent, len(events)),
+	}
+	for i, event := range events {
+		output.Events[i] = CompetitionEvent{
+			RaceID:    event.RaceID,
+			EventName: event.EventName,
+			EventType: event.EventType,
+			Gender:    event.Gender,
+			AgeGroup:  event.AgeGroup,
+			Distance:  event.Distance,
+			Stroke:    event.Stroke,
+			Relay:     event.Relay,
+			Round:     event.Round,
+			EventDate: event.EventDate,
+			Results:   event.Results,
+		}
+	}
+	return output
+}
@@ -1,48 +1,140 @@
 package server
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"aquascore/api/internal/crawler"
 	"aquascore/api/internal/db/mongo"
 	"aquascore/api/internal/db/mongo/models"
 
 	analysisv1 "buf.build/gen/go/aqua/analysis/protocolbuffers/go/analysis/v1"
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // APIHandler holds the dependencies for API handlers.
 type apiHandler struct {
-	raceStore  mongo.RaceStore
-	grpcClient GrpcClient
+	athleteStore     mongo.AthleteStore
+	competitionStore mongo.CompetitionStore
+	raceStore        mongo.RaceStore
+	raceChangeStore  mongo.RaceChangeStore
+	recordStore      mongo.RecordStore
+	athleteBestStore mongo.AthleteBestStore
+	teamStore        mongo.TeamStore
+	grpcClient       GrpcClient
 }
 
 type AthleteRaceResult struct {
-	RaceID    string  `json:"race_id"`
-	EventName string  `json:"event_name"`
-	Record    float64 `json:"record"`
-	Rank      int     `json:"rank"`
-	Score     int     `json:"score"`
-	Note      string  `json:"note"`
+	RaceID       string     `json:"race_id"`
+	EventName    string     `json:"event_name"`
+	Distance     int        `json:"distance"`
+	Stroke       string     `json:"stroke"`
+	Relay        bool       `json:"relay"`
+	Round        string     `json:"round"`
+	Record       float64    `json:"record"`
+	Rank         int        `json:"rank"`
+	Score        int        `json:"score"`
+	Note         string     `json:"note"`
+	Status       string     `json:"status"`
+	StatusReason string     `json:"status_reason,omitempty"` // 狀態說明 (例如犯規原因)
+	Unit         string     `json:"unit"`
+	TeamID       string     `json:"team_id,omitempty"`
+	Team         string     `json:"team,omitempty"`
+	Legs         []RelayLeg `json:"legs,omitempty"`
+	Splits       []Split    `json:"splits,omitempty"`
+	ReactionTime float64    `json:"reaction_time,omitempty"` // 出發反應時間 (秒)
+}
+
+// Split 是成績中的一個分段，time 為累計時間，lap 為與前一個分段的差 (秒)
+type Split struct {
+	Distance int     `json:"distance"`
+	Time     float64 `json:"time"`
+	Lap      float64 `json:"lap"`
+}
+
+// Athlete 是一位選手的基本資料
+type Athlete struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Names        []string `json:"names"`
+	Gender       string   `json:"gender"`
+	Units        []string `json:"units"`
+	BirthYearMin int      `json:"birth_year_min,omitempty"`
+	BirthYearMax int      `json:"birth_year_max,omitempty"`
+}
+
+// RelayLeg 是接力成績中的一棒
+type RelayLeg struct {
+	Leg     int     `json:"leg"`
+	Swimmer string  `json:"swimmer"`
+	Stroke  string  `json:"stroke"`
+	Split   float64 `json:"split,omitempty"`
+}
+
+// AthleteRelayResult 是選手參加過的一筆接力成績
+type AthleteRelayResult struct {
+	RaceID          string     `json:"race_id"`
+	Year            string     `json:"year"`
+	CompetitionName string     `json:"competition_name"`
+	EventName       string     `json:"event_name"`
+	EventDate       time.Time  `json:"event_date"`
+	PoolType        string     `json:"pool_type"`
+	Distance        int        `json:"distance"`
+	Stroke          string     `json:"stroke"`
+	RelayCount      int        `json:"relay_count"`
+	Round           string     `json:"round"`
+	Team            string     `json:"team"`
+	Leg             int        `json:"leg"`
+	Record          float64    `json:"record"`
+	Rank            int        `json:"rank"`
+	Score           int        `json:"score"`
+	Note            string     `json:"note"`
+	Legs            []RelayLeg `json:"legs"`
 }
 
 // NewAPIHandler creates a new APIHandler.
 func initAPIHandler(router gin.IRoutes, db *mongo.Stores, grpcClient GrpcClient) {
 	handler := &apiHandler{
-		raceStore:  db.RaceStore,
-		grpcClient: grpcClient,
+		athleteStore:     db.AthleteStore,
+		competitionStore: db.CompetitionStore,
+		raceStore:        db.RaceStore,
+		raceChangeStore:  db.RaceChangeStore,
+		recordStore:      db.RecordStore,
+		athleteBestStore: db.AthleteBestStore,
+		teamStore:        db.TeamStore,
+		grpcClient:       grpcClient,
 	}
 	router.GET("/athletes", handler.GetAthletes)
+	router.GET("/athletes/search", handler.SearchAthletes)
 	router.GET("/years", handler.GetYears)
 	router.GET("/competitions", handler.GetCompetitions)
-	router.GET("/athletes/:athlete_name/races", handler.GetAthleteRaces)
-	router.GET("/athletes/:athlete_name/performance-overview", handler.GetAthletePerformanceOverview)
+	router.GET("/competitions/:competition_id", handler.GetCompetition)
+	router.GET("/athletes/:athlete", handler.GetAthlete)
+	router.GET("/athletes/:athlete/races", handler.GetAthleteRaces)
+	router.GET("/athletes/:athlete/relays", handler.GetAthleteRelays)
+	router.GET("/athletes/:athlete/pacing", handler.GetAthletePacing)
+	router.GET("/athletes/:athlete/status-counts", handler.GetAthleteStatusCounts)
+	router.GET("/athletes/:athlete/performance-overview", handler.GetAthletePerformanceOverview)
+	router.GET("/athletes/:athlete/head-to-head/:other", handler.GetAthleteHeadToHead)
 	router.GET("/race/:race_id/comparison", handler.GetRaceComparison)
+	router.GET("/race/:race_id/changes", handler.GetRaceChanges)
+	router.GET("/race/:race_id/status-counts", handler.GetRaceStatusCounts)
+	router.GET("/changes", handler.GetChanges)
+	router.GET("/rankings", handler.GetRankings)
+	router.GET("/records", handler.GetRecords)
+	router.GET("/records/:record_id/history", handler.GetRecordHistory)
+	router.GET("/teams", handler.GetTeams)
+	router.GET("/teams/standings", handler.GetTeamStandings)
+	router.GET("/teams/:team_id/athletes", handler.GetTeamAthletes)
+	router.GET("/teams/:team_id/results", handler.GetTeamResults)
 }
 
 // GetAthletes handles the GET /athletes endpoint.
@@ -62,6 +154,84 @@ func (h *apiHandler) GetAthletes(c *gin.Context) {
 	c.JSON(http.StatusOK, names)
 }
 
+// GetAthlete handles the GET /athletes/:athlete endpoint.
+func (h *apiHandler) GetAthlete(c *gin.Context) {
+	param := c.Param("athlete")
+	ctx := c.Request.Context()
+	if id, err := bson.ObjectIDFromHex(param); err == nil {
+		athlete, err := h.findAthlete(ctx, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve athlete"})
+			return
+		}
+		if athlete == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "athlete not found"})
+			return
+		}
+		c.JSON(http.StatusOK, mapAthlete(athlete))
+		return
+	}
+
+	athletes, err := h.athleteStore.FindAthletesByName(ctx, param)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve athlete"})
+		return
+	}
+	switch len(athletes) {
+	case 0:
+		c.JSON(http.StatusNotFound, gin.H{"error": "athlete not found"})
+	case 1:
+		c.JSON(http.StatusOK, mapAthlete(athletes[0]))
+	default:
+		// 同名的選手不只一位，回傳所有候選讓呼叫端改用 athlete ID
+		candidates := make([]Athlete, len(athletes))
+		for i, athlete := range athletes {
+			candidates[i] = mapAthlete(athlete)
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": "multiple athletes share this name", "athletes": candidates})
+	}
+}
+
+// athleteFilter 將 athlete ID 或姓名轉成查詢成績的條件；ID 找不到選手時回應 404 並回傳 false，
+// 姓名則沿用以姓名比對成績的方式
+func (h *apiHandler) athleteFilter(c *gin.Context, param string) (mongo.AthleteFilter, bool) {
+	id, err := bson.ObjectIDFromHex(param)
+	if err != nil {
+		return mongo.AthleteFilter{Name: param}, true
+	}
+	athlete, err := h.findAthlete(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve athlete"})
+		return mongo.AthleteFilter{}, false
+	}
+	if athlete == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "athlete not found"})
+		return mongo.AthleteFilter{}, false
+	}
+	return mongo.AthleteFilter{ID: athlete.ID, Name: athlete.Name}, true
+}
+
+// findAthlete 以 ID 找出選手，已合併的選手改回傳合併後的選手
+func (h *apiHandler) findAthlete(ctx context.Context, id bson.ObjectID) (*models.Athlete, error) {
+	athlete, err := h.athleteStore.FindAthlete(ctx, id)
+	if err != nil || athlete == nil || athlete.MergedInto.IsZero() {
+		return athlete, err
+	}
+	return h.athleteStore.FindAthlete(ctx, athlete.MergedInto)
+}
+
+func mapAthlete(athlete *models.Athlete) Athlete {
+	return Athlete{
+		ID:           athlete.ID.Hex(),
+		Name:         athlete.Name,
+		Names:        athlete.Names,
+		Gender:       athlete.Gender,
+		Units:        athlete.Units,
+		BirthYearMin: athlete.BirthYearMin,
+		BirthYearMax: athlete.BirthYearMax,
+	}
+}
+
 // GetYears handles the GET /years endpoint.
 func (h *apiHandler) GetYears(c *gin.Context) {
 	years, err := h.raceStore.GetYears(c.Request.Context())
@@ -93,20 +263,33 @@ func (h *apiHandler) GetCompetitions(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve competitions"})
 		return
 	}
+	stored, err := h.competitionStore.FindCompetitions(c.Request.Context(), year)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve competitions"})
+		return
+	}
+	// 尚未執行 backfill competition 的舊資料沒有 id
+	ids := make(map[string]string, len(stored))
+	for _, competition := range stored {
+		ids[competition.Name] = competition.ID.Hex()
+	}
 
 	// Transform the string slice into a slice of objects to match frontend expectations
 	competitions := make([]gin.H, 0, len(competitionNames))
 
 	for _, name := range competitionNames {
-		competitions = append(competitions, gin.H{"name": name})
+		competition := gin.H{"name": name}
+		if id, ok := ids[name]; ok {
+			competition["id"] = id
+		}
+		competitions = append(competitions, competition)
 	}
 
 	c.JSON(http.StatusOK, competitions)
 }
 
-// GetAthleteRaces handles the GET /athletes/:athlete_name/races endpoint.
+// GetAthleteRaces handles the GET /athletes/:athlete/races endpoint.
 func (h *apiHandler) GetAthleteRaces(c *gin.Context) {
-	athleteName := c.Param("athlete_name")
 	competitionName := c.Query("competition_name")
 	year := c.Query("year")
 
@@ -115,7 +298,11 @@ func (h *apiHandler) GetAthleteRaces(c *gin.Context) {
 		return
 	}
 
-	races, err := h.raceStore.GetAthleteRaces(c.Request.Context(), athleteName, competitionName, year)
+	athlete, ok := h.athleteFilter(c, c.Param("athlete"))
+	if !ok {
+		return
+	}
+	races, err := h.raceStore.GetAthleteRaces(c.Request.Context(), athlete, competitionName, year)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve athlete races"})
 		return
@@ -130,55 +317,182 @@ func (h *apiHandler) GetAthleteRaces(c *gin.Context) {
 	results := make([]AthleteRaceResult, len(races))
 	for i, race := range races {
 		results[i] = AthleteRaceResult{
-			RaceID:    race.RaceID,
-			EventName: race.EventName,
-			Record:    race.Record / float64(time.Second),
-			Rank:      race.Rank,
-			Score:     race.Score,
-			Note:      race.Note,
+			RaceID:       race.RaceID,
+			EventName:    race.EventName,
+			Distance:     race.Distance,
+			Stroke:       race.Stroke,
+			Relay:        race.Relay,
+			Round:        race.Round,
+			Record:       race.Record / float64(time.Second),
+			Rank:         race.Rank,
+			Score:        race.Score,
+			Note:         race.Note,
+			Status:       string(resultStatus(race.Status, race.Note, race.Record > 0)),
+			StatusReason: race.StatusReason,
+			Unit:         race.Unit,
+			TeamID:       hexOrEmpty(race.TeamID),
+			Team:         race.Team,
+			Legs:         mapRelayLegs(race.Legs),
+			Splits:       mapSplits(race.Splits),
+			ReactionTime: race.ReactionTime.Seconds(),
 		}
 	}
 
 	c.JSON(http.StatusOK, results)
 }
 
-// GetAthletePerformanceOverview handles the GET /athletes/:athlete_name/performance-overview endpoint.
+// GetAthleteRelays handles the GET /athletes/:athlete/relays endpoint.
+func (h *apiHandler) GetAthleteRelays(c *gin.Context) {
+	athlete, ok := h.athleteFilter(c, c.Param("athlete"))
+	if !ok {
+		return
+	}
+
+	relays, err := h.raceStore.GetAthleteRelays(c.Request.Context(), athlete)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve athlete relays"})
+		return
+	}
+
+	results := make([]AthleteRelayResult, len(relays))
+	for i, relay := range relays {
+		results[i] = AthleteRelayResult{
+			RaceID:          relay.RaceID,
+			Year:            relay.Year,
+			CompetitionName: relay.CompetitionName,
+			EventName:       relay.EventName,
+			EventDate:       relay.EventDate,
+			PoolType:        relay.PoolType,
+			Distance:        relay.Distance,
+			Stroke:          relay.Stroke,
+			RelayCount:      relay.RelayCount,
+			Round:           relay.Round,
+			Team:            relay.Team,
+			Record:          relay.Record.Seconds(),
+			Rank:            relay.Rank,
+			Score:           relay.Score,
+			Note:            relay.Note,
+			Legs:            mapRelayLegs(relay.Legs),
+		}
+		for j, leg := range relay.Legs {
+			var ids []bson.ObjectID
+			if j < len(relay.AthleteIDs) {
+				ids = relay.AthleteIDs[j : j+1]
+			}
+			if athlete.MatchResult([]string{leg.Swimmer}, ids) {
+				results[i].Leg = j + 1
+				break
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+func mapRelayLegs(legs []models.RelayLeg) []RelayLeg {
+	if len(legs) == 0 {
+		return nil
+	}
+	output := make([]RelayLeg, len(legs))
+	for i, leg := range legs {
+		output[i] = RelayLeg{
+			Leg:     i + 1,
+			Swimmer: leg.Swimmer,
+			Stroke:  leg.Stroke,
+			Split:   leg.Split.Seconds(),
+		}
+	}
+	return output
+}
+
+func mapSplits(splits []models.Split) []Split {
+	if len(splits) == 0 {
+		return nil
+	}
+	output := make([]Split, len(splits))
+	var previous time.Duration
+	for i, split := range splits {
+		output[i] = Split{
+			Distance: split.Distance,
+			Time:     split.Time.Seconds(),
+			Lap:      (split.Time - previous).Seconds(),
+		}
+		previous = split.Time
+	}
+	return output
+}
+
+// performanceHistoryLimit 是成績趨勢分析讀取的最近成績筆數上限
+const performanceHistoryLimit = 300
+
+// GetAthletePerformanceOverview handles the GET /athletes/:athlete/performance-overview endpoint.
 func (h *apiHandler) GetAthletePerformanceOverview(c *gin.Context) {
-	athleteName := c.Param("athlete_name")
+	athlete, ok := h.athleteFilter(c, c.Param("athlete"))
+	if !ok {
+		return
+	}
 
-	races, err := h.raceStore.GetAllAthleteRaces(c.Request.Context(), athleteName)
+	swims, err := h.athleteBestStore.GetAthleteSwimHistory(c.Request.Context(), athlete, performanceHistoryLimit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve athlete races"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve athlete swims"})
 		return
 	}
-	req := mapRacesToAnalyzePerformanceOverviewRequest(athleteName, races)
+	bests, err := h.athleteBestStore.FindAthleteBests(c.Request.Context(), athlete)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve athlete bests"})
+		return
+	}
+	req := mapSwimsToAnalyzePerformanceOverviewRequest(athlete.Name, swims)
 	res, err := h.grpcClient.AnalyzePerformanceOverview(c.Request.Context(), req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to analyze performance"})
 		return
 	}
-	c.JSON(http.StatusOK, mapAnalysisToResponse(res.EventAnalyses))
+	c.JSON(http.StatusOK, mapAnalysisToResponse(res.EventAnalyses, athleteBestsByEvent(bests)))
+}
+
+// athleteBestsByEvent 以分析服務的項目名稱 (項目類型(水道)) 對應個人最佳成績，
+// 以姓名查詢時同名選手的同一項目取最快的
+func athleteBestsByEvent(bests []*models.AthleteBest) map[string]*models.AthleteBest {
+	byEvent := make(map[string]*models.AthleteBest, len(bests))
+	for _, best := range bests {
+		key := fmt.Sprintf("%s(%s)", best.EventType, best.PoolType)
+		if current, ok := byEvent[key]; !ok || best.Record < current.Record {
+			byEvent[key] = best
+		}
+	}
+	return byEvent
 }
 
-func mapRacesToAnalyzePerformanceOverviewRequest(
+// SeasonBest 是選手在一個項目中某一年的最佳成績
+type SeasonBest struct {
+	Year            string    `json:"year"`
+	AgeGroup        string    `json:"age_group"`
+	Time            float64   `json:"time"`
+	Date            time.Time `json:"date"`
+	RaceID          string    `json:"race_id"`
+	CompetitionName string    `json:"competition_name"`
+	EventName       string    `json:"event_name"`
+}
+
+// mapSwimsToAnalyzePerformanceOverviewRequest 以選手最近的個人項目成績依時間由舊到新組成分析請求，
+// 趨勢與進步幅度由每一次出賽計算；犯規、棄權等沒有有效成績的紀錄不列入
+func mapSwimsToAnalyzePerformanceOverviewRequest(
 	athleteName string,
-	races []*models.AggrAthleteJoinRacesFilterByAthlete,
+	swims []*models.AggrAthleteSwim,
 ) *analysisv1.AnalyzePerformanceOverviewRequest {
-	performanceResults := make([]*analysisv1.PerformanceResult, 0, len(races))
-	appendCount := 0
-	for _, race := range races {
-		if race.Record == 0 {
+	performanceResults := make([]*analysisv1.PerformanceResult, 0, len(swims))
+	for _, swim := range slices.Backward(swims) {
+		if !hasValidTime(float64(swim.Record), swim.Status, swim.Note) {
 			continue
 		}
 		performanceResults = append(performanceResults, &analysisv1.PerformanceResult{
-			EventDate:       timestamppb.New(race.EventDate),
-			ResultTime:      race.Record / float64(time.Second),
-			EventType:       fmt.Sprintf("%s(%s)", race.EventType, race.PoolType),
-			CompetitionName: fmt.Sprintf("%s %s", race.CompetitionName, race.EventName),
+			EventDate:       timestamppb.New(swim.EventDate),
+			ResultTime:      swim.Record.Seconds(),
+			EventType:       fmt.Sprintf("%s(%s)", swim.Label(), swim.PoolType),
+			CompetitionName: fmt.Sprintf("%s %s", swim.CompetitionName, swim.EventName),
 		})
-		appendCount++
 	}
-	performanceResults = performanceResults[:appendCount]
 
 	return &analysisv1.AnalyzePerformanceOverviewRequest{
 		AthleteName: athleteName,
@@ -186,7 +500,11 @@ func mapRacesToAnalyzePerformanceOverviewRequest(
 	}
 }
 
-func mapAnalysisToResponse(analyses []*analysisv1.EventPerformanceAnalysis) []map[string]any {
+// mapAnalysisToResponse 組合分析結果，個人最佳成績與季最佳以 athlete_best 為準，
+// 沒有對應的項目時沿用分析服務的結果
+func mapAnalysisToResponse(
+	analyses []*analysisv1.EventPerformanceAnalysis, bests map[string]*models.AthleteBest,
+) []map[string]any {
 	output := make([]map[string]any, 0, len(analyses))
 	for _, analysis := range analyses {
 		// mins := time.Duration(analysis.PersonalBest) / time.Minute
@@ -199,13 +517,32 @@ func mapAnalysisToResponse(analyses []*analysisv1.EventPerformanceAnalysis) []ma
 				"competition_name": race.CompetitionName,
 			})
 		}
+		personalBest := map[string]any{
+			"time": analysis.PersonalBest.Time,
+			"unit": "s",
+			"date": analysis.PersonalBest.Date,
+		}
+		seasonBests := []SeasonBest{}
+		if best, ok := bests[analysis.EventName]; ok {
+			personalBest["time"] = best.Record.Seconds()
+			personalBest["date"] = best.Date.Format(time.DateOnly)
+			personalBest["race_id"] = best.RaceID.Hex()
+			for _, season := range best.Seasons {
+				seasonBests = append(seasonBests, SeasonBest{
+					Year:            season.Year,
+					AgeGroup:        season.AgeGroup,
+					Time:            season.Record.Seconds(),
+					Date:            season.Date,
+					RaceID:          season.RaceID.Hex(),
+					CompetitionName: season.CompetitionName,
+					EventName:       season.EventName,
+				})
+			}
+		}
 		output = append(output, map[string]any{
-			"event_name": analysis.EventName,
-			"personal_best": map[string]any{
-				"time": analysis.PersonalBest.Time,
-				"unit": "s",
-				"date": analysis.PersonalBest.Date,
-			},
+			"event_name":    analysis.EventName,
+			"personal_best": personalBest,
+			"season_bests":  seasonBests,
 			"analysis": map[string]any{
 				"stability": map[string]any{
 					"value": analysis.Analysis.Stability.Value,
@@ -240,18 +577,22 @@ func mapAnalysisToResponse(analyses []*analysisv1.EventPerformanceAnalysis) []ma
 func (h *apiHandler) GetRaceComparison(c *gin.Context) {
 	raceIDHex := c.Param("race_id")
 
-	athleteName := c.Query("athlete_name")
-	if athleteName == "" {
+	athleteParam := c.Query("athlete_name")
+	if athleteParam == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "athlete_name query parameter is required"})
 		return
 	}
+	athlete, ok := h.athleteFilter(c, athleteParam)
+	if !ok {
+		return
+	}
 	raceWithResult, err := h.raceStore.GetRaceWithResultsByID(c.Request.Context(), raceIDHex)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve race"})
 		return
 	}
 
-	req, err := mapRaceWithResultToAnalyzeResultComparisonRequest(athleteName, raceWithResult)
+	req, err := mapRaceWithResultToAnalyzeResultComparisonRequest(athlete, raceWithResult)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -267,20 +608,27 @@ func (h *apiHandler) GetRaceComparison(c *gin.Context) {
 }
 
 func mapRaceWithResultToAnalyzeResultComparisonRequest(
-	athleteName string,
+	athlete mongo.AthleteFilter,
 	race *models.AggrRaceWithResult,
 ) (*analysisv1.AnalyzeResultComparisonRequest, error) {
 	competitionResults := make([]*analysisv1.RaceResult, len(race.Results))
 	var targetResult *analysisv1.RaceResult
+	var targetStatus crawler.ResultStatus
 	var resultSize int
 	for _, result := range race.Results {
-		if result.Record == 0 {
+		if !hasValidTime(result.Record.Seconds(), result.Status, result.Note) {
+			if athlete.MatchResult(result.Name, result.AthleteIDs) {
+				targetStatus = resultStatus(result.Status, result.Note, result.Record > 0)
+			}
 			continue
 		}
 		var resultAthleteName string
-		if len(result.Name) == 1 {
+		switch {
+		case result.Team != "":
+			resultAthleteName = result.Team
+		case len(result.Name) == 1:
 			resultAthleteName = result.Name[0]
-		} else {
+		default:
 			resultAthleteName = strings.Join(result.Name, ",")
 		}
 		res := &analysisv1.RaceResult{
@@ -297,7 +645,7 @@ func mapRaceWithResultToAnalyzeResultComparisonRequest(
 			resultSize++
 		}
 
-		if slices.Contains(result.Name, athleteName) {
+		if athlete.MatchResult(result.Name, result.AthleteIDs) {
 			targetResult = res
 			if !hasAdded {
 				competitionResults[resultSize] = res
@@ -305,6 +653,9 @@ func mapRaceWithResultToAnalyzeResultComparisonRequest(
 			}
 		}
 	}
+	if targetResult == nil && targetStatus != "" {
+		return nil, fmt.Errorf("target athlete has no valid time in this race: %s", targetStatus)
+	}
 	if targetResult == nil {
 		return nil, errors.New("target athlete not found in this race")
 	}
@@ -365,6 +716,82 @@ func mapAnalyzeResultComparisonResponseToResponse(
 	}
 }
 
+const defaultChangesLimit = 100
+
+type RaceChange struct {
+	RaceID          string    `json:"race_id"`
+	Year            string    `json:"year"`
+	CompetitionName string    `json:"competition_name"`
+	EventName       string    `json:"event_name"`
+	Athlete         string    `json:"athlete"`
+	Field           string    `json:"field"`
+	OldValue        string    `json:"old_value"`
+	NewValue        string    `json:"new_value"`
+	DetectedAt      time.Time `json:"detected_at"`
+}
+
+// GetRaceChanges handles the GET /race/:race_id/changes endpoint.
+func (h *apiHandler) GetRaceChanges(c *gin.Context) {
+	raceID, err := bson.ObjectIDFromHex(c.Param("race_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid race_id"})
+		return
+	}
+	changes, err := h.raceChangeStore.FindRaceChanges(c.Request.Context(), mongo.NewRaceChangeQueryByRace(raceID), 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve race changes"})
+		return
+	}
+	c.JSON(http.StatusOK, mapRaceChanges(changes))
+}
+
+// GetChanges handles the GET /changes endpoint.
+func (h *apiHandler) GetChanges(c *gin.Context) {
+	var since time.Time
+	if value := c.Query("since"); value != "" {
+		var err error
+		since, err = time.Parse(time.DateOnly, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be a date like 2025-01-31"})
+			return
+		}
+	}
+	limit := int64(defaultChangesLimit)
+	if value := c.Query("limit"); value != "" {
+		var err error
+		limit, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+	}
+	q := mongo.NewRaceChangeQuery(c.Query("year"), c.Query("competition_name"), c.Query("athlete"), since)
+	changes, err := h.raceChangeStore.FindRaceChanges(c.Request.Context(), q, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve race changes"})
+		return
+	}
+	c.JSON(http.StatusOK, mapRaceChanges(changes))
+}
+
+func mapRaceChanges(changes []*models.RaceChange) []RaceChange {
+	output := make([]RaceChange, len(changes))
+	for i, change := range changes {
+		output[i] = RaceChange{
+			RaceID:          change.RaceId.Hex(),
+			Year:            change.Year,
+			CompetitionName: change.CompetitionName,
+			EventName:       change.EventName,
+			Athlete:         change.Athlete,
+			Field:           change.Field,
+			OldValue:        change.OldValue,
+			NewValue:        change.NewValue,
+			DetectedAt:      change.DetectedAt,
+		}
+	}
+	return output
+}
+
 func getDiffLabel(diff *float64) string {
 	if diff == nil {
 		return ""
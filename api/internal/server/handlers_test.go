@@ -0,0 +1,43 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 趨勢分析使用每一次出賽的成績，依時間由舊到新，沒有有效成績的紀錄不列入
+func TestMapSwimsToAnalyzePerformanceOverviewRequest(t *testing.T) {
+	event := models.RaceEvent{Distance: 100, Stroke: "freestyle"}
+	swim := func(date time.Time, record time.Duration, status string) *models.AggrAthleteSwim {
+		return &models.AggrAthleteSwim{
+			CompetitionName: "全國運動會", EventName: "100公尺自由式 決賽", EventDate: date,
+			PoolType: "50m", Record: record, Status: status, RaceEvent: event,
+		}
+	}
+	// GetAthleteSwimHistory 由新到舊排序，同一年有多次出賽
+	swims := []*models.AggrAthleteSwim{
+		swim(time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), 58*time.Second, string(crawler.ResultStatusOK)),
+		swim(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), 59*time.Second, string(crawler.ResultStatusDQ)),
+		swim(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), 60*time.Second, string(crawler.ResultStatusOK)),
+		swim(time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC), 62*time.Second, string(crawler.ResultStatusOK)),
+	}
+
+	req := mapSwimsToAnalyzePerformanceOverviewRequest("王小明", swims)
+	assert.Equal(t, "王小明", req.AthleteName)
+	require.Len(t, req.Results, 3)
+	var times []float64
+	for i, result := range req.Results {
+		assert.Equal(t, "100公尺自由式(50m)", result.EventType)
+		if i > 0 {
+			assert.True(t, result.EventDate.AsTime().After(req.Results[i-1].EventDate.AsTime()))
+		}
+		times = append(times, result.ResultTime)
+	}
+	assert.Equal(t, []float64{62, 60, 58}, times)
+}
@@ -0,0 +1,263 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"aquascore/api/internal/db/mongo/models"
+	"aquascore/api/internal/headtohead"
+
+	analysisv1 "buf.build/gen/go/aqua/analysis/protocolbuffers/go/analysis/v1"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// HeadToHead 是兩位選手的對戰比較，races 依日期由舊到新，events 依項目名稱排序
+type HeadToHead struct {
+	AthleteA HeadToHeadAthlete `json:"athlete_a"`
+	AthleteB HeadToHeadAthlete `json:"athlete_b"`
+	Meetings int               `json:"meetings"`
+	WinsA    int               `json:"wins_a"`
+	WinsB    int               `json:"wins_b"`
+	Ties     int               `json:"ties"`
+	Events   []HeadToHeadEvent `json:"events"`
+	Races    []HeadToHeadRace  `json:"races"`
+}
+
+// HeadToHeadAthlete 是比較中的一位選手，以姓名查詢時沒有 id
+type HeadToHeadAthlete struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+// HeadToHeadEvent 是兩位選手在一個項目中的勝負、平均時間差 (a 減 b，負值代表 a 較快) 與最佳成績比較
+type HeadToHeadEvent struct {
+	Event           string    `json:"event"`
+	Meetings        int       `json:"meetings"`
+	WinsA           int       `json:"wins_a"`
+	WinsB           int       `json:"wins_b"`
+	Ties            int       `json:"ties"`
+	AverageGap      *float64  `json:"average_gap,omitempty"`
+	PersonalBestA   float64   `json:"personal_best_a,omitempty"`
+	PersonalBestB   float64   `json:"personal_best_b,omitempty"`
+	PersonalBestGap *float64  `json:"personal_best_gap,omitempty"`
+	GapTrend        *GapTrend `json:"gap_trend,omitempty"`
+}
+
+// GapTrend 是分析服務對時間差變化的分析，label 為 improving 代表 a 相對 b 進步
+type GapTrend struct {
+	Value float64   `json:"value"`
+	Unit  string    `json:"unit"`
+	Label string    `json:"label"`
+	Dates []string  `json:"dates"`
+	Gaps  []float64 `json:"gaps"`
+}
+
+// HeadToHeadRace 是兩位選手都有出賽的一場比賽，winner 為 a、b、tie 或 none (兩位都沒有有效成績)
+type HeadToHeadRace struct {
+	RaceID          string         `json:"race_id"`
+	Event           string         `json:"event"`
+	CompetitionName string         `json:"competition_name"`
+	EventName       string         `json:"event_name"`
+	EventDate       time.Time      `json:"event_date"`
+	A               HeadToHeadSwim `json:"a"`
+	B               HeadToHeadSwim `json:"b"`
+	Winner          string         `json:"winner"`
+	Gap             *float64       `json:"gap,omitempty"`
+}
+
+// HeadToHeadSwim 是選手在對戰比賽中的成績
+type HeadToHeadSwim struct {
+	Record float64 `json:"record"`
+	Rank   int     `json:"rank"`
+	Status string  `json:"status"`
+}
+
+// GetAthleteHeadToHead handles the GET /athletes/:athlete/head-to-head/:other endpoint.
+func (h *apiHandler) GetAthleteHeadToHead(c *gin.Context) {
+	withTrend := false
+	if value := c.Query("trend"); value != "" {
+		var err error
+		withTrend, err = strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "trend must be true or false"})
+			return
+		}
+	}
+	athleteA, ok := h.athleteFilter(c, c.Param("athlete"))
+	if !ok {
+		return
+	}
+	athleteB, ok := h.athleteFilter(c, c.Param("other"))
+	if !ok {
+		return
+	}
+	if athleteA.ID == athleteB.ID && (!athleteA.ID.IsZero() || athleteA.Name == athleteB.Name) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot compare an athlete with themselves"})
+		return
+	}
+
+	racesA, err := h.raceStore.GetAllAthleteRaces(c.Request.Context(), athleteA)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve athlete races"})
+		return
+	}
+	racesB, err := h.raceStore.GetAllAthleteRaces(c.Request.Context(), athleteB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve athlete races"})
+		return
+	}
+	result := headtohead.Compare(toHeadToHeadSwims(racesA), toHeadToHeadSwims(racesB))
+	output := mapHeadToHead(result, racesA, racesB)
+	output.AthleteA = HeadToHeadAthlete{ID: hexOrEmpty(athleteA.ID), Name: athleteA.Name}
+	output.AthleteB = HeadToHeadAthlete{ID: hexOrEmpty(athleteB.ID), Name: athleteB.Name}
+
+	if withTrend && len(result.Meetings) > 0 {
+		req := mapMeetingsToAnalyzePerformanceOverviewRequest(athleteA.Name, athleteB.Name, result.Meetings)
+		res, err := h.grpcClient.AnalyzePerformanceOverview(c.Request.Context(), req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to analyze gap trend"})
+			return
+		}
+		trends := make(map[string]*GapTrend, len(res.EventAnalyses))
+		for _, analysis := range res.EventAnalyses {
+			trends[analysis.EventName] = &GapTrend{
+				Value: analysis.Analysis.Trend.Value,
+				Unit:  analysis.Analysis.Trend.Unit,
+				Label: analysis.Analysis.Trend.Label,
+				Dates: analysis.Charts.TrendChart.Dates,
+				Gaps:  analysis.Charts.TrendChart.Times,
+			}
+		}
+		for i := range output.Events {
+			output.Events[i].GapTrend = trends[output.Events[i].Event]
+		}
+	}
+	c.JSON(http.StatusOK, output)
+}
+
+// performanceEventType 回傳分析用的項目名稱 (項目類型(水道))，
+// 已解析項目的資料以正規化的名稱分組，舊資料沿用原本的項目類型字串
+func performanceEventType(race *models.AggrAthleteJoinRacesFilterByAthlete) string {
+	eventType := race.Label()
+	if eventType == "" {
+		eventType = race.EventType
+	}
+	return fmt.Sprintf("%s(%s)", eventType, race.PoolType)
+}
+
+// toHeadToHeadSwims 轉換選手的個人項目成績，接力不列入比較
+func toHeadToHeadSwims(races []*models.AggrAthleteJoinRacesFilterByAthlete) []headtohead.Swim {
+	swims := make([]headtohead.Swim, 0, len(races))
+	for _, race := range races {
+		if race.Relay {
+			continue
+		}
+		swims = append(swims, headtohead.Swim{
+			RaceID:          race.RaceID,
+			Event:           performanceEventType(race),
+			Date:            race.EventDate,
+			CompetitionName: race.CompetitionName,
+			EventName:       race.EventName,
+			Record:          time.Duration(race.Record),
+			Rank:            race.Rank,
+			Valid:           hasValidTime(race.Record, race.Status, race.Note),
+		})
+	}
+	return swims
+}
+
+func mapHeadToHead(
+	result headtohead.Result, racesA, racesB []*models.AggrAthleteJoinRacesFilterByAthlete,
+) HeadToHead {
+	output := HeadToHead{
+		Meetings: len(result.Meetings),
+		Events:   make([]HeadToHeadEvent, len(result.Events)),
+		Races:    make([]HeadToHeadRace, len(result.Meetings)),
+	}
+	for i, event := range result.Events {
+		output.Events[i] = HeadToHeadEvent{
+			Event:         event.Event,
+			Meetings:      event.Meetings,
+			WinsA:         event.WinsA,
+			WinsB:         event.WinsB,
+			Ties:          event.Ties,
+			AverageGap:    durationSeconds(event.AverageGap),
+			PersonalBestA: event.BestA.Seconds(),
+			PersonalBestB: event.BestB.Seconds(),
+		}
+		if event.BestA > 0 && event.BestB > 0 {
+			gap := event.BestA - event.BestB
+			output.Events[i].PersonalBestGap = durationSeconds(&gap)
+		}
+		output.WinsA += event.WinsA
+		output.WinsB += event.WinsB
+		output.Ties += event.Ties
+	}
+	byRaceA, byRaceB := racesByID(racesA), racesByID(racesB)
+	for i, meeting := range result.Meetings {
+		output.Races[i] = HeadToHeadRace{
+			RaceID:          meeting.A.RaceID,
+			Event:           meeting.A.Event,
+			CompetitionName: meeting.A.CompetitionName,
+			EventName:       meeting.A.EventName,
+			EventDate:       meeting.A.Date,
+			A:               mapHeadToHeadSwim(byRaceA[meeting.A.RaceID]),
+			B:               mapHeadToHeadSwim(byRaceB[meeting.B.RaceID]),
+			Winner:          string(meeting.Winner),
+			Gap:             durationSeconds(meeting.Gap),
+		}
+	}
+	return output
+}
+
+func racesByID(
+	races []*models.AggrAthleteJoinRacesFilterByAthlete,
+) map[string]*models.AggrAthleteJoinRacesFilterByAthlete {
+	byID := make(map[string]*models.AggrAthleteJoinRacesFilterByAthlete, len(races))
+	for _, race := range races {
+		byID[race.RaceID] = race
+	}
+	return byID
+}
+
+func mapHeadToHeadSwim(race *models.AggrAthleteJoinRacesFilterByAthlete) HeadToHeadSwim {
+	return HeadToHeadSwim{
+		Record: race.Record / float64(time.Second),
+		Rank:   race.Rank,
+		Status: string(resultStatus(race.Status, race.Note, race.Record > 0)),
+	}
+}
+
+// mapMeetingsToAnalyzePerformanceOverviewRequest 將每個項目的時間差 (a 減 b) 當作成績送給分析服務，
+// 以整體表現分析的趨勢與走勢圖呈現時間差的變化
+func mapMeetingsToAnalyzePerformanceOverviewRequest(
+	nameA, nameB string, meetings []headtohead.Meeting,
+) *analysisv1.AnalyzePerformanceOverviewRequest {
+	results := make([]*analysisv1.PerformanceResult, 0, len(meetings))
+	for _, meeting := range meetings {
+		if meeting.Gap == nil {
+			continue
+		}
+		results = append(results, &analysisv1.PerformanceResult{
+			EventDate:       timestamppb.New(meeting.A.Date),
+			ResultTime:      meeting.Gap.Seconds(),
+			EventType:       meeting.A.Event,
+			CompetitionName: fmt.Sprintf("%s %s", meeting.A.CompetitionName, meeting.A.EventName),
+		})
+	}
+	return &analysisv1.AnalyzePerformanceOverviewRequest{
+		AthleteName: fmt.Sprintf("%s vs %s", nameA, nameB),
+		Results:     results,
+	}
+}
+
+func durationSeconds(d *time.Duration) *float64 {
+	if d == nil {
+		return nil
+	}
+	seconds := d.Seconds()
+	return &seconds
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"aquascore/api/internal/db/mongo/models"
+	"aquascore/api/internal/pacing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PacingAnalysis 是選手在一個項目中有分段成績的配速，逐段與有分段的最佳成績比較
+type PacingAnalysis struct {
+	Distance     int          `json:"distance"`
+	Stroke       string       `json:"stroke"`
+	PoolType     string       `json:"pool_type,omitempty"`
+	PersonalBest float64      `json:"personal_best"` // 項目的最佳成績 (秒)，不論有沒有分段
+	Best         *PacingSwim  `json:"best"`          // 有分段的最佳成績，沒有任何分段成績時為 null
+	Swims        []PacingSwim `json:"swims"`
+}
+
+// PacingSwim 是一次有分段成績的配速
+type PacingSwim struct {
+	RaceID          string      `json:"race_id"`
+	CompetitionName string      `json:"competition_name"`
+	EventName       string      `json:"event_name"`
+	EventDate       time.Time   `json:"event_date"`
+	PoolType        string      `json:"pool_type"`
+	Record          float64     `json:"record"`
+	ReactionTime    float64     `json:"reaction_time,omitempty"`
+	IsPersonalBest  bool        `json:"is_personal_best"`
+	FirstHalf       float64     `json:"first_half,omitempty"`
+	SecondHalf      float64     `json:"second_half,omitempty"`
+	Differential    *float64    `json:"differential,omitempty"` // 後半段減前半段，正值代表後段較慢
+	Laps            []PacingLap `json:"laps"`
+}
+
+// PacingLap 是配速中的一段，best_diff 為與有分段的最佳成績在同一距離的累計時間差
+type PacingLap struct {
+	Distance int      `json:"distance"`
+	Time     float64  `json:"time"`
+	Lap      float64  `json:"lap"`
+	Share    float64  `json:"share"`
+	BestDiff *float64 `json:"best_diff,omitempty"`
+}
+
+// GetAthletePacing handles the GET /athletes/:athlete/pacing endpoint.
+func (h *apiHandler) GetAthletePacing(c *gin.Context) {
+	distance, err := strconv.Atoi(c.Query("distance"))
+	stroke := c.Query("stroke")
+	if err != nil || distance <= 0 || stroke == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "distance and stroke query parameters are required"})
+		return
+	}
+	poolType := c.Query("pool_type")
+
+	athlete, ok := h.athleteFilter(c, c.Param("athlete"))
+	if !ok {
+		return
+	}
+	races, err := h.raceStore.GetAllAthleteRaces(c.Request.Context(), athlete)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve athlete races"})
+		return
+	}
+
+	races = slices.DeleteFunc(races, func(race *models.AggrAthleteJoinRacesFilterByAthlete) bool {
+		return race.Relay || !hasValidTime(race.Record, race.Status, race.Note) || race.Distance != distance || race.Stroke != stroke ||
+			(poolType != "" && race.PoolType != poolType)
+	})
+	// 由新到舊
+	slices.SortFunc(races, func(a, b *models.AggrAthleteJoinRacesFilterByAthlete) int {
+		return b.EventDate.Compare(a.EventDate)
+	})
+	c.JSON(http.StatusOK, analyzePacing(races, distance, stroke, poolType))
+}
+
+func analyzePacing(
+	races []*models.AggrAthleteJoinRacesFilterByAthlete, distance int, stroke, poolType string,
+) PacingAnalysis {
+	analysis := PacingAnalysis{Distance: distance, Stroke: stroke, PoolType: poolType, Swims: []PacingSwim{}}
+	swims := make([]pacing.Swim, len(races))
+	var personalBest time.Duration
+	for i, race := range races {
+		swims[i] = pacing.Swim{Record: time.Duration(race.Record), Splits: toPacingSplits(race.Splits)}
+		if personalBest == 0 || swims[i].Record < personalBest {
+			personalBest = swims[i].Record
+		}
+	}
+	analysis.PersonalBest = personalBest.Seconds()
+	bestIndex := pacing.Best(swims)
+	if bestIndex < 0 {
+		return analysis
+	}
+	best := &swims[bestIndex]
+	for i, race := range races {
+		if len(swims[i].Splits) == 0 {
+			continue
+		}
+		swim := mapPacingSwim(race, pacing.Analyze(swims[i], distance, best))
+		swim.IsPersonalBest = swims[i].Record == personalBest
+		if i == bestIndex {
+			analysis.Best = &swim
+		}
+		analysis.Swims = append(analysis.Swims, swim)
+	}
+	return analysis
+}
+
+func mapPacingSwim(race *models.AggrAthleteJoinRacesFilterByAthlete, profile pacing.Profile) PacingSwim {
+	swim := PacingSwim{
+		RaceID:          race.RaceID,
+		CompetitionName: race.CompetitionName,
+		EventName:       race.EventName,
+		EventDate:       race.EventDate,
+		PoolType:        race.PoolType,
+		Record:          race.Record / float64(time.Second),
+		ReactionTime:    race.ReactionTime.Seconds(),
+		Laps:            make([]PacingLap, len(profile.Laps)),
+	}
+	if profile.HasHalves() {
+		differential := profile.Differential.Seconds()
+		swim.FirstHalf = profile.FirstHalf.Seconds()
+		swim.SecondHalf = profile.SecondHalf.Seconds()
+		swim.Differential = &differential
+	}
+	for i, lap := range profile.Laps {
+		swim.Laps[i] = PacingLap{
+			Distance: lap.Distance,
+			Time:     lap.Cumulative.Seconds(),
+			Lap:      lap.Time.Seconds(),
+			Share:    lap.Share,
+		}
+		if lap.BestDiff != nil {
+			diff := lap.BestDiff.Seconds()
+			swim.Laps[i].BestDiff = &diff
+		}
+	}
+	return swim
+}
+
+func toPacingSplits(splits []models.Split) []pacing.Split {
+	if len(splits) == 0 {
+		return nil
+	}
+	output := make([]pacing.Split, len(splits))
+	for i, split := range splits {
+		output[i] = pacing.Split{Distance: split.Distance, Time: split.Time}
+	}
+	return output
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	defaultRankingLimit = 50
+	maxRankingLimit     = 500
+)
+
+// Ranking 是排行中的一位選手與其最佳成績，成績相同時 rank 並列
+type Ranking struct {
+	Rank            int       `json:"rank"`
+	AthleteID       string    `json:"athlete_id,omitempty"`
+	Name            string    `json:"name"`
+	Unit            string    `json:"unit"`
+	TeamID          string    `json:"team_id,omitempty"`
+	Team            string    `json:"team"`
+	Record          float64   `json:"record"`
+	RaceID          string    `json:"race_id"`
+	Year            string    `json:"year"`
+	CompetitionName string    `json:"competition_name"`
+	EventName       string    `json:"event_name"`
+	EventDate       time.Time `json:"event_date"`
+	AgeGroup        string    `json:"age_group"`
+}
+
+// GetRankings handles the GET /rankings endpoint.
+func (h *apiHandler) GetRankings(c *gin.Context) {
+	event := crawler.ParseEvent(c.Query("event"))
+	if event.Distance == 0 || event.Stroke == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "event query parameter must be an event like 100公尺仰式"})
+		return
+	}
+	if event.Relay {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "relay events are not ranked"})
+		return
+	}
+	filter := mongo.RankingFilter{
+		Event:    models.RaceEvent{Distance: event.Distance, Stroke: string(event.Stroke)},
+		Gender:   c.Query("gender"),
+		AgeGroup: c.Query("age_group"),
+		Year:     c.Query("year"),
+		PoolType: c.Query("course"),
+	}
+	if value := c.Query("team_id"); value != "" {
+		teamID, err := bson.ObjectIDFromHex(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid team_id"})
+			return
+		}
+		filter.TeamID = teamID
+	}
+	limit, ok := intQuery(c, "limit", defaultRankingLimit)
+	if !ok || limit <= 0 || limit > maxRankingLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be an integer between 1 and 500"})
+		return
+	}
+	offset, ok := intQuery(c, "offset", 0)
+	if !ok || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+
+	rankings, err := h.raceStore.GetRankings(c.Request.Context(), filter, offset, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve rankings"})
+		return
+	}
+	output := make([]Ranking, len(rankings))
+	for i, ranking := range rankings {
+		output[i] = Ranking{
+			Rank:            ranking.Rank,
+			Unit:            ranking.Unit,
+			TeamID:          hexOrEmpty(ranking.TeamID),
+			Team:            ranking.Team,
+			Record:          ranking.Record.Seconds(),
+			RaceID:          ranking.RaceID.Hex(),
+			Year:            ranking.Year,
+			CompetitionName: ranking.CompetitionName,
+			EventName:       ranking.EventName,
+			EventDate:       ranking.EventDate,
+			AgeGroup:        ranking.AgeGroup,
+		}
+		if len(ranking.AthleteIDs) > 0 {
+			output[i].AthleteID = ranking.AthleteIDs[0].Hex()
+		}
+		if len(ranking.Name) > 0 {
+			output[i].Name = ranking.Name[0]
+		}
+	}
+	c.JSON(http.StatusOK, output)
+}
+
+// intQuery 讀取整數查詢參數，沒有提供時回傳 defaultValue，不是整數時 ok 為 false
+func intQuery(c *gin.Context, key string, defaultValue int) (value int, ok bool) {
+	raw := c.Query(key)
+	if raw == "" {
+		return defaultValue, true
+	}
+	value, err := strconv.Atoi(raw)
+	return value, err == nil
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"aquascore/api/internal/db/mongo"
+	"aquascore/api/internal/db/mongo/models"
+	"aquascore/api/internal/record"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Record 是一項全國紀錄或大會紀錄目前的值，athletes 只有紀錄是在我們的成績中創下時才有
+type Record struct {
+	ID              string    `json:"id"`
+	Type            string    `json:"type"`
+	CompetitionName string    `json:"competition_name,omitempty"`
+	Gender          string    `json:"gender"`
+	AgeGroup        string    `json:"age_group"`
+	PoolType        string    `json:"pool_type"`
+	EventType       string    `json:"event_type"`
+	Distance        int       `json:"distance"`
+	Stroke          string    `json:"stroke"`
+	Relay           bool      `json:"relay"`
+	Record          float64   `json:"record"`
+	Date            time.Time `json:"date"`
+	Athletes        []string  `json:"athletes,omitempty"`
+	Unit            string    `json:"unit,omitempty"`
+}
+
+// RecordHistory 是一項紀錄與其歷史，history 依日期由舊到新
+type RecordHistory struct {
+	Record
+	History []RecordEntry `json:"history"`
+}
+
+// RecordEntry 是紀錄歷史中的一筆，source 為 report (成績報告上列出的紀錄) 或 result (成績中平或破紀錄)
+type RecordEntry struct {
+	Record          float64   `json:"record"`
+	Date            time.Time `json:"date"`
+	Source          string    `json:"source"`
+	RaceID          string    `json:"race_id,omitempty"`
+	Year            string    `json:"year"`
+	CompetitionName string    `json:"competition_name"`
+	EventName       string    `json:"event_name"`
+	Athletes        []string  `json:"athletes,omitempty"`
+	Unit            string    `json:"unit,omitempty"`
+}
+
+// GetRecords handles the GET /records endpoint.
+func (h *apiHandler) GetRecords(c *gin.Context) {
+	filter := mongo.RecordFilter{
+		Type:            c.Query("type"),
+		CompetitionName: c.Query("competition_name"),
+		Gender:          c.Query("gender"),
+		AgeGroup:        c.Query("age_group"),
+		PoolType:        c.Query("pool_type"),
+		Stroke:          c.Query("stroke"),
+	}
+	if filter.Type != "" && filter.Type != string(record.TypeNational) && filter.Type != string(record.TypeGames) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be national or games"})
+		return
+	}
+	if value := c.Query("distance"); value != "" {
+		distance, err := strconv.Atoi(value)
+		if err != nil || distance <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "distance must be a positive integer"})
+			return
+		}
+		filter.Distance = distance
+	}
+	records, err := h.recordStore.FindRecords(c.Request.Context(), mongo.NewRecordQuery(filter))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve records"})
+		return
+	}
+	output := make([]Record, len(records))
+	for i, r := range records {
+		output[i] = mapRecord(r)
+	}
+	c.JSON(http.StatusOK, output)
+}
+
+// GetRecordHistory handles the GET /records/:record_id/history endpoint.
+func (h *apiHandler) GetRecordHistory(c *gin.Context) {
+	id, err := bson.ObjectIDFromHex(c.Param("record_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid record_id"})
+		return
+	}
+	r, err := h.recordStore.FindRecord(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve record"})
+		return
+	}
+	if r == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "record not found"})
+		return
+	}
+	output := RecordHistory{Record: mapRecord(r), History: make([]RecordEntry, len(r.History))}
+	for i, entry := range r.History {
+		output.History[i] = RecordEntry{
+			Record:          entry.Record.Seconds(),
+			Date:            entry.Date,
+			Source:          entry.Source,
+			RaceID:          hexOrEmpty(entry.RaceID),
+			Year:            entry.Year,
+			CompetitionName: entry.CompetitionName,
+			EventName:       entry.EventName,
+			Athletes:        entry.Athletes,
+			Unit:            entry.Unit,
+		}
+	}
+	c.JSON(http.StatusOK, output)
+}
+
+func mapRecord(r *models.Record) Record {
+	output := Record{
+		ID:              r.ID.Hex(),
+		Type:            r.Type,
+		CompetitionName: r.CompetitionName,
+		Gender:          r.Gender,
+		AgeGroup:        r.AgeGroup,
+		PoolType:        r.PoolType,
+		EventType:       r.EventType,
+		Distance:        r.Distance,
+		Stroke:          r.Stroke,
+		Relay:           r.Relay,
+		Record:          r.Record.Seconds(),
+		Date:            r.Date,
+	}
+	if len(r.History) > 0 {
+		current := r.History[len(r.History)-1]
+		output.Athletes = current.Athletes
+		output.Unit = current.Unit
+	}
+	return output
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"aquascore/api/internal/athlete"
+	"aquascore/api/internal/db/mongo"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	// rocYearOffset 是西元年與民國年的差，出生年大於此值時視為西元年
+	rocYearOffset = 1911
+)
+
+// AthleteSearchResult 是搜尋到的一位選手，match 為比對等級 (prefix、substring 或 fuzzy)
+type AthleteSearchResult struct {
+	Athlete
+	RaceCount int    `json:"race_count"`
+	Match     string `json:"match"`
+}
+
+// SearchAthletes handles the GET /athletes/search endpoint.
+func (h *apiHandler) SearchAthletes(c *gin.Context) {
+	search := mongo.AthleteSearch{Query: strings.TrimSpace(c.Query("q"))}
+	if athlete.NormalizeSearch(search.Query) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q query parameter is required"})
+		return
+	}
+	if value := c.Query("gender"); value != "" {
+		// 接受 M/F 或組別的寫法 (例如 "女子組")
+		search.Gender = strings.ToUpper(value)
+		if search.Gender != athlete.GenderMale && search.Gender != athlete.GenderFemale {
+			search.Gender = athlete.NormalizeGender(value)
+		}
+		if search.Gender == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "gender must be M or F"})
+			return
+		}
+	}
+	birthYear, ok := intQuery(c, "birth_year", 0)
+	if !ok || birthYear < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "birth_year must be a positive integer"})
+		return
+	}
+	if birthYear > rocYearOffset {
+		birthYear -= rocYearOffset
+	}
+	search.BirthYear = birthYear
+	limit, ok := intQuery(c, "limit", defaultSearchLimit)
+	if !ok || limit <= 0 || limit > maxSearchLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be an integer between 1 and 100"})
+		return
+	}
+	search.Limit = limit
+
+	ctx := c.Request.Context()
+	if value := c.Query("team_id"); value != "" {
+		teamID, err := bson.ObjectIDFromHex(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid team_id"})
+			return
+		}
+		team, err := h.teamStore.FindTeam(ctx, teamID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve team"})
+			return
+		}
+		if team == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "team not found"})
+			return
+		}
+		// 選手的單位是成績上的寫法，以隊伍的名稱與所有別名比對
+		search.Units = append([]string{team.Name}, team.Aliases...)
+	}
+
+	athletes, err := h.athleteStore.SearchAthletes(ctx, search)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to search athletes"})
+		return
+	}
+	output := make([]AthleteSearchResult, len(athletes))
+	for i, a := range athletes {
+		output[i] = AthleteSearchResult{
+			Athlete: Athlete{
+				ID:           a.ID.Hex(),
+				Name:         a.Name,
+				Names:        a.Names,
+				Gender:       a.Gender,
+				Units:        a.Units,
+				BirthYearMin: a.BirthYearMin,
+				BirthYearMax: a.BirthYearMax,
+			},
+			RaceCount: a.RaceCount,
+			Match:     a.Match(),
+		}
+	}
+	c.JSON(http.StatusOK, output)
+}
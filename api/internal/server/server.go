@@ -1,6 +1,11 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
 	"aquascore/api/internal/db/mongo"
 
 	"github.com/gin-gonic/gin"
@@ -31,9 +36,39 @@ func NewHTTPServer(store *mongo.Stores, analysisServerAddr string) (*Server, err
 	return s, nil
 }
 
-// Start runs the HTTP server on a specific address.
-func (s *Server) Start(addr string) error {
-	return s.router.Run(addr)
+const (
+	// shutdownTimeout 是關閉伺服器時等待處理中請求完成的時間上限
+	shutdownTimeout = 30 * time.Second
+	// readHeaderTimeout 是讀取請求標頭的時間上限，避免緩慢傳送標頭的連線佔用伺服器
+	readHeaderTimeout = 10 * time.Second
+)
+
+// Start runs the HTTP server on a specific address until ctx is cancelled,
+// then waits for in-flight requests to finish.
+func (s *Server) Start(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s.router,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
 func (s *Server) Close() error {
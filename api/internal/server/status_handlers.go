@@ -0,0 +1,136 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatusCounts 是各成績狀態的筆數
+type StatusCounts struct {
+	Total      int `json:"total"`
+	OK         int `json:"ok"`
+	DQ         int `json:"dq"`
+	DNS        int `json:"dns"`
+	DNF        int `json:"dnf"`
+	Scratch    int `json:"scratch"`
+	Exhibition int `json:"exhibition"`
+}
+
+func (s *StatusCounts) add(status crawler.ResultStatus) {
+	s.Total++
+	switch status {
+	case crawler.ResultStatusDQ:
+		s.DQ++
+	case crawler.ResultStatusDNS:
+		s.DNS++
+	case crawler.ResultStatusDNF:
+		s.DNF++
+	case crawler.ResultStatusScratch:
+		s.Scratch++
+	case crawler.ResultStatusExhibition:
+		s.Exhibition++
+	default:
+		s.OK++
+	}
+}
+
+// EventStatusCounts 是選手在一個項目中的成績狀態統計
+type EventStatusCounts struct {
+	Event    string       `json:"event"`
+	Distance int          `json:"distance,omitempty"`
+	Stroke   string       `json:"stroke,omitempty"`
+	Relay    bool         `json:"relay"`
+	Counts   StatusCounts `json:"counts"`
+}
+
+// AthleteStatusCounts 是選手所有成績的狀態統計，events 依項目名稱排序
+type AthleteStatusCounts struct {
+	Counts StatusCounts        `json:"counts"`
+	Events []EventStatusCounts `json:"events"`
+}
+
+// RaceStatusCounts 是一個項目所有成績的狀態統計
+type RaceStatusCounts struct {
+	RaceID    string       `json:"race_id"`
+	EventName string       `json:"event_name"`
+	Counts    StatusCounts `json:"counts"`
+}
+
+// GetAthleteStatusCounts handles the GET /athletes/:athlete/status-counts endpoint.
+func (h *apiHandler) GetAthleteStatusCounts(c *gin.Context) {
+	athlete, ok := h.athleteFilter(c, c.Param("athlete"))
+	if !ok {
+		return
+	}
+	races, err := h.raceStore.GetAllAthleteRaces(c.Request.Context(), athlete)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve athlete races"})
+		return
+	}
+	c.JSON(http.StatusOK, countAthleteStatuses(races))
+}
+
+// GetRaceStatusCounts handles the GET /race/:race_id/status-counts endpoint.
+func (h *apiHandler) GetRaceStatusCounts(c *gin.Context) {
+	race, err := h.raceStore.GetRaceWithResultsByID(c.Request.Context(), c.Param("race_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve race"})
+		return
+	}
+	output := RaceStatusCounts{RaceID: race.ID.Hex(), EventName: race.EventName}
+	for _, result := range race.Results {
+		output.Counts.add(resultStatus(result.Status, result.Note, result.Record > 0))
+	}
+	c.JSON(http.StatusOK, output)
+}
+
+func countAthleteStatuses(races []*models.AggrAthleteJoinRacesFilterByAthlete) AthleteStatusCounts {
+	output := AthleteStatusCounts{Events: []EventStatusCounts{}}
+	events := make(map[string]int)
+	for _, race := range races {
+		status := resultStatus(race.Status, race.Note, race.Record > 0)
+		output.Counts.add(status)
+
+		// 已解析項目的資料以正規化的名稱分組，舊資料沿用原本的項目類型字串
+		label := race.Label()
+		if label == "" {
+			label = race.EventType
+		}
+		i, ok := events[label]
+		if !ok {
+			i = len(output.Events)
+			events[label] = i
+			output.Events = append(output.Events, EventStatusCounts{
+				Event:    label,
+				Distance: race.Distance,
+				Stroke:   race.Stroke,
+				Relay:    race.Relay,
+			})
+		}
+		output.Events[i].Counts.add(status)
+	}
+	slices.SortFunc(output.Events, func(a, b EventStatusCounts) int {
+		return strings.Compare(a.Event, b.Event)
+	})
+	return output
+}
+
+// resultStatus 回傳成績狀態，尚未記錄狀態的舊資料由備註與成績推斷
+func resultStatus(status, note string, hasTime bool) crawler.ResultStatus {
+	if status != "" {
+		return crawler.ResultStatus(status)
+	}
+	parsed, _ := crawler.ParseResultStatus(note, hasTime)
+	return parsed
+}
+
+// hasValidTime 回傳成績是否列入表現分析，犯規、未出賽、未完賽與退賽的成績即使有時間也不列入
+func hasValidTime(record float64, status, note string) bool {
+	return record > 0 && resultStatus(status, note, true).HasValidTime()
+}
@@ -0,0 +1,194 @@
+package server
+
+import (
+	"net/http"
+
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Team 是學校或俱樂部
+type Team struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+// TeamAthlete 是代表隊伍出賽過的選手，尚未比對選手的成績沒有 athlete_id
+type TeamAthlete struct {
+	AthleteID string `json:"athlete_id,omitempty"`
+	Name      string `json:"name"`
+	Results   int    `json:"results"`
+}
+
+// TeamResult 是代表隊伍出賽的一筆成績
+type TeamResult struct {
+	RaceID          string   `json:"race_id"`
+	Year            string   `json:"year"`
+	CompetitionName string   `json:"competition_name"`
+	EventName       string   `json:"event_name"`
+	Distance        int      `json:"distance"`
+	Stroke          string   `json:"stroke"`
+	Relay           bool     `json:"relay"`
+	Round           string   `json:"round"`
+	Athletes        []string `json:"athletes"`
+	Record          float64  `json:"record"`
+	Rank            int      `json:"rank"`
+	Score           int      `json:"score"`
+	Note            string   `json:"note"`
+	Status          string   `json:"status"`
+	StatusReason    string   `json:"status_reason,omitempty"`
+}
+
+// TeamStanding 是隊伍在一場比賽的獎牌數與積分
+type TeamStanding struct {
+	Rank   int    `json:"rank"`
+	TeamID string `json:"team_id,omitempty"`
+	Team   string `json:"team"`
+	Gold   int    `json:"gold"`
+	Silver int    `json:"silver"`
+	Bronze int    `json:"bronze"`
+	Points int    `json:"points"`
+}
+
+// GetTeams handles the GET /teams endpoint.
+func (h *apiHandler) GetTeams(c *gin.Context) {
+	teams, err := h.teamStore.FindTeams(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve teams"})
+		return
+	}
+	output := make([]Team, len(teams))
+	for i, team := range teams {
+		output[i] = mapTeam(team)
+	}
+	c.JSON(http.StatusOK, output)
+}
+
+// GetTeamAthletes handles the GET /teams/:team_id/athletes endpoint.
+func (h *apiHandler) GetTeamAthletes(c *gin.Context) {
+	team, ok := h.findTeam(c)
+	if !ok {
+		return
+	}
+	athletes, err := h.teamStore.GetTeamAthletes(c.Request.Context(), team.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve team athletes"})
+		return
+	}
+	output := make([]TeamAthlete, len(athletes))
+	for i, athlete := range athletes {
+		output[i] = TeamAthlete{
+			AthleteID: hexOrEmpty(athlete.AthleteID),
+			Name:      athlete.Name,
+			Results:   athlete.Results,
+		}
+	}
+	c.JSON(http.StatusOK, output)
+}
+
+// GetTeamResults handles the GET /teams/:team_id/results endpoint.
+func (h *apiHandler) GetTeamResults(c *gin.Context) {
+	team, ok := h.findTeam(c)
+	if !ok {
+		return
+	}
+	results, err := h.teamStore.GetTeamResults(c.Request.Context(), team.ID, c.Query("year"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve team results"})
+		return
+	}
+	output := make([]TeamResult, len(results))
+	for i, result := range results {
+		output[i] = TeamResult{
+			RaceID:          result.RaceID,
+			Year:            result.Year,
+			CompetitionName: result.CompetitionName,
+			EventName:       result.EventName,
+			Distance:        result.Distance,
+			Stroke:          result.Stroke,
+			Relay:           result.Relay,
+			Round:           result.Round,
+			Athletes:        result.Name,
+			Record:          result.Record.Seconds(),
+			Rank:            result.Rank,
+			Score:           result.Score,
+			Note:            result.Note,
+			Status:          string(resultStatus(result.Status, result.Note, result.Record > 0)),
+			StatusReason:    result.StatusReason,
+		}
+	}
+	c.JSON(http.StatusOK, output)
+}
+
+// GetTeamStandings handles the GET /teams/standings endpoint.
+func (h *apiHandler) GetTeamStandings(c *gin.Context) {
+	year := c.Query("year")
+	competitionName := c.Query("competition_name")
+	if year == "" || competitionName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "competition_name and year query parameters are required"})
+		return
+	}
+	standings, err := h.teamStore.GetTeamStandings(c.Request.Context(), year, competitionName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve team standings"})
+		return
+	}
+	output := make([]TeamStanding, len(standings))
+	for i, standing := range standings {
+		output[i] = TeamStanding{
+			Rank:   i + 1,
+			TeamID: hexOrEmpty(standing.TeamID),
+			Team:   standing.Team,
+			Gold:   standing.Gold,
+			Silver: standing.Silver,
+			Bronze: standing.Bronze,
+			Points: standing.Points,
+		}
+		// 積分與獎牌數相同時名次並列
+		if i > 0 && sameStanding(standings[i-1], standing) {
+			output[i].Rank = output[i-1].Rank
+		}
+	}
+	c.JSON(http.StatusOK, output)
+}
+
+// findTeam 由路徑參數找出隊伍，找不到時回應錯誤並回傳 false
+func (h *apiHandler) findTeam(c *gin.Context) (*models.Team, bool) {
+	id, err := bson.ObjectIDFromHex(c.Param("team_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid team_id"})
+		return nil, false
+	}
+	team, err := h.teamStore.FindTeam(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve team"})
+		return nil, false
+	}
+	if team == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "team not found"})
+		return nil, false
+	}
+	return team, true
+}
+
+func sameStanding(a, b *models.AggrTeamStanding) bool {
+	return a.Points == b.Points && a.Gold == b.Gold && a.Silver == b.Silver && a.Bronze == b.Bronze
+}
+
+func mapTeam(team *models.Team) Team {
+	return Team{
+		ID:      team.ID.Hex(),
+		Name:    team.Name,
+		Aliases: team.Aliases,
+	}
+}
+
+func hexOrEmpty(id bson.ObjectID) string {
+	if id.IsZero() {
+		return ""
+	}
+	return id.Hex()
+}
@@ -0,0 +1,365 @@
+package sheet
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"aquascore/api/internal/athlete"
+	"aquascore/api/internal/crawler"
+	"aquascore/api/internal/db/mongo/models"
+)
+
+const (
+	// SourceName 是由試算表匯入的 Race 的來源名稱
+	SourceName = "spreadsheet"
+
+	maleLabel   = "男子組"
+	femaleLabel = "女子組"
+	mixedLabel  = "混合組"
+
+	rocYearOffset = 1911
+	// minExcelSerial 以上的整數視為 Excel 的日期序號 (1902 年以後)
+	minExcelSerial = 1000
+)
+
+var (
+	competitionYearReg = regexp.MustCompile(`^(\d+)年(.*)`)
+	dateReg            = regexp.MustCompile(`^(\d{2,4})[/.\-](\d{1,2})[/.\-](\d{1,2})$`)
+	nameSeparatorReg   = regexp.MustCompile(`\s*[、,，/／;；]\s*`)
+
+	// excelEpoch 是 Excel 日期序號的起點 (序號 1 為 1900-01-01，並沿用 1900 年是閏年的錯誤)
+	excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+)
+
+// columns 是每個欄位在列中的索引，-1 代表沒有這個欄位
+type columns struct {
+	event, ageGroup, gender, round, date int
+	name, unit, time, rank, score, note  int
+}
+
+// Races 將試算表表頭以下的每一列轉成一筆成績，依年齡組、性別、項目、賽次與日期分成 Race。
+// 無法轉換或與前面重複的列不會中斷匯入，而是略過並回傳在 invalid 中；找不到對應的表頭時回傳 error
+func Races(rows [][]string, mapping Mapping) (races []*crawler.Race, invalid []crawler.UnparsedRow, err error) {
+	if err := mapping.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid mapping: %w", err)
+	}
+	headerIndex := mapping.headerIndex()
+	if headerIndex >= len(rows) {
+		return nil, nil, fmt.Errorf("試算表只有 %d 列，找不到第 %d 列的表頭", len(rows), headerIndex+1)
+	}
+	cols, err := findColumns(rows[headerIndex], mapping.Columns)
+	if err != nil {
+		return nil, nil, err
+	}
+	var defaultDate time.Time
+	if mapping.Date != "" {
+		if defaultDate, err = parseDate(mapping.Date); err != nil {
+			return nil, nil, fmt.Errorf("invalid date: %w", err)
+		}
+	}
+	year, competition := splitCompetition(mapping.Competition)
+	if mapping.Year != "" {
+		year = mapping.Year
+	}
+
+	p := &rowParser{mapping: mapping, cols: cols, defaultDate: defaultDate, year: year, competition: competition}
+	byKey := make(map[string]*crawler.Race)
+	seen := make(map[string]int) // 項目與選手 => 第一次出現的列
+	for i := headerIndex + 1; i < len(rows); i++ {
+		row := rows[i]
+		if isBlankRow(row) {
+			continue
+		}
+		race, result, err := p.parse(row)
+		if err == nil {
+			key := RaceKey(race) + "|" + result.Unit + "|" + strings.Join(result.Name, "、")
+			if first, ok := seen[key]; ok {
+				err = fmt.Errorf("與第 %d 列的成績重複", first)
+			} else {
+				seen[key] = i + 1
+			}
+		}
+		if err != nil {
+			invalid = append(invalid, crawler.UnparsedRow{Row: i + 1, Text: rowText(row), Reason: err.Error()})
+			continue
+		}
+		// 與儲存時的自然鍵相同，不同日期的同一個項目會寫入同一個 race，日期以第一列為準
+		key := RaceKey(race)
+		existing, ok := byKey[key]
+		if !ok {
+			byKey[key] = race
+			races = append(races, race)
+			existing = race
+		}
+		existing.Results = append(existing.Results, result)
+	}
+	return races, invalid, nil
+}
+
+// RaceKey 回傳項目儲存時的自然鍵，見 models.Race.NaturalKey
+func RaceKey(race *crawler.Race) string {
+	return models.RaceKey(race.Source, race.Year, race.CompetitionName, race.EventName,
+		string(race.Event.Round), race.Type)
+}
+
+func findColumns(header []string, mapped Columns) (columns, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if _, ok := index[name]; !ok && name != "" {
+			index[name] = i
+		}
+	}
+	var errs []error
+	lookup := func(name string) int {
+		if name == "" {
+			return -1
+		}
+		i, ok := index[strings.TrimSpace(name)]
+		if !ok {
+			errs = append(errs, fmt.Errorf("表頭中找不到欄位 %q", name))
+			return -1
+		}
+		return i
+	}
+	cols := columns{
+		event:    lookup(mapped.Event),
+		ageGroup: lookup(mapped.AgeGroup),
+		gender:   lookup(mapped.Gender),
+		round:    lookup(mapped.Round),
+		date:     lookup(mapped.Date),
+		name:     lookup(mapped.Name),
+		unit:     lookup(mapped.Unit),
+		time:     lookup(mapped.Time),
+		rank:     lookup(mapped.Rank),
+		score:    lookup(mapped.Score),
+		note:     lookup(mapped.Note),
+	}
+	return cols, errors.Join(errs...)
+}
+
+type rowParser struct {
+	mapping     Mapping
+	cols        columns
+	defaultDate time.Time
+	year        string
+	competition string
+}
+
+// parse 轉換一列，回傳的 Race 只有項目資料，成績另外回傳
+func (p *rowParser) parse(row []string) (*crawler.Race, *crawler.RaceResult, error) {
+	race, err := p.parseRace(row)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := p.parseResult(row, race.Event)
+	if err != nil {
+		return nil, nil, err
+	}
+	return race, result, nil
+}
+
+func (p *rowParser) parseRace(row []string) (*crawler.Race, error) {
+	eventText := cell(row, p.cols.event)
+	if eventText == "" {
+		return nil, errors.New("缺少項目")
+	}
+	roundLabel := cell(row, p.cols.round)
+	if roundLabel == "" {
+		roundLabel = crawler.ParseRound(eventText).Label()
+		if roundLabel != "" && strings.Contains(eventText, roundLabel) {
+			eventText = strings.TrimSpace(strings.Replace(eventText, roundLabel, "", 1))
+		}
+	}
+	ageGroup := strings.Join(strings.Fields(cell(row, p.cols.ageGroup)), "")
+	gender := genderLabel(cell(row, p.cols.gender))
+	eventName := joinNonEmpty(ageGroup+gender, eventText, roundLabel)
+	event := crawler.ParseEvent(eventName)
+	if event.Distance == 0 || event.Stroke == "" {
+		return nil, fmt.Errorf("無法解析項目 %q", eventText)
+	}
+
+	date := p.defaultDate
+	if text := cell(row, p.cols.date); text != "" {
+		d, err := parseDate(text)
+		if err != nil {
+			return nil, err
+		}
+		date = d
+	}
+	if date.IsZero() {
+		return nil, errors.New("缺少比賽日期")
+	}
+	year := p.year
+	if year == "" {
+		year = strconv.Itoa(date.Year() - rocYearOffset)
+	}
+	return &crawler.Race{
+		Source:          SourceName,
+		Organizer:       p.mapping.Organizer,
+		Venue:           p.mapping.Venue,
+		Year:            year,
+		Type:            roundLabel,
+		CompetitionName: p.competition,
+		Gender:          gender,
+		PoolType:        p.mapping.PoolType,
+		AgeGroup:        ageGroup,
+		EventType:       eventText,
+		EventName:       eventName,
+		Event:           event,
+		Time:            date,
+	}, nil
+}
+
+func (p *rowParser) parseResult(row []string, event crawler.Event) (*crawler.RaceResult, error) {
+	name := cell(row, p.cols.name)
+	if name == "" {
+		return nil, errors.New("缺少選手姓名")
+	}
+	result := &crawler.RaceResult{
+		Unit: cell(row, p.cols.unit),
+		Name: []string{name},
+		Note: cell(row, p.cols.note),
+	}
+	statusText := result.Note
+	if text := cell(row, p.cols.time); text != "" {
+		record, err := crawler.ParseSwimTime(text)
+		switch {
+		case err == nil:
+			result.Record = record
+		case crawler.IsResultStatusText(text):
+			statusText = text + " " + statusText
+		default:
+			return nil, err
+		}
+	}
+	result.Status, result.StatusReason = crawler.ParseResultStatus(statusText, result.Record > 0)
+
+	if !event.Round.IsQualifier() {
+		var err error
+		if result.Rank, err = parseNumber(cell(row, p.cols.rank)); err != nil {
+			return nil, fmt.Errorf("名次格式錯誤: %w", err)
+		}
+		if result.Score, err = parseNumber(cell(row, p.cols.score)); err != nil {
+			return nil, fmt.Errorf("積分格式錯誤: %w", err)
+		}
+	}
+
+	if event.Relay {
+		result.Team = result.Unit
+		result.Name = splitSwimmers(name)
+		result.Legs = crawler.RelayLegs(event, result.Name)
+	}
+	return result, nil
+}
+
+// splitCompetition 將 "113年臺南市議長盃游泳錦標賽" 分成民國年與競賽名稱，沒有年份時 year 為空
+func splitCompetition(name string) (year, competition string) {
+	name = strings.ReplaceAll(name, " ", "")
+	if matches := competitionYearReg.FindStringSubmatch(name); matches != nil {
+		return matches[1], matches[2]
+	}
+	return "", name
+}
+
+// genderLabel 將性別欄位轉成與 CTSA 相同的組別寫法，例如 "男"、"M" 轉成 "男子組"
+func genderLabel(gender string) string {
+	switch strings.ToUpper(gender) {
+	case "":
+		return ""
+	case athlete.GenderMale:
+		return maleLabel
+	case athlete.GenderFemale:
+		return femaleLabel
+	case "X":
+		return mixedLabel
+	}
+	if strings.HasSuffix(gender, "組") {
+		return gender
+	}
+	switch athlete.NormalizeGender(gender) {
+	case athlete.GenderMale:
+		return maleLabel
+	case athlete.GenderFemale:
+		return femaleLabel
+	}
+	if strings.Contains(gender, "混合") {
+		return mixedLabel
+	}
+	return gender
+}
+
+// parseDate 解析 "2024-07-20"、"2024/7/20"、"113/07/20" (民國年)、"20240720" 或 Excel 的日期序號
+func parseDate(text string) (time.Time, error) {
+	if matches := dateReg.FindStringSubmatch(text); matches != nil {
+		y, _ := strconv.Atoi(matches[1])
+		m, _ := strconv.Atoi(matches[2])
+		d, _ := strconv.Atoi(matches[3])
+		if y < rocYearOffset {
+			y += rocYearOffset
+		}
+		date := time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+		if date.Month() != time.Month(m) || date.Day() != d {
+			return time.Time{}, fmt.Errorf("日期格式錯誤: %q", text)
+		}
+		return date, nil
+	}
+	if date, err := time.Parse("20060102", text); err == nil {
+		return date, nil
+	}
+	if serial, err := strconv.ParseFloat(text, 64); err == nil && serial >= minExcelSerial {
+		return excelEpoch.AddDate(0, 0, int(serial)), nil
+	}
+	return time.Time{}, fmt.Errorf("日期格式錯誤: %q", text)
+}
+
+// parseNumber 解析名次或積分，空白或 "-" 代表沒有
+func parseNumber(text string) (int32, error) {
+	if text == "" || text == "-" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(text, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%q 不是數字", text)
+	}
+	return int32(n), nil
+}
+
+// splitSwimmers 將接力的選手欄位分成各棒，以 "、"、","、"/" 或空白分隔
+func splitSwimmers(names string) []string {
+	swimmers := nameSeparatorReg.Split(names, -1)
+	if len(swimmers) == 1 {
+		swimmers = strings.Fields(names)
+	}
+	return slices.DeleteFunc(swimmers, func(s string) bool { return s == "" })
+}
+
+func cell(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func isBlankRow(row []string) bool {
+	return !slices.ContainsFunc(row, func(s string) bool { return strings.TrimSpace(s) != "" })
+}
+
+func rowText(row []string) string {
+	cells := make([]string, 0, len(row))
+	for _, c := range row {
+		if c = strings.TrimSpace(c); c != "" {
+			cells = append(cells, c)
+		}
+	}
+	return strings.Join(cells, " ")
+}
+
+func joinNonEmpty(parts ...string) string {
+	return strings.Join(slices.DeleteFunc(parts, func(s string) bool { return s == "" }), " ")
+}
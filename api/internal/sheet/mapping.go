@@ -0,0 +1,68 @@
+// Package sheet 依欄位對應設定將試算表 (.csv/.xlsx) 的成績轉成 crawler.Race，
+// 用於只以試算表公布成績、沒有放在網路上的比賽
+package sheet
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Mapping 是試算表的欄位對應設定，通常由 YAML 檔讀入：
+// 整份檔案共用的資料 (競賽名稱、日期...) 直接寫值，每一列的資料寫對應的表頭名稱
+type Mapping struct {
+	Competition string  `mapstructure:"competition"` // 競賽名稱，可包含民國年，例如 "113年臺南市議長盃游泳錦標賽"
+	Year        string  `mapstructure:"year"`        // 民國年，空白時由競賽名稱或日期決定
+	Organizer   string  `mapstructure:"organizer"`   // 主辦單位
+	Venue       string  `mapstructure:"venue"`       // 比賽場地
+	PoolType    string  `mapstructure:"pool_type"`   // 水道，見 crawler.PoolTypeShortCourse/PoolTypeLongCourse
+	Date        string  `mapstructure:"date"`        // 沒有日期欄位時使用的比賽日期
+	Sheet       string  `mapstructure:"sheet"`       // .xlsx 的工作表名稱，空白時為第一個工作表
+	HeaderRow   int     `mapstructure:"header_row"`  // 表頭所在的列 (從 1 開始)，0 代表第 1 列
+	Columns     Columns `mapstructure:"columns"`
+}
+
+// Columns 是每個欄位對應的表頭名稱，空白代表試算表沒有這個欄位
+type Columns struct {
+	Event    string `mapstructure:"event"`     // 項目，例如 "100公尺自由式" 或 "11&12歲級男子組 100公尺自由式 計時決賽" (必填)
+	AgeGroup string `mapstructure:"age_group"` // 年齡組，例如 "11&12歲級"
+	Gender   string `mapstructure:"gender"`    // 性別組別，"男"、"女子組"、"M"、"F" 都可以
+	Round    string `mapstructure:"round"`     // 賽次，空白時由項目名稱判斷
+	Date     string `mapstructure:"date"`      // 比賽日期
+	Name     string `mapstructure:"name"`      // 選手姓名，接力以 "、"、","、"/" 分隔各棒 (必填)
+	Unit     string `mapstructure:"unit"`      // 單位
+	Time     string `mapstructure:"time"`      // 成績，也可以是 "犯規"、"DNS" 等狀態 (必填)
+	Rank     string `mapstructure:"rank"`      // 名次
+	Score    string `mapstructure:"score"`     // 積分
+	Note     string `mapstructure:"note"`      // 備註
+}
+
+// Validate 檢查必填的設定
+func (m *Mapping) Validate() error {
+	var errs []error
+	if m.Competition == "" {
+		errs = append(errs, errors.New("competition is required"))
+	}
+	required := []struct{ field, column string }{
+		{"event", m.Columns.Event}, {"name", m.Columns.Name}, {"time", m.Columns.Time},
+	}
+	for _, r := range required {
+		if r.column == "" {
+			errs = append(errs, fmt.Errorf("columns.%s is required", r.field))
+		}
+	}
+	if m.Columns.Date == "" && m.Date == "" {
+		errs = append(errs, errors.New("date or columns.date is required"))
+	}
+	if m.HeaderRow < 0 {
+		errs = append(errs, fmt.Errorf("invalid header_row %d", m.HeaderRow))
+	}
+	return errors.Join(errs...)
+}
+
+// headerIndex 回傳表頭的列序 (從 0 開始)
+func (m *Mapping) headerIndex() int {
+	if m.HeaderRow == 0 {
+		return 0
+	}
+	return m.HeaderRow - 1
+}
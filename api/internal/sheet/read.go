@@ -0,0 +1,215 @@
+package sheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const lettersInAlphabet = 26
+
+// utf8BOM 是 Excel 另存 CSV (UTF-8) 時加在檔案開頭的 BOM
+var utf8BOM = []byte("\xEF\xBB\xBF")
+
+// Read 依副檔名讀取 .csv 或 .xlsx 的所有列，sheetName 只用於 .xlsx，空白時為第一個工作表
+func Read(name string, data []byte, sheetName string) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return readCSV(data)
+	case ".xlsx":
+		return readXLSX(data, sheetName)
+	default:
+		return nil, fmt.Errorf("不支援的檔案格式: %s (只支援 .csv 與 .xlsx)", name)
+	}
+}
+
+func readCSV(data []byte) ([][]string, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read csv fail: %w", err)
+	}
+	return rows, nil
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText 是共用字串或內嵌字串，格式化過的文字會分成多個 r (run)
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	var b strings.Builder
+	b.WriteString(t.T)
+	for _, run := range t.Runs {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX 讀取 .xlsx 工作表中每個儲存格的值，只取值不計算公式，日期為 Excel 的序號
+func readXLSX(data []byte, sheetName string) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open xlsx fail: %w", err)
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := xlsxSheetPath(files, sheetName)
+	if err != nil {
+		return nil, err
+	}
+	var shared xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeZipXML(f, &shared); err != nil {
+			return nil, err
+		}
+	}
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("xlsx 中沒有 %s", sheetPath)
+	}
+	var worksheet xlsxWorksheet
+	if err := decodeZipXML(f, &worksheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(worksheet.Rows))
+	for _, r := range worksheet.Rows {
+		var row []string
+		for _, c := range r.Cells {
+			value := c.Value
+			switch c.Type {
+			case "s":
+				i, err := strconv.Atoi(c.Value)
+				if err != nil || i < 0 || i >= len(shared.Items) {
+					return nil, fmt.Errorf("儲存格 %s 的共用字串索引錯誤: %q", c.Ref, c.Value)
+				}
+				value = shared.Items[i].String()
+			case "inlineStr":
+				value = c.Inline.String()
+			}
+			col := columnIndex(c.Ref)
+			if col < len(row) {
+				col = len(row)
+			}
+			for len(row) < col {
+				row = append(row, "")
+			}
+			row = append(row, value)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// xlsxSheetPath 由 workbook.xml 與其關聯找出工作表在壓縮檔中的路徑
+func xlsxSheetPath(files map[string]*zip.File, sheetName string) (string, error) {
+	var workbook xlsxWorkbook
+	f, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", errors.New("xlsx 中沒有 xl/workbook.xml")
+	}
+	if err := decodeZipXML(f, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("xlsx 中沒有工作表")
+	}
+	rID := workbook.Sheets[0].RID
+	if sheetName != "" {
+		rID = ""
+		for _, sheet := range workbook.Sheets {
+			if sheet.Name == sheetName {
+				rID = sheet.RID
+			}
+		}
+		if rID == "" {
+			return "", fmt.Errorf("xlsx 中沒有工作表 %q", sheetName)
+		}
+	}
+
+	var rels xlsxRelationships
+	if f, ok := files["xl/_rels/workbook.xml.rels"]; ok {
+		if err := decodeZipXML(f, &rels); err != nil {
+			return "", err
+		}
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != rID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", fmt.Errorf("xlsx 中找不到工作表 %s 的檔案", rID)
+}
+
+func decodeZipXML(f *zip.File, v any) error {
+	r, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("open %s fail: %w", f.Name, err)
+	}
+	defer r.Close()
+	if err := xml.NewDecoder(r).Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("decode %s fail: %w", f.Name, err)
+	}
+	return nil
+}
+
+// columnIndex 將儲存格位置 (例如 "C12") 的欄轉成從 0 開始的索引，沒有位置時回傳 -1
+func columnIndex(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*lettersInAlphabet + int(r-'A') + 1
+	}
+	return col - 1
+}
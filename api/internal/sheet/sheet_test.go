@@ -0,0 +1,179 @@
+package sheet
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"aquascore/api/internal/crawler"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMapping() Mapping {
+	return Mapping{
+		Competition: "113年臺南市議長盃游泳錦標賽",
+		Organizer:   "臺南市體育總會游泳委員會",
+		PoolType:    crawler.PoolTypeLongCourse,
+		HeaderRow:   2,
+		Columns: Columns{
+			Date: "日期", AgeGroup: "組別", Gender: "性別", Event: "項目", Name: "姓名",
+			Unit: "單位", Time: "成績", Rank: "名次", Note: "備註",
+		},
+	}
+}
+
+func readFixture(t *testing.T, name, sheetName string) [][]string {
+	t.Helper()
+	data, err := os.ReadFile("test_file/" + name)
+	require.NoError(t, err)
+	rows, err := Read(name, data, sheetName)
+	require.NoError(t, err)
+	return rows
+}
+
+func TestRaces_csv(t *testing.T) {
+	races, invalid, err := Races(readFixture(t, "results.csv", ""), testMapping())
+	require.NoError(t, err)
+	require.Len(t, races, 3)
+
+	race := races[0]
+	assert.Equal(t, SourceName, race.Source)
+	assert.Equal(t, "113", race.Year)
+	assert.Equal(t, "臺南市議長盃游泳錦標賽", race.CompetitionName)
+	assert.Equal(t, "臺南市體育總會游泳委員會", race.Organizer)
+	assert.Equal(t, crawler.PoolTypeLongCourse, race.PoolType)
+	assert.Equal(t, "11&12歲級", race.AgeGroup)
+	assert.Equal(t, "男子組", race.Gender)
+	assert.Equal(t, "100公尺自由式", race.EventType)
+	assert.Equal(t, "計時決賽", race.Type)
+	assert.Equal(t, "11&12歲級男子組 100公尺自由式 計時決賽", race.EventName)
+	assert.Equal(t, crawler.Event{Distance: 100, Stroke: crawler.StrokeFreestyle, Round: crawler.RoundTimedFinal},
+		race.Event)
+	assert.Equal(t, time.Date(2024, 7, 20, 0, 0, 0, 0, time.UTC), race.Time)
+	// 不同日期的同一個項目與儲存時的自然鍵相同，合併成同一個 race
+	require.Len(t, race.Results, 3)
+	assert.Equal(t, &crawler.RaceResult{
+		Unit:   "大同高中",
+		Name:   []string{"王小明"},
+		Record: time.Minute + 2*time.Second + 350*time.Millisecond,
+		Rank:   1,
+		Status: crawler.ResultStatusOK,
+	}, race.Results[0])
+	dq := race.Results[1]
+	assert.Equal(t, time.Duration(0), dq.Record)
+	assert.Equal(t, crawler.ResultStatusDQ, dq.Status)
+	assert.Equal(t, "蛙式踢腿", dq.StatusReason)
+	assert.Equal(t, []string{"黃五"}, race.Results[2].Name)
+
+	assert.Equal(t, "11&12歲級女子組 100公尺自由式 計時決賽", races[1].EventName)
+
+	relay := races[2]
+	assert.Equal(t, time.Date(2024, 7, 21, 0, 0, 0, 0, time.UTC), relay.Time)
+	require.Len(t, relay.Results, 1)
+	assert.Equal(t, "大同高中", relay.Results[0].Team)
+	assert.Equal(t, []string{"王小明", "李大華", "陳志強", "林建宏"}, relay.Results[0].Name)
+	require.Len(t, relay.Results[0].Legs, 4)
+	assert.Equal(t, crawler.StrokeBackstroke, relay.Results[0].Legs[0].Stroke)
+
+	require.Len(t, invalid, 5)
+	assert.Equal(t, 8, invalid[0].Row)
+	assert.Equal(t, "缺少選手姓名", invalid[0].Reason)
+	assert.Equal(t, 9, invalid[1].Row)
+	assert.Contains(t, invalid[1].Reason, "無法解析項目")
+	assert.Equal(t, 10, invalid[2].Row)
+	assert.Contains(t, invalid[2].Reason, "時間格式錯誤")
+	assert.Equal(t, 11, invalid[3].Row)
+	assert.Contains(t, invalid[3].Reason, "名次格式錯誤")
+	assert.Equal(t, 12, invalid[4].Row)
+	assert.Equal(t, "與第 3 列的成績重複", invalid[4].Reason)
+}
+
+// 項目名稱只差在空白的列與儲存時的自然鍵相同，分在同一個 race
+func TestRaces_groupsByNaturalKey(t *testing.T) {
+	mapping := testMapping()
+	mapping.HeaderRow = 0
+	mapping.Date = "2024-07-20"
+	mapping.Columns = Columns{AgeGroup: "組別", Gender: "性別", Event: "項目", Name: "姓名", Time: "成績"}
+	rows := [][]string{
+		{"組別", "性別", "項目", "姓名", "成績"},
+		{"11&12歲級", "男", "100公尺自由式 計時決賽", "王小明", "1:02.35"},
+		{"11&12歲級", "男", "100公尺自由式  計時決賽", "李大華", "1:03.00"},
+	}
+	races, invalid, err := Races(rows, mapping)
+	require.NoError(t, err)
+	assert.Empty(t, invalid)
+	require.Len(t, races, 1)
+	assert.Len(t, races[0].Results, 2)
+}
+
+func TestRaces_xlsx(t *testing.T) {
+	mapping := testMapping()
+	mapping.HeaderRow = 0
+	mapping.Sheet = "成績"
+	mapping.Columns.Note = ""
+	races, invalid, err := Races(readFixture(t, "results.xlsx", mapping.Sheet), mapping)
+	require.NoError(t, err)
+	assert.Empty(t, invalid)
+	require.Len(t, races, 1)
+	assert.Equal(t, time.Date(2024, 7, 20, 0, 0, 0, 0, time.UTC), races[0].Time)
+	require.Len(t, races[0].Results, 2)
+	assert.Equal(t, []string{"王小明"}, races[0].Results[0].Name)
+	assert.Equal(t, "大同高中", races[0].Results[0].Unit)
+	assert.Equal(t, time.Minute+2*time.Second+350*time.Millisecond, races[0].Results[0].Record)
+	assert.Equal(t, int32(1), races[0].Results[0].Rank)
+	assert.Equal(t, []string{"李大華"}, races[0].Results[1].Name)
+	assert.Empty(t, races[0].Results[1].Unit)
+	assert.Equal(t, crawler.ResultStatusDQ, races[0].Results[1].Status)
+
+	rows := readFixture(t, "results.xlsx", "")
+	assert.Equal(t, [][]string{{"說明"}}, rows)
+	data, err := os.ReadFile("test_file/results.xlsx")
+	require.NoError(t, err)
+	_, err = Read("results.xlsx", data, "不存在")
+	assert.Error(t, err)
+}
+
+func TestRaces_invalidMapping(t *testing.T) {
+	rows := readFixture(t, "results.csv", "")
+	_, _, err := Races(rows, Mapping{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "competition is required")
+	assert.Contains(t, err.Error(), "columns.event is required")
+
+	mapping := testMapping()
+	mapping.Columns.Score = "積分"
+	_, _, err = Races(rows, mapping)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"積分"`)
+}
+
+func TestParseDate(t *testing.T) {
+	expected := time.Date(2024, 7, 20, 0, 0, 0, 0, time.UTC)
+	for _, text := range []string{"2024-07-20", "2024/7/20", "113/07/20", "113.7.20", "20240720", "45493"} {
+		d, err := parseDate(text)
+		require.NoError(t, err, text)
+		assert.Equal(t, expected, d, text)
+	}
+	for _, text := range []string{"2024/02/30", "七月二十日", "12"} {
+		_, err := parseDate(text)
+		assert.Error(t, err, text)
+	}
+}
+
+func TestGenderLabel(t *testing.T) {
+	assert.Equal(t, "男子組", genderLabel("男"))
+	assert.Equal(t, "男子組", genderLabel("m"))
+	assert.Equal(t, "女子組", genderLabel("女生"))
+	assert.Equal(t, "女子組", genderLabel("女子組"))
+	assert.Equal(t, "混合組", genderLabel("X"))
+	assert.Empty(t, genderLabel(""))
+}
+
+func TestColumnIndex(t *testing.T) {
+	assert.Equal(t, 0, columnIndex("A1"))
+	assert.Equal(t, 2, columnIndex("C12"))
+	assert.Equal(t, 26, columnIndex("AA3"))
+	assert.Equal(t, -1, columnIndex(""))
+}
@@ -0,0 +1,38 @@
+package team
+
+import (
+	"strings"
+	"unicode"
+)
+
+// fullWidthOffset 是全形 ASCII (U+FF01~U+FF5E) 與半形字元的差距
+const fullWidthOffset = 0xFEE0
+
+// abbreviations 將學校的全名改為 CTSA 成績單上常見的簡稱，較長的名稱必須在前面
+var abbreviations = strings.NewReplacer(
+	"高級中等學校", "高中",
+	"高級中學", "高中",
+	"國民中學", "國中",
+	"國民小學", "國小",
+	"台", "臺",
+)
+
+// NormalizeKey 回傳比對隊伍名稱用的鍵：全形轉半形、去除空白、「台」改為「臺」、
+// 學校全名改為簡稱並去掉結尾的「游泳隊」，例如 "台北市 中山國民中學游泳隊" 與 "臺北市中山國中" 相同
+func NormalizeKey(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case unicode.IsSpace(r):
+			continue
+		case r >= '！' && r <= '～':
+			r -= fullWidthOffset
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	key := abbreviations.Replace(b.String())
+	if trimmed := strings.TrimSuffix(key, "游泳隊"); trimmed != "" {
+		key = trimmed
+	}
+	return key
+}
@@ -0,0 +1,106 @@
+package team
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"aquascore/api/internal/db/mongo/models"
+)
+
+// Store 是 Normalizer 需要的隊伍資料存取，由 mongo.TeamStore 實作
+type Store interface {
+	// FindTeamByKey 以 NormalizeKey 的結果找出隊伍，找不到時回傳 nil
+	FindTeamByKey(ctx context.Context, key string) (*models.Team, error)
+	SaveTeam(ctx context.Context, team *models.Team) error
+}
+
+// Alias 是設定檔 team.aliases 中的一筆規則，Names 中的任何寫法都視為同一個隊伍 Name
+type Alias struct {
+	Name  string   `mapstructure:"name"`
+	Names []string `mapstructure:"names"`
+}
+
+// Normalizer 將成績上的單位名稱對應到 team，沒有相符的隊伍時建立新的隊伍
+type Normalizer struct {
+	store Store
+	// canonical 是別名的鍵對應到的正式名稱
+	canonical map[string]string
+	// mu 讓同一個單位不會因為同時爬取多個項目而建立重複的隊伍
+	mu sync.Mutex
+}
+
+// NewNormalizer 建立 Normalizer，同一個寫法被指定給不同隊伍時回傳錯誤
+func NewNormalizer(store Store, aliases []Alias) (*Normalizer, error) {
+	canonical := make(map[string]string)
+	for _, alias := range aliases {
+		name := strings.TrimSpace(alias.Name)
+		if name == "" {
+			return nil, fmt.Errorf("team alias %v has empty name", alias.Names)
+		}
+		for _, spelling := range append([]string{name}, alias.Names...) {
+			key := NormalizeKey(spelling)
+			if existing, ok := canonical[key]; ok && existing != name {
+				return nil, fmt.Errorf("team alias %q is used by both %q and %q", spelling, existing, name)
+			}
+			canonical[key] = name
+		}
+	}
+	return &Normalizer{store: store, canonical: canonical}, nil
+}
+
+// CanonicalName 回傳設定檔中單位對應的正式名稱，沒有設定時回傳原本的名稱
+func (n *Normalizer) CanonicalName(unit string) string {
+	unit = strings.TrimSpace(unit)
+	if canonical, ok := n.canonical[NormalizeKey(unit)]; ok {
+		return canonical
+	}
+	return unit
+}
+
+// Resolve 回傳單位所屬的隊伍，並記錄這個單位的寫法；單位為空時回傳 nil
+func (n *Normalizer) Resolve(ctx context.Context, unit string) (*models.Team, error) {
+	unit = strings.TrimSpace(unit)
+	if unit == "" {
+		return nil, nil
+	}
+	name := n.CanonicalName(unit)
+	key := NormalizeKey(unit)
+	nameKey := NormalizeKey(name)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	team, err := n.store.FindTeamByKey(ctx, nameKey)
+	if err != nil {
+		return nil, fmt.Errorf("find team %s fail: %w", name, err)
+	}
+	if team == nil && key != nameKey {
+		if team, err = n.store.FindTeamByKey(ctx, key); err != nil {
+			return nil, fmt.Errorf("find team %s fail: %w", unit, err)
+		}
+	}
+	if team == nil {
+		team = models.NewTeam()
+		team.Name = name
+		team.CreatedAt = time.Now()
+	} else if slices.Contains(team.Keys, nameKey) && slices.Contains(team.Keys, key) &&
+		slices.Contains(team.Aliases, unit) {
+		return team, nil
+	}
+	for _, k := range []string{nameKey, key} {
+		if !slices.Contains(team.Keys, k) {
+			team.Keys = append(team.Keys, k)
+		}
+	}
+	if !slices.Contains(team.Aliases, unit) {
+		team.Aliases = append(team.Aliases, unit)
+	}
+	team.UpdatedAt = time.Now()
+	if err := n.store.SaveTeam(ctx, team); err != nil {
+		return nil, fmt.Errorf("save team %s fail: %w", name, err)
+	}
+	return team, nil
+}
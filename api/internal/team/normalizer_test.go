@@ -0,0 +1,94 @@
+package team
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"aquascore/api/internal/db/mongo/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeKey(t *testing.T) {
+	assert.Equal(t, "臺北市中山國中", NormalizeKey("台北市 中山國民中學"))
+	assert.Equal(t, "臺北市中山國中", NormalizeKey("臺北市中山國中游泳隊"))
+	assert.Equal(t, "新竹市立建華國中", NormalizeKey("新竹市立建華國民中學"))
+	assert.Equal(t, "高雄市立新莊高中", NormalizeKey("高雄市立新莊高級中學"))
+	assert.Equal(t, "abc俱樂部", NormalizeKey("ＡＢＣ　俱樂部"))
+	assert.Equal(t, "游泳隊", NormalizeKey("游泳隊"))
+}
+
+type memoryStore struct {
+	teams []*models.Team
+	saved int
+}
+
+func (m *memoryStore) FindTeamByKey(_ context.Context, key string) (*models.Team, error) {
+	for _, team := range m.teams {
+		if slices.Contains(team.Keys, key) {
+			return team, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryStore) SaveTeam(_ context.Context, team *models.Team) error {
+	m.saved++
+	for i, t := range m.teams {
+		if t.ID == team.ID {
+			m.teams[i] = team
+			return nil
+		}
+	}
+	m.teams = append(m.teams, team)
+	return nil
+}
+
+func TestNormalizer_Resolve(t *testing.T) {
+	store := &memoryStore{}
+	n, err := NewNormalizer(store, []Alias{
+		{Name: "臺北市立中山國中", Names: []string{"北市中山", "中山國中(北市)"}},
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	team, err := n.Resolve(ctx, "")
+	require.NoError(t, err)
+	assert.Nil(t, team)
+
+	a, err := n.Resolve(ctx, "台北市立中山國民中學")
+	require.NoError(t, err)
+	b, err := n.Resolve(ctx, "臺北市立中山國中")
+	require.NoError(t, err)
+	assert.Equal(t, a.ID, b.ID)
+
+	// 設定檔的別名使用正式名稱
+	c, err := n.Resolve(ctx, "北市中山")
+	require.NoError(t, err)
+	assert.Equal(t, a.ID, c.ID)
+	assert.Equal(t, "臺北市立中山國中", c.Name)
+	assert.Equal(t, []string{"台北市立中山國民中學", "臺北市立中山國中", "北市中山"}, c.Aliases)
+
+	// 已記錄的寫法不再寫入
+	saved := store.saved
+	_, err = n.Resolve(ctx, "北市中山")
+	require.NoError(t, err)
+	assert.Equal(t, saved, store.saved)
+
+	other, err := n.Resolve(ctx, "高雄市立中山國中")
+	require.NoError(t, err)
+	assert.NotEqual(t, a.ID, other.ID)
+	assert.Len(t, store.teams, 2)
+}
+
+func TestNewNormalizer(t *testing.T) {
+	_, err := NewNormalizer(nil, []Alias{{Names: []string{"A"}}})
+	require.Error(t, err)
+	_, err = NewNormalizer(nil, []Alias{
+		{Name: "A國小", Names: []string{"A"}},
+		{Name: "B國小", Names: []string{"A"}},
+	})
+	require.Error(t, err)
+}